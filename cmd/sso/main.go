@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	"github.com/Krokozabra213/schools_backend/services/sso/app"
 	ssoconfig "github.com/Krokozabra213/schools_backend/services/sso/config"
 )
 
@@ -21,24 +26,42 @@ func run() error {
 	flag.StringVar(&configPath, "config", "configs/sso.yaml", "path to configuration file")
 	flag.Parse()
 
-	// // Config
+	// Config
 	cfg, err := ssoconfig.Init(configPath)
 	if err != nil {
 		return err
 	}
 
 	// Logger
-	log := logger.Init(cfg.App.Environment).WithService("sso", "v0.1.0")
-	log.Info("initialized config", "config", cfg.LogValue())
-	log.Info("starting application")
+	log := logger.Init(cfg.App.Environment,
+		logger.WithRedactKeys(cfg.App.LogRedactKeys...),
+		logger.WithSampling(cfg.App.LogSampleInterval, cfg.App.LogSampleBurst),
+		logger.WithAsync(cfg.App.LogBufferSize),
+	).WithService(app.Name, app.Version)
+	defer log.Close()
+	if cfg.App.LogLevel != "" {
+		log.SetLevel(cfg.App.Level())
+	}
 
-	log.Info(configPath)
+	// SIGINT/SIGTERM отменяют ctx и запускают graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// sso migrate up|down|status|redo|version
+	if args := flag.Args(); len(args) > 0 {
+		if args[0] != "migrate" {
+			return fmt.Errorf("unknown command %q", args[0])
+		}
+		return runMigrate(ctx, cfg, log.Logger, args[1:])
+	}
 
-	// Database
-	log.Info("connected to postgres")
+	log.Info("initialized config", "config", cfg.LogValue())
+	log.Info("starting application")
 
-	// Cache
-	log.Info("connected to redis")
+	application, err := app.New(ctx, cfg, configPath, log)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return application.Run(ctx)
 }
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	"github.com/Krokozabra213/schools_backend/services/sso/app"
+	ssoconfig "github.com/Krokozabra213/schools_backend/services/sso/config"
+	"github.com/Krokozabra213/schools_backend/services/sso/migrator"
+	"github.com/pressly/goose/v3"
+)
+
+const migrateUsage = "usage: sso [-config path] migrate up|down|status|redo|version"
+
+var migrateCommands = []string{"up", "down", "status", "redo", "version"}
+
+// runMigrate выполняет `sso migrate <command>` на встроенных миграциях,
+// подключаясь к базе по настройкам из ssoconfig.
+func runMigrate(ctx context.Context, cfg *ssoconfig.Config, log *slog.Logger, args []string) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+	// до подключения: опечатка не должна ждать таймаута базы
+	if !slices.Contains(migrateCommands, args[0]) {
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], migrateUsage)
+	}
+
+	db, err := app.NewPostgres(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	mig, err := migrator.New(db.Pool(), logger.Component(log, "migrator"))
+	if err != nil {
+		return err
+	}
+	defer mig.Close()
+
+	switch args[0] {
+	case "up":
+		results, err := mig.Up(ctx)
+		printResults(results...)
+		return err
+
+	case "down":
+		result, err := mig.Down(ctx)
+		if errors.Is(err, migrator.ErrNoMigrations) {
+			fmt.Println("no migrations to roll back")
+			return nil
+		}
+		printResults(result)
+		return err
+
+	case "redo":
+		results, err := mig.Redo(ctx)
+		printResults(results...)
+		return err
+
+	case "status":
+		status, err := mig.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printStatus(status)
+		return nil
+
+	case "version":
+		current, target, err := mig.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("current: %d\nlatest:  %d\n", current, target)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], migrateUsage)
+	}
+}
+
+func printResults(results ...*goose.MigrationResult) {
+	applied := 0
+	for _, r := range results {
+		if r == nil || r.Empty {
+			continue
+		}
+		applied++
+		fmt.Printf("%-4s %d %s (%s)\n", r.Direction, r.Source.Version, r.Source.Path, r.Duration.Round(time.Millisecond))
+	}
+	if applied == 0 {
+		fmt.Println("no migrations to apply")
+	}
+}
+
+func printStatus(status []*goose.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tSOURCE")
+	for _, s := range status {
+		appliedAt := "-"
+		if s.State == goose.StateApplied {
+			appliedAt = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Source.Version, s.State, appliedAt, s.Source.Path)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	ssoconfig "github.com/Krokozabra213/schools_backend/services/sso/config"
+)
+
+// Неверные аргументы отклоняются до подключения к базе: конфигурация
+// пустая, и попытка подключиться закончилась бы другой ошибкой.
+func TestRunMigrateArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "no command", args: nil, wantErr: "usage:"},
+		{name: "extra args", args: []string{"up", "1"}, wantErr: "usage:"},
+		{name: "unknown command", args: []string{"upp"}, wantErr: `unknown migrate command "upp"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runMigrate(context.Background(), &ssoconfig.Config{}, nil, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/secrets"
+	ssoconfig "github.com/Krokozabra213/schools_backend/services/sso/config"
+	"github.com/spf13/cobra"
+)
+
+// keystoreOptions — путь к хранилищу; парольная фраза берётся из
+// SSO_KEYSTORE_PASSPHRASE(_FILE), как и в сервисе.
+type keystoreOptions struct {
+	path string
+}
+
+func (o *keystoreOptions) passphrase(ctx context.Context) (secrets.Secret, error) {
+	passphrase, err := secrets.NewEnvProvider("").Secret(ctx, ssoconfig.SecretKeystorePassphrase)
+	if err != nil {
+		return secrets.Secret{}, fmt.Errorf("keystore passphrase: %w", err)
+	}
+	return passphrase, nil
+}
+
+func (o *keystoreOptions) open(ctx context.Context) (*secrets.Keystore, error) {
+	passphrase, err := o.passphrase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return secrets.OpenKeystore(o.path, passphrase)
+}
+
+func newKeystoreCmd(root *rootOptions) *cobra.Command {
+	opts := &keystoreOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "keystore",
+		Short: "Manage the encrypted secrets keystore",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := cmd.Root().PersistentPreRunE(cmd, args); err != nil {
+				return err
+			}
+			if opts.path == "" {
+				return errors.New("keystore path is required: --keystore or SSO_KEYSTORE_PATH")
+			}
+			return nil
+		},
+	}
+	cmd.PersistentFlags().StringVar(&opts.path, "keystore", os.Getenv("SSO_KEYSTORE_PATH"), "keystore file")
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "init",
+			Short: "Create an empty keystore",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				passphrase, err := opts.passphrase(cmd.Context())
+				if err != nil {
+					return err
+				}
+				ks, err := secrets.NewKeystore(opts.path, passphrase)
+				if err != nil {
+					return err
+				}
+				return ks.Save()
+			},
+		},
+		&cobra.Command{
+			Use:   "set <name>",
+			Short: "Store a secret read from stdin (multi-line values such as PEM keys are kept)",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ks, err := opts.open(cmd.Context())
+				if err != nil {
+					return err
+				}
+
+				value, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("read secret: %w", err)
+				}
+				defer clear(value)
+
+				value = bytes.TrimRight(value, "\r\n")
+				if len(value) == 0 {
+					return errors.New("secret must not be empty")
+				}
+
+				if err := ks.Set(args[0], secrets.New(value)); err != nil {
+					return err
+				}
+				return ks.Save()
+			},
+		},
+		&cobra.Command{
+			Use:   "delete <name>",
+			Short: "Remove a secret",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ks, err := opts.open(cmd.Context())
+				if err != nil {
+					return err
+				}
+				if !ks.Delete(args[0]) {
+					return fmt.Errorf("secret %q not found", args[0])
+				}
+				return ks.Save()
+			},
+		},
+		&cobra.Command{
+			Use:   "list",
+			Short: "List secret names (values are never printed)",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				ks, err := opts.open(cmd.Context())
+				if err != nil {
+					return err
+				}
+
+				out, err := newPrinter(root.output, cmd.OutOrStdout())
+				if err != nil {
+					return err
+				}
+				return out.names(ks.Names())
+			},
+		},
+	)
+
+	return cmd
+}
@@ -0,0 +1,27 @@
+// Command ssoctl — административная утилита для данных SSO: создание
+// пользователей, сброс паролей, блокировки, роли и отзыв сессий.
+// Читает ту же конфигурацию, что и сервис, поэтому работает в любом
+// окружении без ручной сборки DSN:
+//
+//	ssoctl -c configs/sso.yaml list-users -o json
+//	ssoctl lock 42
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := newRootCmd(&rootOptions{connect: connectStorage}).ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		stop()
+		os.Exit(1)
+	}
+}
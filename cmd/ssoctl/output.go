@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+func newPrinter(format string, w io.Writer) (*printer, error) {
+	switch format {
+	case outputTable, outputJSON:
+		return &printer{format: format, w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, want %s or %s", format, outputTable, outputJSON)
+	}
+}
+
+// userView — представление пользователя в выводе. Пароль не выводится никогда.
+type userView struct {
+	ID        int64      `json:"id"`
+	Username  string     `json:"username"`
+	Email     string     `json:"email"`
+	Name      string     `json:"name"`
+	Surname   string     `json:"surname"`
+	IsMale    bool       `json:"is_male"`
+	Role      string     `json:"role"`
+	LockedAt  *time.Time `json:"locked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func newUserView(u domain.User) userView {
+	return userView{
+		ID:        u.ID,
+		Username:  u.Username,
+		Email:     u.Email,
+		Name:      u.Name,
+		Surname:   u.Surname,
+		IsMale:    u.IsMale,
+		Role:      string(u.Role),
+		LockedAt:  u.LockedAt,
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+func (p *printer) users(users []domain.User) error {
+	views := make([]userView, 0, len(users))
+	for _, u := range users {
+		views = append(views, newUserView(u))
+	}
+
+	if p.format == outputJSON {
+		return p.json(views)
+	}
+
+	rows := make([][]string, 0, len(views))
+	for _, v := range views {
+		locked := "-"
+		if v.LockedAt != nil {
+			locked = v.LockedAt.Format(time.RFC3339)
+		}
+		rows = append(rows, []string{
+			fmt.Sprint(v.ID), v.Username, v.Email, v.Name + " " + v.Surname,
+			v.Role, locked, v.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return p.table([]string{"ID", "USERNAME", "EMAIL", "NAME", "ROLE", "LOCKED AT", "CREATED AT"}, rows)
+}
+
+func (p *printer) names(names []string) error {
+	if p.format == outputJSON {
+		return p.json(names)
+	}
+
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{name})
+	}
+	return p.table([]string{"NAME"}, rows)
+}
+
+// result печатает итог команды: JSON-объектом или строками "key: value".
+// Порядок ключей задаёт keys.
+func (p *printer) result(keys []string, values map[string]any) error {
+	if p.format == outputJSON {
+		return p.json(values)
+	}
+
+	rows := make([][]string, 0, len(keys))
+	for _, k := range keys {
+		rows = append(rows, []string{strings.ToUpper(k), fmt.Sprint(values[k])})
+	}
+	return p.table(nil, rows)
+}
+
+func (p *printer) json(v any) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (p *printer) table(header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	if header != nil {
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
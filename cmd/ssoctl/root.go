@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	pgxclient "github.com/Krokozabra213/schools_backend/internal/pkg/pgx-client"
+	"github.com/Krokozabra213/schools_backend/services/sso/app"
+	"github.com/Krokozabra213/schools_backend/services/sso/business"
+	ssoconfig "github.com/Krokozabra213/schools_backend/services/sso/config"
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+	"github.com/Krokozabra213/schools_backend/services/sso/repository/postgres"
+	"github.com/spf13/cobra"
+)
+
+type rootOptions struct {
+	configPath string
+	output     string
+	timeout    time.Duration
+	verbose    bool
+
+	// connect подключает команду к данным; в тестах подменяется
+	connect func(ctx context.Context, configPath string) (userAdmin, func(), error)
+}
+
+func newRootCmd(opts *rootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "ssoctl",
+		Short:         "Administrative tasks against SSO data",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			if _, err := newPrinter(opts.output, cmd.OutOrStdout()); err != nil {
+				return err
+			}
+
+			// логи идут в stderr, чтобы не смешиваться с выводом команд
+			level := slog.LevelWarn
+			if opts.verbose {
+				level = slog.LevelDebug
+			}
+			slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+			return nil
+		},
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVarP(&opts.configPath, "config", "c", "configs/sso.yaml", "path to configuration file")
+	flags.StringVarP(&opts.output, "output", "o", outputTable, "output format: table or json")
+	flags.DurationVar(&opts.timeout, "timeout", 30*time.Second, "timeout for the whole command")
+	flags.BoolVarP(&opts.verbose, "verbose", "v", false, "log business operations to stderr")
+
+	cmd.AddCommand(
+		newCreateUserCmd(opts),
+		newResetPasswordCmd(opts),
+		newLockCmd(opts),
+		newUnlockCmd(opts),
+		newAssignRoleCmd(opts),
+		newRevokeSessionsCmd(opts),
+		newListUsersCmd(opts),
+		newCountUsersCmd(opts),
+		newKeystoreCmd(opts),
+	)
+
+	return cmd
+}
+
+// userAdmin — операции, которые вызывают команды (business.Business).
+type userAdmin interface {
+	CreateUser(ctx context.Context, user *domain.CreateUser) (*domain.CreateUserRow, error)
+	ResetPassword(ctx context.Context, userID int64, newPassword string) error
+	LockUser(ctx context.Context, userID int64) error
+	UnlockUser(ctx context.Context, userID int64) error
+	AssignRole(ctx context.Context, userID int64, role domain.Role) error
+	RevokeUserTokens(ctx context.Context, userID int64) error
+	ListUsers(ctx context.Context, limit, offset int) ([]domain.User, error)
+	CountUsers(ctx context.Context) (int64, error)
+}
+
+// env — зависимости одной команды.
+type env struct {
+	business userAdmin
+	out      *printer
+}
+
+// run подключается к хранилищам, выполняет fn и освобождает ресурсы.
+func (o *rootOptions) run(cmd *cobra.Command, fn func(ctx context.Context, e *env) error) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), o.timeout)
+	defer cancel()
+
+	out, err := newPrinter(o.output, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	b, closeStorage, err := o.connect(ctx, o.configPath)
+	if err != nil {
+		return err
+	}
+	defer closeStorage()
+
+	if err := fn(ctx, &env{business: b, out: out}); err != nil {
+		// изменение уже сохранено: повторять нужно только отзыв сессий
+		if errors.Is(err, business.ErrTokensNotRevoked) {
+			return fmt.Errorf("%s: %w, retry with revoke-sessions", cmd.Name(), err)
+		}
+		return fmt.Errorf("%s: %w", cmd.Name(), err)
+	}
+	return nil
+}
+
+// connectStorage собирает бизнес-слой из ssoconfig так же, как сервис, но
+// без серверов, метрик и трассировки.
+func connectStorage(ctx context.Context, configPath string) (_ userAdmin, closeStorage func(), err error) {
+	cfg, err := ssoconfig.Init(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := app.NewPostgres(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if err != nil {
+			db.Close()
+		}
+	}()
+
+	rdb, err := app.NewRedis(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if err != nil {
+			rdb.Close()
+		}
+	}()
+
+	// через общий кеш: инвалидации дойдут до работающих реплик
+	cache, err := app.NewUserCache(cfg, rdb)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b := business.New(cfg, slog.Default(),
+		pgxclient.NewTxManager(db),
+		postgres.NewRepository(db),
+		cache,
+		cache,
+		nil,
+	)
+
+	return b, func() {
+		rdb.Close()
+		db.Close()
+	}, nil
+}
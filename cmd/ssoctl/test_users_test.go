@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Krokozabra213/schools_backend/services/sso/business"
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+)
+
+// fakeAdmin записывает вызовы команд; err возвращают все методы.
+type fakeAdmin struct {
+	calls   []string
+	created []domain.CreateUser
+	err     error
+}
+
+func (f *fakeAdmin) call(format string, args ...any) error {
+	f.calls = append(f.calls, fmt.Sprintf(format, args...))
+	return f.err
+}
+
+func (f *fakeAdmin) CreateUser(_ context.Context, user *domain.CreateUser) (*domain.CreateUserRow, error) {
+	f.created = append(f.created, *user)
+	if err := f.call("CreateUser(%s)", user.Username); err != nil {
+		return nil, err
+	}
+	return &domain.CreateUserRow{ID: 7}, nil
+}
+
+func (f *fakeAdmin) ResetPassword(_ context.Context, userID int64, newPassword string) error {
+	return f.call("ResetPassword(%d, %s)", userID, newPassword)
+}
+
+func (f *fakeAdmin) LockUser(_ context.Context, userID int64) error {
+	return f.call("LockUser(%d)", userID)
+}
+
+func (f *fakeAdmin) UnlockUser(_ context.Context, userID int64) error {
+	return f.call("UnlockUser(%d)", userID)
+}
+
+func (f *fakeAdmin) AssignRole(_ context.Context, userID int64, role domain.Role) error {
+	return f.call("AssignRole(%d, %s)", userID, role)
+}
+
+func (f *fakeAdmin) RevokeUserTokens(_ context.Context, userID int64) error {
+	return f.call("RevokeUserTokens(%d)", userID)
+}
+
+func (f *fakeAdmin) ListUsers(_ context.Context, limit, offset int) ([]domain.User, error) {
+	if err := f.call("ListUsers(%d, %d)", limit, offset); err != nil {
+		return nil, err
+	}
+	return []domain.User{{ID: 1, Username: "ivan", Password: "hash", Role: domain.RoleStudent}}, nil
+}
+
+func (f *fakeAdmin) CountUsers(context.Context) (int64, error) {
+	return 3, f.call("CountUsers()")
+}
+
+// execute запускает ssoctl с fake вместо хранилищ и возвращает stdout.
+func execute(t *testing.T, fake *fakeAdmin, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	var out bytes.Buffer
+	cmd := newRootCmd(&rootOptions{
+		connect: func(context.Context, string) (userAdmin, func(), error) {
+			return fake, func() {}, nil
+		},
+	})
+	cmd.SetArgs(args)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+
+	err := cmd.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func TestUserCommands(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		stdin     string
+		err       error
+		wantCalls []string
+		wantErr   string
+	}{
+		{
+			name:      "reset password from stdin",
+			args:      []string{"reset-password", "42", "--password-stdin"},
+			stdin:     "s3cret\n",
+			wantCalls: []string{"ResetPassword(42, s3cret)"},
+		},
+		{
+			name:    "reset password empty stdin",
+			args:    []string{"reset-password", "42", "--password-stdin"},
+			wantErr: "password must not be empty",
+		},
+		{
+			name:      "lock",
+			args:      []string{"lock", "42"},
+			wantCalls: []string{"LockUser(42)"},
+		},
+		{
+			name:      "unlock",
+			args:      []string{"unlock", "42"},
+			wantCalls: []string{"UnlockUser(42)"},
+		},
+		{
+			name:    "invalid user id",
+			args:    []string{"lock", "0"},
+			wantErr: `invalid user id "0"`,
+		},
+		{
+			name:      "assign role",
+			args:      []string{"assign-role", "42", "teacher"},
+			wantCalls: []string{"AssignRole(42, teacher)"},
+		},
+		{
+			name:    "assign unknown role",
+			args:    []string{"assign-role", "42", "janitor"},
+			wantErr: "janitor",
+		},
+		{
+			name:      "tokens not revoked",
+			args:      []string{"lock", "42"},
+			err:       business.ErrTokensNotRevoked,
+			wantCalls: []string{"LockUser(42)"},
+			wantErr:   "retry with revoke-sessions",
+		},
+		{
+			name:      "list users pagination",
+			args:      []string{"list-users", "--limit", "10", "--offset", "20"},
+			wantCalls: []string{"ListUsers(10, 20)"},
+		},
+		{
+			name:    "unknown output",
+			args:    []string{"count-users", "-o", "xml"},
+			wantErr: "unknown output format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeAdmin{err: tt.err}
+
+			_, err := execute(t, fake, tt.stdin, tt.args...)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+			if got := strings.Join(fake.calls, "; "); got != strings.Join(tt.wantCalls, "; ") {
+				t.Errorf("got calls %q, want %q", fake.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCreateUserCommand(t *testing.T) {
+	args := []string{
+		"create-user", "-o", "json",
+		"--username", "ivan", "--email", "ivan@example.com",
+		"--name", "Ivan", "--surname", "Petrov",
+		"--password-stdin",
+	}
+
+	t.Run("role is created with the user", func(t *testing.T) {
+		fake := &fakeAdmin{}
+
+		out, err := execute(t, fake, "s3cret\n", append(args, "--role", "teacher")...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// роль уходит в CreateUser, а не отдельным AssignRole после него
+		if len(fake.calls) != 1 || len(fake.created) != 1 {
+			t.Fatalf("got calls %q, want a single CreateUser", fake.calls)
+		}
+		if got := fake.created[0]; got.Role != domain.RoleTeacher || got.Password != "s3cret" {
+			t.Errorf("got role %q and password %q, want %q and %q", got.Role, got.Password, domain.RoleTeacher, "s3cret")
+		}
+
+		var result map[string]any
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("output is not json: %v\n%s", err, out)
+		}
+		if result["id"] != float64(7) || result["role"] != string(domain.RoleTeacher) {
+			t.Errorf("got output %v, want id 7 and role %q", result, domain.RoleTeacher)
+		}
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		fake := &fakeAdmin{}
+
+		if _, err := execute(t, fake, "s3cret\n", append(args, "--role", "janitor")...); err == nil {
+			t.Error("got nil error, want unknown role")
+		}
+		if len(fake.calls) != 0 {
+			t.Errorf("got calls %q, want none", fake.calls)
+		}
+	})
+}
+
+func TestListUsersHidesPassword(t *testing.T) {
+	for _, format := range []string{outputTable, outputJSON} {
+		out, err := execute(t, &fakeAdmin{}, "", "list-users", "-o", format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out, "ivan") || strings.Contains(out, "hash") {
+			t.Errorf("%s: got output %q, want user without password", format, out)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+	"github.com/spf13/cobra"
+)
+
+// passwordFlags — пароль из флага или из stdin. Флаг попадает в историю
+// shell и в список процессов, поэтому для скриптов есть --password-stdin.
+type passwordFlags struct {
+	password string
+	stdin    bool
+}
+
+func (f *passwordFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.password, "password", "", "new password (prefer --password-stdin)")
+	cmd.Flags().BoolVar(&f.stdin, "password-stdin", false, "read the password from the first line of stdin")
+	cmd.MarkFlagsMutuallyExclusive("password", "password-stdin")
+	cmd.MarkFlagsOneRequired("password", "password-stdin")
+}
+
+func (f *passwordFlags) read(r io.Reader) (string, error) {
+	password := f.password
+	if f.stdin {
+		line, err := bufio.NewReader(r).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("read password: %w", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+	if password == "" {
+		return "", errors.New("password must not be empty")
+	}
+	return password, nil
+}
+
+func parseUserID(arg string) (int64, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid user id %q", arg)
+	}
+	return id, nil
+}
+
+func newCreateUserCmd(opts *rootOptions) *cobra.Command {
+	var (
+		user     domain.CreateUser
+		role     string
+		password passwordFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create-user",
+		Short: "Create a user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			r, err := domain.ParseRole(role)
+			if err != nil {
+				return err
+			}
+			if user.Password, err = password.read(cmd.InOrStdin()); err != nil {
+				return err
+			}
+			user.Role = r
+
+			return opts.run(cmd, func(ctx context.Context, e *env) error {
+				row, err := e.business.CreateUser(ctx, &user)
+				if err != nil {
+					return err
+				}
+
+				return e.out.result([]string{"id", "username", "role", "created_at"}, map[string]any{
+					"id":         row.ID,
+					"username":   user.Username,
+					"role":       r,
+					"created_at": row.CreatedAt,
+				})
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&user.Username, "username", "", "unique username")
+	flags.StringVar(&user.Email, "email", "", "unique email")
+	flags.StringVar(&user.Name, "name", "", "first name")
+	flags.StringVar(&user.Surname, "surname", "", "last name")
+	flags.BoolVar(&user.IsMale, "male", false, "user is male")
+	flags.StringVar(&role, "role", string(domain.RoleStudent), fmt.Sprintf("role, one of %v", domain.Roles))
+	for _, name := range []string{"username", "email", "name", "surname"} {
+		_ = cmd.MarkFlagRequired(name)
+	}
+	password.register(cmd)
+
+	return cmd
+}
+
+func newResetPasswordCmd(opts *rootOptions) *cobra.Command {
+	var password passwordFlags
+
+	cmd := &cobra.Command{
+		Use:   "reset-password <user-id>",
+		Short: "Set a new password and revoke all sessions of the user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseUserID(args[0])
+			if err != nil {
+				return err
+			}
+			newPassword, err := password.read(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			return opts.run(cmd, func(ctx context.Context, e *env) error {
+				if err := e.business.ResetPassword(ctx, id, newPassword); err != nil {
+					return err
+				}
+				return e.out.result([]string{"user_id", "status"}, map[string]any{
+					"user_id": id,
+					"status":  "password reset",
+				})
+			})
+		},
+	}
+	password.register(cmd)
+
+	return cmd
+}
+
+// newUserActionCmd описывает команду вида `<name> <user-id>` без флагов.
+func newUserActionCmd(opts *rootOptions, use, short, status string, action func(ctx context.Context, e *env, id int64) error) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <user-id>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseUserID(args[0])
+			if err != nil {
+				return err
+			}
+
+			return opts.run(cmd, func(ctx context.Context, e *env) error {
+				if err := action(ctx, e, id); err != nil {
+					return err
+				}
+				return e.out.result([]string{"user_id", "status"}, map[string]any{
+					"user_id": id,
+					"status":  status,
+				})
+			})
+		},
+	}
+}
+
+func newLockCmd(opts *rootOptions) *cobra.Command {
+	return newUserActionCmd(opts, "lock", "Lock the user and revoke all sessions", "locked",
+		func(ctx context.Context, e *env, id int64) error {
+			return e.business.LockUser(ctx, id)
+		})
+}
+
+func newUnlockCmd(opts *rootOptions) *cobra.Command {
+	return newUserActionCmd(opts, "unlock", "Unlock the user", "unlocked",
+		func(ctx context.Context, e *env, id int64) error {
+			return e.business.UnlockUser(ctx, id)
+		})
+}
+
+func newRevokeSessionsCmd(opts *rootOptions) *cobra.Command {
+	return newUserActionCmd(opts, "revoke-sessions", "Revoke all access and refresh tokens of the user", "sessions revoked",
+		func(ctx context.Context, e *env, id int64) error {
+			return e.business.RevokeUserTokens(ctx, id)
+		})
+}
+
+func newAssignRoleCmd(opts *rootOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "assign-role <user-id> <role>",
+		Short: fmt.Sprintf("Change the role of the user, one of %v", domain.Roles),
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseUserID(args[0])
+			if err != nil {
+				return err
+			}
+			role, err := domain.ParseRole(args[1])
+			if err != nil {
+				return err
+			}
+
+			return opts.run(cmd, func(ctx context.Context, e *env) error {
+				if err := e.business.AssignRole(ctx, id, role); err != nil {
+					return err
+				}
+				return e.out.result([]string{"user_id", "role"}, map[string]any{
+					"user_id": id,
+					"role":    role,
+				})
+			})
+		},
+	}
+}
+
+func newListUsersCmd(opts *rootOptions) *cobra.Command {
+	var limit, offset int
+
+	cmd := &cobra.Command{
+		Use:   "list-users",
+		Short: "List active users ordered by id",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return opts.run(cmd, func(ctx context.Context, e *env) error {
+				users, err := e.business.ListUsers(ctx, limit, offset)
+				if err != nil {
+					return err
+				}
+				return e.out.users(users)
+			})
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 50, "page size")
+	cmd.Flags().IntVar(&offset, "offset", 0, "number of users to skip")
+
+	return cmd
+}
+
+func newCountUsersCmd(opts *rootOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "count-users",
+		Short: "Count active users",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return opts.run(cmd, func(ctx context.Context, e *env) error {
+				count, err := e.business.CountUsers(ctx)
+				if err != nil {
+					return err
+				}
+				return e.out.result([]string{"count"}, map[string]any{"count": count})
+			})
+		},
+	}
+}
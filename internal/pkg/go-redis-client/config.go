@@ -2,7 +2,12 @@ package gorediscli
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/resilience"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -16,8 +21,18 @@ const (
 	defaultMaxConnLifetime = 2 * time.Hour
 	defaultMaxConnIdleTime = 15 * time.Minute
 	defaultPingTimeout     = 3 * time.Second
+	defaultTLSMode         = "disable"
 )
 
+// tlsModes — режимы TLS, имена как у sslmode libpq. allow и prefer
+// нет: Redis не договаривается о TLS, порт либо с ним, либо без.
+var tlsModes = map[string]bool{
+	"disable":     true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
 type config struct {
 	addr            string
 	password        *string
@@ -30,6 +45,21 @@ type config struct {
 	maxConnLifetime time.Duration
 	maxConnIdleTime time.Duration
 	pingTimeout     time.Duration
+	tracerProvider  trace.TracerProvider
+
+	tlsMode       string
+	tlsRootCert   string
+	tlsCert       string
+	tlsKey        string
+	tlsServerName string
+
+	useResilience bool
+	resilience    []resilience.Option
+
+	masterName       string
+	sentinelAddrs    []string
+	sentinelPassword string
+	clusterAddrs     []string
 }
 
 func defaultConfig() config {
@@ -44,13 +74,32 @@ func defaultConfig() config {
 		maxConnLifetime: defaultMaxConnLifetime,
 		maxConnIdleTime: defaultMaxConnIdleTime,
 		pingTimeout:     defaultPingTimeout,
+		tlsMode:         defaultTLSMode,
 	}
 }
 
 func (c config) valid() error {
-	if c.addr == "" {
+	sentinel := c.masterName != "" || len(c.sentinelAddrs) > 0
+	cluster := len(c.clusterAddrs) > 0
+
+	switch {
+	case sentinel && cluster:
+		return errors.New("sentinel and cluster are mutually exclusive")
+	case sentinel && c.masterName == "":
+		return errors.New("sentinel master name is required")
+	case sentinel && len(c.sentinelAddrs) == 0:
+		return errors.New("sentinel addrs are required")
+	case cluster && c.db != 0:
+		// в кластере есть только база 0
+		return errors.New("db must be 0 in cluster mode")
+	case !sentinel && !cluster && c.addr == "":
 		return errors.New("addr is required")
 	}
+	for _, addr := range slices.Concat(c.sentinelAddrs, c.clusterAddrs) {
+		if addr == "" {
+			return errors.New("empty node addr")
+		}
+	}
 	if c.password == nil {
 		return errors.New("password is required")
 	}
@@ -66,6 +115,22 @@ func (c config) valid() error {
 	if c.minIdleConns > c.poolSize {
 		return errors.New("minIdleConns must be <= poolSize")
 	}
+	if !tlsModes[c.tlsMode] {
+		return fmt.Errorf("unknown tls mode: %s", c.tlsMode)
+	}
 
 	return nil
 }
+
+// endpoint — имя подключения для логов и автомата: адрес, мастер Sentinel
+// или первый из seed-узлов кластера.
+func (c config) endpoint() string {
+	switch {
+	case c.masterName != "":
+		return "sentinel " + c.masterName
+	case len(c.clusterAddrs) > 0:
+		return "cluster " + c.clusterAddrs[0]
+	default:
+		return c.addr
+	}
+}
@@ -0,0 +1,22 @@
+package gorediscli
+
+import "strings"
+
+// hashTagEscaper кодирует фигурные скобки (и сам %, чтобы кодирование
+// оставалось однозначным): иначе "}" из значения закроет тег раньше.
+var hashTagEscaper = strings.NewReplacer("%", "%25", "{", "%7B", "}", "%7D")
+
+// HashTag оборачивает s в hash tag "{s}". В Redis Cluster слот ключа
+// считается только по тегу, поэтому ключи с одинаковым тегом попадают в
+// один слот и доступны Lua-скриптам, MULTI и командам с несколькими
+// ключами. Скобки внутри s кодируются, так что тег нельзя подменить
+// пользовательским значением. Пустая s даёт "{_}": пустой тег "{}"
+// Redis игнорирует и считает слот по всему ключу.
+func HashTag(s string) string {
+	if s == "" {
+		s = "_"
+	} else {
+		s = hashTagEscaper.Replace(s)
+	}
+	return "{" + s + "}"
+}
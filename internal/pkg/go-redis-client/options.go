@@ -1,6 +1,11 @@
 package gorediscli
 
-import "time"
+import (
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/resilience"
+	"go.opentelemetry.io/otel/trace"
+)
 
 type Option func(*config)
 
@@ -69,3 +74,79 @@ func WithPingTimeout(timeout time.Duration) Option {
 		c.pingTimeout = timeout
 	}
 }
+
+// WithTracerProvider включает OpenTelemetry спаны на каждую команду.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithTLS включает TLS: "require" (без проверки сертификата), "verify-ca"
+// (цепочка до CA) или "verify-full" (цепочка и имя хоста); "disable" — без TLS.
+func WithTLS(mode string) Option {
+	return func(c *config) {
+		c.tlsMode = mode
+	}
+}
+
+// WithTLSRootCert задаёт CA bundle (PEM) вместо системных корневых
+// сертификатов. С ним require проверяет цепочку, как verify-ca.
+func WithTLSRootCert(path string) Option {
+	return func(c *config) {
+		c.tlsRootCert = path
+	}
+}
+
+// WithTLSCert задаёт клиентский сертификат и ключ (PEM) для mTLS.
+func WithTLSCert(certPath, keyPath string) Option {
+	return func(c *config) {
+		c.tlsCert = certPath
+		c.tlsKey = keyPath
+	}
+}
+
+// WithTLSServerName задаёт имя для SNI и проверки verify-full вместо хоста из addr.
+func WithTLSServerName(name string) Option {
+	return func(c *config) {
+		c.tlsServerName = name
+	}
+}
+
+// WithResilience включает повторы при временных сбоях и автомат вместо
+// встроенных повторов go-redis. После обрыва соединения повторяются
+// только команды чтения и помеченные resilience.ContextWithIdempotent.
+func WithResilience(opts ...resilience.Option) Option {
+	return func(c *config) {
+		c.useResilience = true
+		c.resilience = append(c.resilience, opts...)
+	}
+}
+
+// WithSentinel подключает к мастеру masterName через Redis Sentinel: адрес
+// мастера запрашивается у sentinel-узлов addrs и обновляется после failover.
+// WithAddr при этом не используется.
+func WithSentinel(masterName string, addrs ...string) Option {
+	return func(c *config) {
+		c.masterName = masterName
+		c.sentinelAddrs = addrs
+	}
+}
+
+// WithSentinelPassword задаёт пароль sentinel-узлов, если он отличается
+// от пароля мастера или задан только у них.
+func WithSentinelPassword(password string) Option {
+	return func(c *config) {
+		c.sentinelPassword = password
+	}
+}
+
+// WithCluster подключает к Redis Cluster; addrs — seed-узлы, остальные
+// узлы и слоты клиент узнаёт сам. Команды с несколькими ключами работают,
+// только если ключи в одном слоте (см. HashTag). WithAddr не используется,
+// база — только 0.
+func WithCluster(addrs ...string) Option {
+	return func(c *config) {
+		c.clusterAddrs = addrs
+	}
+}
@@ -2,13 +2,21 @@ package gorediscli
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
+	"slices"
 
+	"github.com/Krokozabra213/schools_backend/internal/pkg/resilience"
+	tlsconfig "github.com/Krokozabra213/schools_backend/internal/pkg/tls-config"
 	"github.com/redis/go-redis/v9"
 )
 
+// Client — клиент одного узла, Sentinel или Cluster в зависимости от
+// опций; все три реализуют redis.UniversalClient.
 type Client struct {
-	*redis.Client
+	redis.UniversalClient
+	policy *resilience.Policy
 }
 
 func New(ctx context.Context, opts ...Option) (*Client, error) {
@@ -22,8 +30,50 @@ func New(ctx context.Context, opts ...Option) (*Client, error) {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     cfg.addr,
+	tlsCfg, err := newTLSConfig(&cfg)
+	if err != nil {
+		return nil, fmt.Errorf("configure TLS: %w", err)
+	}
+
+	policy, err := newPolicy(&cfg)
+	if err != nil {
+		return nil, fmt.Errorf("resilience: %w", err)
+	}
+
+	maxRetries := 0 // по умолчанию go-redis
+	if policy != nil {
+		maxRetries = -1
+	}
+
+	rdb := redis.NewUniversalClient(universalOptions(&cfg, tlsCfg, maxRetries))
+
+	if cfg.tracerProvider != nil {
+		rdb.AddHook(newTracingHook(cfg.tracerProvider))
+	}
+	// после трассировки: один спан на команду вместе с повторами
+	if policy != nil {
+		rdb.AddHook(resilienceHook{policy: policy})
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, cfg.pingTimeout)
+	defer cancel()
+
+	if err := rdb.Ping(pingCtx).Err(); err != nil {
+		_ = rdb.Close()
+		return nil, fmt.Errorf("ping redis: %w", err)
+	}
+
+	return &Client{
+		UniversalClient: rdb,
+		policy:          policy,
+	}, nil
+}
+
+// universalOptions выбирает режим: Sentinel при заданном мастере, Cluster
+// при seed-узлах, иначе один узел по addr.
+func universalOptions(cfg *config, tlsCfg *tls.Config, maxRetries int) *redis.UniversalOptions {
+	opts := &redis.UniversalOptions{
+		Addrs:    []string{cfg.addr},
 		Password: *cfg.password,
 		DB:       cfg.db,
 
@@ -36,17 +86,78 @@ func New(ctx context.Context, opts ...Option) (*Client, error) {
 
 		ConnMaxLifetime: cfg.maxConnLifetime,
 		ConnMaxIdleTime: cfg.maxConnIdleTime,
-	})
 
-	pingCtx, cancel := context.WithTimeout(ctx, cfg.pingTimeout)
-	defer cancel()
+		TLSConfig: tlsCfg,
 
-	if err := rdb.Ping(pingCtx).Err(); err != nil {
-		_ = rdb.Close()
-		return nil, fmt.Errorf("ping redis: %w", err)
+		MaxRetries: maxRetries,
 	}
 
-	return &Client{
-		Client: rdb,
-	}, nil
+	switch {
+	case cfg.masterName != "":
+		opts.MasterName = cfg.masterName
+		opts.Addrs = cfg.sentinelAddrs
+		opts.SentinelPassword = cfg.sentinelPassword
+	case len(cfg.clusterAddrs) > 0:
+		// и с одним seed-узлом: это может быть configuration endpoint
+		opts.Addrs = cfg.clusterAddrs
+		opts.IsClusterMode = true
+	}
+	return opts
+}
+
+// Resilience возвращает политику или nil без WithResilience.
+func (c *Client) Resilience() *resilience.Policy {
+	return c.policy
+}
+
+// newTLSConfig собирает TLS по tlsMode; для "disable" возвращает nil.
+func newTLSConfig(cfg *config) (*tls.Config, error) {
+	verify := tlsconfig.VerifyNone
+	switch cfg.tlsMode {
+	case "disable":
+		return nil, nil
+	case "require":
+		// как в libpq: с заданным CA require проверяет цепочку
+		if cfg.tlsRootCert != "" {
+			verify = tlsconfig.VerifyCA
+		}
+	case "verify-ca":
+		verify = tlsconfig.VerifyCA
+	case "verify-full":
+		verify = tlsconfig.VerifyFull
+	default:
+		return nil, fmt.Errorf("unknown tls mode: %s", cfg.tlsMode)
+	}
+
+	multiNode := cfg.masterName != "" || len(cfg.clusterAddrs) > 0
+
+	serverName := cfg.tlsServerName
+	if serverName == "" {
+		addr := cfg.addr
+		if multiNode {
+			addr = slices.Concat(cfg.sentinelAddrs, cfg.clusterAddrs)[0]
+		}
+		serverName = addr
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			serverName = host
+		}
+	}
+
+	tlsCfg, err := tlsconfig.Config{
+		Verify:     verify,
+		CAFile:     cfg.tlsRootCert,
+		CertFile:   cfg.tlsCert,
+		KeyFile:    cfg.tlsKey,
+		ServerName: serverName,
+	}.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	// в Sentinel и Cluster узлов несколько и мастер меняется: без явного
+	// имени tls.DialWithDialer берёт его из адреса каждого подключения
+	if multiNode && cfg.tlsServerName == "" {
+		tlsCfg.ServerName = ""
+	}
+	return tlsCfg, nil
 }
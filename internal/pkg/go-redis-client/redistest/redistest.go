@@ -0,0 +1,157 @@
+// Package redistest запускает Redis в testcontainers для интеграционных
+// тестов: один узел, мастер под Sentinel и кластер из одного узла.
+//
+// Sentinel и Cluster сообщают клиенту адреса узлов, поэтому узел слушает
+// внутри контейнера тот же порт, что проброшен на хост, и объявляет себя
+// как 127.0.0.1 — так объявленный адрес доступен и из теста.
+package redistest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	image   = "redis:7-alpine"
+	startup = 60 * time.Second
+
+	// Password — пароль всех узлов и sentinel.
+	Password = "testpass"
+	// MasterName — имя мастера в Sentinel.
+	MasterName = "mymaster"
+)
+
+// Start запускает один узел и возвращает его адрес.
+func Start(t *testing.T) string {
+	t.Helper()
+
+	c := run(t, testcontainers.ContainerRequest{
+		Image:        image,
+		ExposedPorts: []string{"6379/tcp"},
+		Cmd:          []string{"redis-server", "--requirepass", Password},
+		WaitingFor:   wait.ForLog("Ready to accept connections").WithStartupTimeout(startup),
+	})
+	port, err := c.MappedPort(context.Background(), "6379/tcp")
+	return endpoint(t, c, port.Port(), err)
+}
+
+// StartSentinel запускает мастер и sentinel в одном контейнере и
+// возвращает адрес sentinel. Мастер объявлен как 127.0.0.1:порт.
+func StartSentinel(t *testing.T) string {
+	t.Helper()
+
+	port := freePort(t)
+	script := strings.Join([]string{
+		fmt.Sprintf("redis-server --port %d --requirepass %s --masterauth %s --daemonize yes", port, Password, Password),
+		fmt.Sprintf("printf 'port 26379\\nsentinel monitor %s 127.0.0.1 %d 1\\nsentinel auth-pass %s %s\\nrequirepass %s\\n' > /tmp/sentinel.conf",
+			MasterName, port, MasterName, Password, Password),
+		"exec redis-sentinel /tmp/sentinel.conf",
+	}, " && ")
+
+	c := run(t, testcontainers.ContainerRequest{
+		Image:        image,
+		ExposedPorts: []string{fmt.Sprintf("%d:%d/tcp", port, port), "26379/tcp"},
+		Cmd:          []string{"sh", "-c", script},
+		WaitingFor:   wait.ForLog("+monitor master").WithStartupTimeout(startup),
+	})
+	sentinelPort, err := c.MappedPort(context.Background(), "26379/tcp")
+	return endpoint(t, c, sentinelPort.Port(), err)
+}
+
+// StartCluster запускает кластер из одного узла со всеми слотами и
+// возвращает адрес seed-узла. Проверка CROSSSLOT в нём такая же, как в
+// кластере из многих узлов.
+func StartCluster(t *testing.T) string {
+	t.Helper()
+
+	port := freePort(t)
+	cli := []string{"redis-cli", "-p", strconv.Itoa(port), "-a", Password, "--no-auth-warning"}
+
+	c := run(t, testcontainers.ContainerRequest{
+		Image:        image,
+		ExposedPorts: []string{fmt.Sprintf("%d:%d/tcp", port, port)},
+		Cmd: []string{"redis-server",
+			"--port", strconv.Itoa(port),
+			"--requirepass", Password,
+			"--cluster-enabled", "yes",
+			"--cluster-announce-ip", "127.0.0.1",
+		},
+		WaitingFor: wait.ForLog("Ready to accept connections").WithStartupTimeout(startup),
+	})
+
+	ctx := context.Background()
+	code, out, err := c.Exec(ctx, append(cli, "CLUSTER", "ADDSLOTSRANGE", "0", "16383"))
+	if err != nil || code != 0 {
+		t.Fatalf("cluster addslots: code %d, err %v, output %s", code, err, readAll(out))
+	}
+
+	err = wait.ForExec(append(cli, "CLUSTER", "INFO")).
+		WithResponseMatcher(func(body io.Reader) bool {
+			return strings.Contains(readAll(body), "cluster_state:ok")
+		}).
+		WithStartupTimeout(startup).
+		WaitUntilReady(ctx, c)
+	if err != nil {
+		t.Fatalf("wait cluster state: %v", err)
+	}
+
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+}
+
+func run(t *testing.T, req testcontainers.ContainerRequest) testcontainers.Container {
+	t.Helper()
+
+	c, err := testcontainers.GenericContainer(context.Background(), testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	testcontainers.CleanupContainer(t, c)
+	if err != nil {
+		t.Fatalf("start redis: %v", err)
+	}
+	return c
+}
+
+// endpoint собирает адрес из хоста контейнера и проброшенного порта.
+func endpoint(t *testing.T, c testcontainers.Container, port string, portErr error) string {
+	t.Helper()
+
+	if portErr != nil {
+		t.Fatalf("mapped port: %v", portErr)
+	}
+	host, err := c.Host(context.Background())
+	if err != nil {
+		t.Fatalf("container host: %v", err)
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// freePort возвращает свободный порт хоста. Между проверкой и запуском
+// контейнера его может занять кто-то ещё — для тестов это приемлемо.
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("find free port: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func readAll(r io.Reader) string {
+	if r == nil {
+		return ""
+	}
+	b, _ := io.ReadAll(r)
+	return string(b)
+}
@@ -0,0 +1,118 @@
+package gorediscli
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/resilience"
+	"github.com/redis/go-redis/v9"
+)
+
+// retryablePrefixes — ответы, при которых команда не выполнялась: сервер
+// загружает данные, реплика только для чтения после failover, слот
+// мигрирует или кластер/мастер недоступен.
+var retryablePrefixes = []string{"LOADING", "READONLY", "TRYAGAIN", "MASTERDOWN", "CLUSTERDOWN"}
+
+// readOnlyCommands — команды, которые можно повторить после обрыва
+// соединения: повтор не меняет ни данные, ни ответ.
+var readOnlyCommands = map[string]bool{
+	"ping": true, "get": true, "mget": true, "getrange": true, "strlen": true,
+	"exists": true, "ttl": true, "pttl": true, "type": true,
+	"hget": true, "hmget": true, "hgetall": true, "hexists": true, "hlen": true,
+	"smembers": true, "sismember": true, "scard": true,
+	"zscore": true, "zrange": true, "zcard": true,
+	"lrange": true, "llen": true, "scan": true,
+}
+
+// IsRetryable — resilience.Classifier для Redis: ответы из
+// retryablePrefixes повторяются всегда, обрыв соединения — только для
+// идемпотентных команд. redis.Nil (ключа нет) — не ошибка.
+func IsRetryable(err error, idempotent bool) bool {
+	if err == nil || errors.Is(err, redis.Nil) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var redisErr redis.Error
+	if errors.As(err, &redisErr) {
+		for _, prefix := range retryablePrefixes {
+			if strings.HasPrefix(redisErr.Error(), prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return idempotent && resilience.IsTransient(err)
+}
+
+// isFailure — отказ для автомата: сервер недоступен или не принимает команды.
+func isFailure(err error) bool {
+	return IsRetryable(err, true)
+}
+
+func newPolicy(cfg *config) (*resilience.Policy, error) {
+	if !cfg.useResilience {
+		return nil, nil
+	}
+
+	opts := []resilience.Option{
+		resilience.WithClassifier(IsRetryable),
+		resilience.WithFailureClassifier(isFailure),
+	}
+	opts = append(opts, cfg.resilience...)
+	opts = append(opts, resilience.WithName("redis "+cfg.endpoint()))
+
+	return resilience.New(opts...)
+}
+
+func isIdempotent(ctx context.Context, cmds ...redis.Cmder) bool {
+	if resilience.IsIdempotent(ctx) {
+		return true
+	}
+	for _, cmd := range cmds {
+		if !readOnlyCommands[strings.ToLower(cmd.Name())] {
+			return false
+		}
+	}
+	return true
+}
+
+// resilienceHook проводит команды и пайплайны через политику. Встроенные
+// повторы go-redis при этом отключены: они повторяют и INCR после обрыва.
+type resilienceHook struct {
+	policy *resilience.Policy
+}
+
+var _ redis.Hook = resilienceHook{}
+
+func (h resilienceHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h resilienceHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		return h.policy.Do(ctx, isIdempotent(ctx, cmd), func(ctx context.Context) error {
+			return next(ctx, cmd)
+		})
+	}
+}
+
+func (h resilienceHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := h.policy.Do(ctx, isIdempotent(ctx, cmds...), func(ctx context.Context) error {
+			return next(ctx, cmds)
+		})
+		// при разомкнутом автомате пайплайн не выполнялся и ошибки
+		// в командах не проставлены
+		if errors.Is(err, resilience.ErrCircuitOpen) {
+			for _, cmd := range cmds {
+				if cmd.Err() == nil {
+					cmd.SetErr(err)
+				}
+			}
+		}
+		return err
+	}
+}
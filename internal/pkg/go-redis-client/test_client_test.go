@@ -2,8 +2,11 @@ package gorediscli
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 func TestNewFailsWithoutPassword(t *testing.T) {
@@ -68,3 +71,62 @@ func TestNewFailsOnUnreachableRedis(t *testing.T) {
 		t.Error("expected error when redis is unreachable")
 	}
 }
+
+func TestUniversalOptions(t *testing.T) {
+	password := "secret"
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    string
+		addrs   []string
+		cluster bool
+	}{
+		{
+			name:  "single node",
+			opts:  []Option{WithAddr("redis:6379")},
+			want:  "*redis.Client",
+			addrs: []string{"redis:6379"},
+		},
+		{
+			name:  "sentinel",
+			opts:  []Option{WithSentinel("mymaster", "s1:26379", "s2:26379"), WithSentinelPassword("sp")},
+			want:  "*redis.Client",
+			addrs: []string{"s1:26379", "s2:26379"},
+		},
+		{
+			name:    "cluster with one seed",
+			opts:    []Option{WithCluster("node-1:6379")},
+			want:    "*redis.ClusterClient",
+			addrs:   []string{"node-1:6379"},
+			cluster: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.password = &password
+			for _, opt := range tt.opts {
+				opt(&cfg)
+			}
+
+			opts := universalOptions(&cfg, nil, -1)
+			if fmt.Sprint(opts.Addrs) != fmt.Sprint(tt.addrs) {
+				t.Errorf("got addrs %v, want %v", opts.Addrs, tt.addrs)
+			}
+			if opts.IsClusterMode != tt.cluster {
+				t.Errorf("got cluster mode %v, want %v", opts.IsClusterMode, tt.cluster)
+			}
+			if opts.MaxRetries != -1 {
+				t.Errorf("got max retries %d, want -1", opts.MaxRetries)
+			}
+
+			rdb := redis.NewUniversalClient(opts)
+			defer rdb.Close()
+			if got := fmt.Sprintf("%T", rdb); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
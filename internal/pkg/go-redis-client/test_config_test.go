@@ -24,6 +24,7 @@ func TestDefaultConfig(t *testing.T) {
 		{"maxConnLifetime", cfg.maxConnLifetime, 2 * time.Hour},
 		{"maxConnIdleTime", cfg.maxConnIdleTime, 15 * time.Minute},
 		{"pingTimeout", cfg.pingTimeout, 3 * time.Second},
+		{"tlsMode", cfg.tlsMode, "disable"},
 	}
 
 	for _, tt := range tests {
@@ -97,6 +98,62 @@ func TestConfigValid(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:    "verify-full tls mode",
+			modify:  func(c *config) { c.tlsMode = "verify-full" },
+			wantErr: false,
+		},
+		{
+			name:    "unknown tls mode",
+			modify:  func(c *config) { c.tlsMode = "prefer" },
+			wantErr: true,
+		},
+		{
+			name: "sentinel",
+			modify: func(c *config) {
+				c.addr = ""
+				c.masterName = "mymaster"
+				c.sentinelAddrs = []string{"sentinel-1:26379", "sentinel-2:26379"}
+			},
+			wantErr: false,
+		},
+		{
+			name:    "sentinel without addrs",
+			modify:  func(c *config) { c.masterName = "mymaster" },
+			wantErr: true,
+		},
+		{
+			name:    "sentinel without master",
+			modify:  func(c *config) { c.sentinelAddrs = []string{"sentinel-1:26379"} },
+			wantErr: true,
+		},
+		{
+			name:    "cluster",
+			modify:  func(c *config) { c.clusterAddrs = []string{"node-1:6379"} },
+			wantErr: false,
+		},
+		{
+			name: "cluster with db",
+			modify: func(c *config) {
+				c.clusterAddrs = []string{"node-1:6379"}
+				c.db = 1
+			},
+			wantErr: true,
+		},
+		{
+			name:    "cluster with empty addr",
+			modify:  func(c *config) { c.clusterAddrs = []string{"node-1:6379", ""} },
+			wantErr: true,
+		},
+		{
+			name: "sentinel and cluster",
+			modify: func(c *config) {
+				c.masterName = "mymaster"
+				c.sentinelAddrs = []string{"sentinel-1:26379"}
+				c.clusterAddrs = []string{"node-1:6379"}
+			},
+			wantErr: true,
+		},
 		{
 			name: "minIdleConns equals poolSize",
 			modify: func(c *config) {
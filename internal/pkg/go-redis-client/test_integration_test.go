@@ -4,10 +4,12 @@ package gorediscli_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	gorediscli "github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client/redistest"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
@@ -114,3 +116,70 @@ func TestWrongPasswordIntegration(t *testing.T) {
 		t.Error("expected error with wrong password")
 	}
 }
+
+func TestSentinelIntegration(t *testing.T) {
+	ctx := context.Background()
+	sentinel := redistest.StartSentinel(t)
+
+	client, err := gorediscli.New(ctx,
+		gorediscli.WithSentinel(redistest.MasterName, sentinel),
+		gorediscli.WithSentinelPassword(redistest.Password),
+		gorediscli.WithPassword(redistest.Password),
+		gorediscli.WithResilience(),
+	)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Set(ctx, "testkey", "testvalue", 10*time.Second).Err(); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	val, err := client.Get(ctx, "testkey").Result()
+	if err != nil || val != "testvalue" {
+		t.Errorf("got (%q, %v), want (%q, nil)", val, err, "testvalue")
+	}
+}
+
+func TestClusterIntegration(t *testing.T) {
+	ctx := context.Background()
+	seed := redistest.StartCluster(t)
+
+	client, err := gorediscli.New(ctx,
+		gorediscli.WithCluster(seed),
+		gorediscli.WithPassword(redistest.Password),
+		gorediscli.WithResilience(),
+	)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	defer client.Close()
+
+	tag := gorediscli.HashTag("user:42")
+	if err := client.MSet(ctx, "a:"+tag, "1", "b:"+tag, "2").Err(); err != nil {
+		t.Fatalf("MSet with one hash tag: %v", err)
+	}
+	vals, err := client.MGet(ctx, "a:"+tag, "b:"+tag).Result()
+	if err != nil || len(vals) != 2 || vals[0] != "1" || vals[1] != "2" {
+		t.Errorf("got (%v, %v), want ([1 2], nil)", vals, err)
+	}
+
+	// без общего тега ключи в разных слотах
+	err = client.MSet(ctx, "a:user:42", "1", "b:user:42", "2").Err()
+	if err == nil || !strings.HasPrefix(err.Error(), "CROSSSLOT") {
+		t.Errorf("got %v, want CROSSSLOT", err)
+	}
+
+	pubsub := client.Subscribe(ctx, "test:channel")
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+	if err := client.Publish(ctx, "test:channel", "hello").Err(); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	msg, err := pubsub.ReceiveMessage(ctx)
+	if err != nil || msg.Payload != "hello" {
+		t.Errorf("got (%v, %v), want hello", msg, err)
+	}
+}
@@ -0,0 +1,25 @@
+package gorediscli
+
+import "testing"
+
+func TestHashTag(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "user:42", "{user:42}"},
+		{"empty", "", "{_}"},
+		{"closing brace", "a}b", "{a%7Db}"},
+		{"opening brace", "{a}", "{%7Ba%7D}"},
+		{"percent kept unambiguous", "%7D", "{%257D}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HashTag(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,6 +20,13 @@ func TestOptions(t *testing.T) {
 		WithMaxConnLifetime(1 * time.Hour),
 		WithMaxConnIdleTime(30 * time.Minute),
 		WithPingTimeout(2 * time.Second),
+		WithTLS("verify-full"),
+		WithTLSRootCert("/etc/ssl/redis/ca.crt"),
+		WithTLSCert("/etc/ssl/redis/client.crt", "/etc/ssl/redis/client.key"),
+		WithTLSServerName("redis.internal"),
+		WithSentinel("mymaster", "sentinel-1:26379", "sentinel-2:26379"),
+		WithSentinelPassword("sentinelsecret"),
+		WithCluster("node-1:6379"),
 	}
 
 	for _, opt := range opts {
@@ -42,6 +49,15 @@ func TestOptions(t *testing.T) {
 		{"maxConnLifetime", cfg.maxConnLifetime, 1 * time.Hour},
 		{"maxConnIdleTime", cfg.maxConnIdleTime, 30 * time.Minute},
 		{"pingTimeout", cfg.pingTimeout, 2 * time.Second},
+		{"tlsMode", cfg.tlsMode, "verify-full"},
+		{"tlsRootCert", cfg.tlsRootCert, "/etc/ssl/redis/ca.crt"},
+		{"tlsCert", cfg.tlsCert, "/etc/ssl/redis/client.crt"},
+		{"tlsKey", cfg.tlsKey, "/etc/ssl/redis/client.key"},
+		{"tlsServerName", cfg.tlsServerName, "redis.internal"},
+		{"masterName", cfg.masterName, "mymaster"},
+		{"sentinelAddrs", len(cfg.sentinelAddrs), 2},
+		{"sentinelPassword", cfg.sentinelPassword, "sentinelsecret"},
+		{"clusterAddrs", len(cfg.clusterAddrs), 1},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,132 @@
+package gorediscli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/resilience"
+	"github.com/redis/go-redis/v9"
+)
+
+// replyErr — ответ-ошибка сервера, как proto.RedisError.
+type replyErr string
+
+func (e replyErr) Error() string { return string(e) }
+func (replyErr) RedisError()     {}
+
+func testPolicy(t *testing.T, opts ...resilience.Option) *resilience.Policy {
+	t.Helper()
+
+	cfg := defaultConfig()
+	WithResilience(append([]resilience.Option{resilience.WithBackoff(0, 0)}, opts...)...)(&cfg)
+
+	p, err := newPolicy(&cfg)
+	if err != nil {
+		t.Fatalf("newPolicy() error: %v", err)
+	}
+	return p
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		want         bool
+		wantNonIdemp bool
+	}{
+		{"loading", replyErr("LOADING Redis is loading the dataset in memory"), true, true},
+		{"readonly", replyErr("READONLY You can't write against a read only replica."), true, true},
+		{"tryagain", replyErr("TRYAGAIN Multiple keys request during rehashing of slot"), true, true},
+		{"wrong type", replyErr("WRONGTYPE Operation against a key holding the wrong kind of value"), false, false},
+		{"nil", redis.Nil, false, false},
+		{"unexpected EOF", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true, false},
+		{"context canceled", context.Canceled, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err, true); got != tt.want {
+				t.Errorf("idempotent: got %v, want %v", got, tt.want)
+			}
+			if got := IsRetryable(tt.err, false); got != tt.wantNonIdemp {
+				t.Errorf("non-idempotent: got %v, want %v", got, tt.wantNonIdemp)
+			}
+		})
+	}
+}
+
+func TestResilienceHook(t *testing.T) {
+	ctx := context.Background()
+	eof := fmt.Errorf("read: %w", io.ErrUnexpectedEOF)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		cmd       redis.Cmder
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{"get retried after EOF", ctx, redis.NewStringCmd(ctx, "get", "k"), []error{eof}, 2, false},
+		{"incr not retried after EOF", ctx, redis.NewIntCmd(ctx, "incr", "k"), []error{eof}, 1, true},
+		{"incr retried when marked idempotent", resilience.ContextWithIdempotent(ctx), redis.NewIntCmd(ctx, "incr", "k"), []error{eof}, 2, false},
+		{"set retried on LOADING", ctx, redis.NewStatusCmd(ctx, "set", "k", "v"), []error{replyErr("LOADING")}, 2, false},
+		{"nil not retried", ctx, redis.NewStringCmd(ctx, "get", "k"), []error{redis.Nil}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			process := resilienceHook{policy: testPolicy(t)}.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+
+			err := process(tt.ctx, tt.cmd)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestResilienceHookPipeline(t *testing.T) {
+	ctx := context.Background()
+	eof := fmt.Errorf("read: %w", io.ErrUnexpectedEOF)
+	hook := resilienceHook{policy: testPolicy(t, resilience.WithBreaker(1, time.Hour))}
+
+	calls := 0
+	process := hook.ProcessPipelineHook(func(ctx context.Context, cmds []redis.Cmder) error {
+		calls++
+		return eof
+	})
+
+	// запись в пайплайне после обрыва не повторяется, отказ размыкает автомат
+	write := []redis.Cmder{redis.NewStatusCmd(ctx, "set", "k", "v"), redis.NewIntCmd(ctx, "expire", "k", 10)}
+	if err := process(ctx, write); !errors.Is(err, eof) || calls != 1 {
+		t.Fatalf("got error %v after %d calls, want EOF after 1", err, calls)
+	}
+
+	read := []redis.Cmder{redis.NewStringCmd(ctx, "get", "k"), redis.NewIntCmd(ctx, "ttl", "k")}
+	if err := process(ctx, read); !errors.Is(err, resilience.ErrCircuitOpen) {
+		t.Fatalf("got error %v, want ErrCircuitOpen", err)
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1: open breaker must not reach redis", calls)
+	}
+	for _, cmd := range read {
+		if !errors.Is(cmd.Err(), resilience.ErrCircuitOpen) {
+			t.Errorf("%s: got error %v, want ErrCircuitOpen", cmd.Name(), cmd.Err())
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package gorediscli
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/tls-config/tlstest"
+)
+
+// handshakeOK — ошибка фейкового сервера: до неё клиент доходит
+// только после успешного TLS рукопожатия.
+const handshakeOK = "tls handshake ok"
+
+// fakeTLSServer принимает TLS и отвечает ошибкой handshakeOK на любую команду.
+func fakeTLSServer(t *testing.T, tlsCfg *tls.Config) string {
+	t.Helper()
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", tlsCfg)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveTLS(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func serveTLS(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// команда RESP начинается с "*<n>": на каждую отвечаем ошибкой,
+	// остальные строки (длины и аргументы) пропускаем
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		if strings.HasPrefix(line, "*") {
+			if _, err := conn.Write([]byte("-ERR " + handshakeOK + "\r\n")); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestTLSHandshake(t *testing.T) {
+	ca := tlstest.NewCA(t, "test CA")
+	other := tlstest.NewCA(t, "other CA")
+
+	caFile := ca.CAFile(t)
+	otherFile := other.CAFile(t)
+	certFile, keyFile := ca.IssueFiles(t, "client")
+
+	addr := fakeTLSServer(t, ca.ServerConfig(t, nil, "127.0.0.1"))
+	mtlsAddr := fakeTLSServer(t, ca.ServerConfig(t, ca.Pool, "127.0.0.1"))
+
+	tests := []struct {
+		name    string
+		addr    string
+		opts    []Option
+		wantErr bool
+	}{
+		{
+			name: "verify-full",
+			addr: addr,
+			opts: []Option{WithTLS("verify-full"), WithTLSRootCert(caFile)},
+		},
+		{
+			name:    "verify-full wrong server name",
+			addr:    addr,
+			opts:    []Option{WithTLS("verify-full"), WithTLSRootCert(caFile), WithTLSServerName("redis.internal")},
+			wantErr: true,
+		},
+		{
+			name:    "verify-full unknown CA",
+			addr:    addr,
+			opts:    []Option{WithTLS("verify-full"), WithTLSRootCert(otherFile)},
+			wantErr: true,
+		},
+		{
+			name: "verify-ca ignores server name",
+			addr: addr,
+			opts: []Option{WithTLS("verify-ca"), WithTLSRootCert(caFile), WithTLSServerName("redis.internal")},
+		},
+		{
+			name: "require skips verification",
+			addr: addr,
+			opts: []Option{WithTLS("require")},
+		},
+		{
+			name:    "require with root cert verifies chain",
+			addr:    addr,
+			opts:    []Option{WithTLS("require"), WithTLSRootCert(otherFile)},
+			wantErr: true,
+		},
+		{
+			name:    "plain client to tls server",
+			addr:    addr,
+			wantErr: true,
+		},
+		{
+			name: "mtls",
+			addr: mtlsAddr,
+			opts: []Option{WithTLS("verify-full"), WithTLSRootCert(caFile), WithTLSCert(certFile, keyFile)},
+		},
+		{
+			name:    "mtls without client cert",
+			addr:    mtlsAddr,
+			opts:    []Option{WithTLS("verify-full"), WithTLSRootCert(caFile)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{
+				WithAddr(tt.addr),
+				WithPassword("test"),
+				WithMinIdleConns(0),
+				WithPingTimeout(2 * time.Second),
+				WithDialTimeout(time.Second),
+				WithReadTimeout(time.Second),
+			}, tt.opts...)
+
+			_, err := New(context.Background(), opts...)
+			if err == nil {
+				t.Fatal("fake server must reject commands")
+			}
+
+			ok := strings.Contains(err.Error(), handshakeOK)
+			if ok == tt.wantErr {
+				t.Errorf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	ca := tlstest.NewCA(t, "test CA")
+	caFile := ca.CAFile(t)
+
+	tests := []struct {
+		name           string
+		modify         func(*config)
+		wantNil        bool
+		wantServerName string
+		wantErr        bool
+	}{
+		{
+			name:    "disable",
+			modify:  func(c *config) {},
+			wantNil: true,
+		},
+		{
+			name:           "server name from addr",
+			modify:         func(c *config) { c.addr = "redis.internal:6380"; c.tlsMode = "verify-full" },
+			wantServerName: "redis.internal",
+		},
+		{
+			name: "explicit server name",
+			modify: func(c *config) {
+				c.tlsMode = "verify-full"
+				c.tlsServerName = "cache.internal"
+			},
+			wantServerName: "cache.internal",
+		},
+		{
+			// имя берётся из адреса каждого узла при подключении
+			name: "cluster without server name",
+			modify: func(c *config) {
+				c.clusterAddrs = []string{"node-1:6379", "node-2:6379"}
+				c.tlsMode = "verify-full"
+			},
+			wantServerName: "",
+		},
+		{
+			name: "sentinel with explicit server name",
+			modify: func(c *config) {
+				c.masterName = "mymaster"
+				c.sentinelAddrs = []string{"sentinel-1:26379"}
+				c.tlsMode = "verify-full"
+				c.tlsServerName = "redis.internal"
+			},
+			wantServerName: "redis.internal",
+		},
+		{
+			name:    "missing CA file",
+			modify:  func(c *config) { c.tlsMode = "verify-ca"; c.tlsRootCert = caFile + ".missing" },
+			wantErr: true,
+		},
+		{
+			name:    "cert without key",
+			modify:  func(c *config) { c.tlsMode = "require"; c.tlsCert = caFile },
+			wantErr: true,
+		},
+		{
+			name:    "unknown mode",
+			modify:  func(c *config) { c.tlsMode = "prefer" },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			tt.modify(&cfg)
+
+			tlsCfg, err := newTLSConfig(&cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newTLSConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if (tlsCfg == nil) != tt.wantNil {
+				t.Fatalf("got nil TLS config %v, want %v", tlsCfg == nil, tt.wantNil)
+			}
+			if tlsCfg != nil && tlsCfg.ServerName != tt.wantServerName {
+				t.Errorf("got ServerName %q, want %q", tlsCfg.ServerName, tt.wantServerName)
+			}
+		})
+	}
+}
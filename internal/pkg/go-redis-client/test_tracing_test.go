@@ -0,0 +1,50 @@
+package gorediscli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingHook(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	hook := newTracingHook(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	tests := []struct {
+		name       string
+		cmd        redis.Cmder
+		err        error
+		wantSpan   string
+		wantStatus codes.Code
+	}{
+		{"ok", redis.NewStringCmd(context.Background(), "get", "k"), nil, "redis GET", codes.Unset},
+		{"nil is not an error", redis.NewStringCmd(context.Background(), "get", "k"), redis.Nil, "redis GET", codes.Unset},
+		{"error", redis.NewStatusCmd(context.Background(), "set", "k", "v"), errors.New("connection refused"), "redis SET", codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			process := hook.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+				return tt.err
+			})
+
+			if err := process(context.Background(), tt.cmd); !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-1]
+			if span.Name() != tt.wantSpan {
+				t.Errorf("got span %q, want %q", span.Name(), tt.wantSpan)
+			}
+			if span.Status().Code != tt.wantStatus {
+				t.Errorf("got status %v, want %v", span.Status().Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package gorediscli
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client"
+
+// tracingHook создаёт клиентский спан на каждую команду и пайплайн.
+// Аргументы команд не записываются: в ключах и значениях бывают токены.
+type tracingHook struct {
+	tracer trace.Tracer
+}
+
+var _ redis.Hook = (*tracingHook)(nil)
+
+func newTracingHook(tp trace.TracerProvider) *tracingHook {
+	return &tracingHook{
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (h *tracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *tracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		name := strings.ToUpper(cmd.Name())
+
+		ctx, span := h.tracer.Start(ctx, "redis "+name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemNameRedis,
+				semconv.DBOperationName(name),
+			),
+		)
+		defer span.End()
+
+		err := next(ctx, cmd)
+		recordError(span, err)
+		return err
+	}
+}
+
+func (h *tracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := h.tracer.Start(ctx, "redis pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemNameRedis,
+				semconv.DBOperationName("PIPELINE"),
+				semconv.DBOperationBatchSize(len(cmds)),
+			),
+		)
+		defer span.End()
+
+		err := next(ctx, cmds)
+		recordError(span, err)
+		return err
+	}
+}
+
+// recordError помечает спан ошибкой; redis.Nil (ключ не найден) ошибкой не считается.
+func recordError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		span.SetAttributes(semconv.ErrorTypeKey.String("timeout"))
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,38 @@
+package health
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultTimeout  = 2 * time.Second
+	defaultCacheTTL = 5 * time.Second
+)
+
+type config struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+	now      func() time.Time
+}
+
+func defaultConfig() config {
+	return config{
+		timeout:  defaultTimeout,
+		cacheTTL: defaultCacheTTL,
+		now:      time.Now,
+	}
+}
+
+func (c config) valid() error {
+	if c.timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+	if c.cacheTTL < 0 {
+		return errors.New("cache ttl must be >= 0")
+	}
+	if c.now == nil {
+		return errors.New("clock is required")
+	}
+	return nil
+}
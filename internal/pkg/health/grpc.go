@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ServiceLiveness — имя сервиса в HealthCheckRequest для проверки живости.
+	// Пустое имя (как у grpc_health_probe по умолчанию) означает readiness.
+	ServiceLiveness = "liveness"
+	// ServiceReadiness — явное имя для проверки готовности.
+	ServiceReadiness = "readiness"
+
+	minWatchInterval = time.Second
+)
+
+// GRPCServer реализует стандартный grpc.health.v1.Health поверх тех же проверок.
+type GRPCServer struct {
+	healthpb.UnimplementedHealthServer
+	health *Health
+}
+
+var _ healthpb.HealthServer = (*GRPCServer)(nil)
+
+// GRPCServer возвращает сервис для healthpb.RegisterHealthServer.
+func (h *Health) GRPCServer() *GRPCServer {
+	return &GRPCServer{health: h}
+}
+
+func (s *GRPCServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	report, err := s.report(ctx, req.GetService())
+	if err != nil {
+		return nil, err
+	}
+	return &healthpb.HealthCheckResponse{Status: servingStatus(report)}, nil
+}
+
+// Watch отправляет статус сразу и затем при каждом изменении.
+func (s *GRPCServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+
+	if _, err := s.report(ctx, req.GetService()); err != nil {
+		return err
+	}
+
+	interval := max(s.health.cfg.cacheTTL, minWatchInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		report, err := s.report(ctx, req.GetService())
+		if err != nil {
+			return err
+		}
+
+		if st := servingStatus(report); st != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *GRPCServer) report(ctx context.Context, service string) (Report, error) {
+	switch service {
+	case "", ServiceReadiness:
+		return s.health.Readiness(ctx), nil
+	case ServiceLiveness:
+		return s.health.Liveness(ctx), nil
+	default:
+		return Report{}, status.Errorf(codes.NotFound, "unknown service %q", service)
+	}
+}
+
+func servingStatus(r Report) healthpb.HealthCheckResponse_ServingStatus {
+	if r.Up() {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,213 @@
+// Package health runs liveness and readiness checks for orchestrator probes.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// CheckFunc проверяет одну зависимость; nil — зависимость доступна.
+type CheckFunc func(ctx context.Context) error
+
+// Pinger — зависимость с методом Ping (pgxclient.Client и т.п.).
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingCheck проверяет зависимость через Ping.
+func PingCheck(p Pinger) CheckFunc {
+	return p.Ping
+}
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// CheckResult — результат одной проверки.
+type CheckResult struct {
+	Status    Status        `json:"status"`
+	Error     string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// Report — сводный результат набора проверок.
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+func (r Report) Up() bool {
+	return r.Status == StatusUp
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+
+	mu      sync.Mutex
+	result  CheckResult
+	expires time.Time
+}
+
+// Health хранит проверки живости и готовности.
+//
+// Liveness отвечает на вопрос «нужно ли перезапустить процесс» и не должна
+// зависеть от внешних систем, иначе падение БД перезапустит все реплики.
+// Readiness — «можно ли слать трафик»: БД, Redis, ключи, миграции.
+//
+// Результат каждой проверки кешируется на cacheTTL, одновременные пробы
+// ждут одну и ту же проверку, а сама проверка ограничена timeout — так
+// медленная БД не копит висящие запросы от проб.
+type Health struct {
+	cfg config
+
+	mu        sync.RWMutex
+	liveness  []*check
+	readiness []*check
+
+	group        singleflight.Group
+	shuttingDown atomic.Bool
+}
+
+func New(opts ...Option) (*Health, error) {
+	cfg := defaultConfig()
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := cfg.valid(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &Health{cfg: cfg}, nil
+}
+
+// AddLivenessCheck регистрирует проверку живости.
+func (h *Health) AddLivenessCheck(name string, fn CheckFunc) {
+	h.mu.Lock()
+	h.liveness = append(h.liveness, &check{name: name, fn: fn})
+	h.mu.Unlock()
+}
+
+// AddReadinessCheck регистрирует проверку готовности.
+func (h *Health) AddReadinessCheck(name string, fn CheckFunc) {
+	h.mu.Lock()
+	h.readiness = append(h.readiness, &check{name: name, fn: fn})
+	h.mu.Unlock()
+}
+
+// SetShuttingDown переводит readiness в down: балансировщик перестаёт слать
+// новые запросы, пока сервер дорабатывает текущие.
+func (h *Health) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Liveness выполняет проверки живости.
+func (h *Health) Liveness(ctx context.Context) Report {
+	h.mu.RLock()
+	checks := h.liveness
+	h.mu.RUnlock()
+
+	return h.run(ctx, "live:", checks)
+}
+
+// Readiness выполняет проверки готовности.
+func (h *Health) Readiness(ctx context.Context) Report {
+	if h.shuttingDown.Load() {
+		return Report{
+			Status: StatusDown,
+			Checks: map[string]CheckResult{
+				"shutdown": {Status: StatusDown, Error: "server is shutting down", CheckedAt: h.cfg.now()},
+			},
+		}
+	}
+
+	h.mu.RLock()
+	checks := h.readiness
+	h.mu.RUnlock()
+
+	return h.run(ctx, "ready:", checks)
+}
+
+// run выполняет проверки параллельно.
+func (h *Health) run(ctx context.Context, prefix string, checks []*check) Report {
+	report := Report{
+		Status: StatusUp,
+		Checks: make(map[string]CheckResult, len(checks)),
+	}
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.runCheck(ctx, prefix, c)
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+func (h *Health) runCheck(ctx context.Context, prefix string, c *check) CheckResult {
+	c.mu.Lock()
+	if h.cfg.now().Before(c.expires) {
+		result := c.result
+		c.mu.Unlock()
+		return result
+	}
+	c.mu.Unlock()
+
+	// Проверка не привязана к отмене конкретной пробы: её результат
+	// достанется и остальным ожидающим, и кешу.
+	ch := h.group.DoChan(prefix+c.name, func() (any, error) {
+		checkCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.cfg.timeout)
+		defer cancel()
+
+		start := h.cfg.now()
+		err := c.fn(checkCtx)
+
+		result := CheckResult{
+			Status:    StatusUp,
+			Duration:  h.cfg.now().Sub(start),
+			CheckedAt: start,
+		}
+		if err != nil {
+			result.Status = StatusDown
+			result.Error = err.Error()
+		}
+
+		c.mu.Lock()
+		c.result = result
+		c.expires = start.Add(h.cfg.cacheTTL)
+		c.mu.Unlock()
+
+		return result, nil
+	})
+
+	select {
+	case res := <-ch:
+		return res.Val.(CheckResult)
+	case <-ctx.Done():
+		return CheckResult{Status: StatusDown, Error: ctx.Err().Error(), CheckedAt: h.cfg.now()}
+	}
+}
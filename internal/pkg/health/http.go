@@ -0,0 +1,46 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// LivenessHandler обслуживает /healthz.
+func (h *Health) LivenessHandler() http.Handler {
+	return reportHandler(h.Liveness)
+}
+
+// ReadinessHandler обслуживает /readyz.
+func (h *Health) ReadinessHandler() http.Handler {
+	return reportHandler(h.Readiness)
+}
+
+// hiddenError заменяет текст ошибки проверки в HTTP ответе.
+const hiddenError = "check failed"
+
+// reportHandler отвечает 200 или 503 с JSON отчётом по проверкам.
+// Пробы не аутентифицируются, поэтому текст ошибок зависимостей (адреса,
+// имена баз) наружу не отдаётся; он доступен через Liveness и Readiness.
+func reportHandler(run func(ctx context.Context) Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := run(r.Context())
+		for name, result := range report.Checks {
+			if result.Error != "" {
+				result.Error = hiddenError
+				report.Checks[name] = result
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		if report.Up() {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
@@ -0,0 +1,26 @@
+package health
+
+import "time"
+
+type Option func(*config)
+
+// WithTimeout ограничивает время одной проверки.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// WithCacheTTL задаёт, сколько переиспользуется результат проверки.
+// Zero отключает кеш (но не объединение одновременных проверок).
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.cacheTTL = ttl
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(c *config) {
+		c.now = now
+	}
+}
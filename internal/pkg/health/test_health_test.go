@@ -0,0 +1,172 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/testclock"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func newTestHealth(t *testing.T, opts ...Option) (*Health, *testclock.Clock) {
+	t.Helper()
+
+	clock := testclock.New()
+	h, err := New(append([]Option{WithClock(clock.Now)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h, clock
+}
+
+func TestReadinessCachesResult(t *testing.T) {
+	h, clock := newTestHealth(t, WithCacheTTL(5*time.Second))
+
+	var calls atomic.Int32
+	h.AddReadinessCheck("db", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if r := h.Readiness(context.Background()); !r.Up() {
+			t.Fatalf("got %+v, want up", r)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("got %d calls within ttl, want 1", got)
+	}
+
+	clock.Advance(6 * time.Second)
+	h.Readiness(context.Background())
+	if got := calls.Load(); got != 2 {
+		t.Errorf("got %d calls after ttl, want 2", got)
+	}
+}
+
+func TestConcurrentProbesShareCheck(t *testing.T) {
+	h, _ := newTestHealth(t)
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	h.AddReadinessCheck("db", func(ctx context.Context) error {
+		calls.Add(1)
+		<-release
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.Readiness(context.Background())
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("got %d concurrent calls, want 1", got)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	h, err := New(WithTimeout(20 * time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.AddReadinessCheck("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	r := h.Readiness(context.Background())
+	if r.Up() {
+		t.Fatal("got up, want down on timeout")
+	}
+	if got := r.Checks["slow"].Error; got != context.DeadlineExceeded.Error() {
+		t.Errorf("got error %q, want deadline exceeded", got)
+	}
+}
+
+func TestHTTPHandlers(t *testing.T) {
+	h, _ := newTestHealth(t)
+	h.AddLivenessCheck("process", func(ctx context.Context) error { return nil })
+	h.AddReadinessCheck("redis", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		want    int
+	}{
+		{"liveness ignores dependencies", h.LivenessHandler(), http.StatusOK},
+		{"readiness reports failed dependency", h.ReadinessHandler(), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d", rec.Code, tt.want)
+			}
+			if strings.Contains(rec.Body.String(), "connection refused") {
+				t.Errorf("dependency error leaked to probe response: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestShuttingDown(t *testing.T) {
+	h, _ := newTestHealth(t)
+	h.AddReadinessCheck("db", func(ctx context.Context) error { return nil })
+
+	h.SetShuttingDown()
+
+	if r := h.Readiness(context.Background()); r.Up() {
+		t.Error("got ready while shutting down")
+	}
+	if r := h.Liveness(context.Background()); !r.Up() {
+		t.Error("got not alive while shutting down")
+	}
+}
+
+func TestGRPCCheck(t *testing.T) {
+	h, _ := newTestHealth(t)
+	h.AddReadinessCheck("db", func(ctx context.Context) error { return errors.New("down") })
+	srv := h.GRPCServer()
+
+	tests := []struct {
+		service  string
+		want     healthpb.HealthCheckResponse_ServingStatus
+		wantCode codes.Code
+	}{
+		{"", healthpb.HealthCheckResponse_NOT_SERVING, codes.OK},
+		{ServiceLiveness, healthpb.HealthCheckResponse_SERVING, codes.OK},
+		{"sso.Unknown", healthpb.HealthCheckResponse_UNKNOWN, codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		resp, err := srv.Check(context.Background(), &healthpb.HealthCheckRequest{Service: tt.service})
+		if status.Code(err) != tt.wantCode {
+			t.Errorf("service %q: got code %v, want %v", tt.service, status.Code(err), tt.wantCode)
+			continue
+		}
+		if resp.GetStatus() != tt.want {
+			t.Errorf("service %q: got %v, want %v", tt.service, resp.GetStatus(), tt.want)
+		}
+	}
+}
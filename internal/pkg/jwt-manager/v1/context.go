@@ -0,0 +1,26 @@
+package jwtv1
+
+import "context"
+
+type claimsCtxKey struct{}
+
+// ContextWithAccessClaims stores validated access claims in ctx.
+// Used by auth interceptors so downstream code (e.g. rate limiting
+// by user) can read the caller's identity.
+func ContextWithAccessClaims(ctx context.Context, claims *AccessClaims) context.Context {
+	return context.WithValue(ctx, claimsCtxKey{}, claims)
+}
+
+func AccessClaimsFromContext(ctx context.Context) (*AccessClaims, bool) {
+	claims, ok := ctx.Value(claimsCtxKey{}).(*AccessClaims)
+	return claims, ok && claims != nil
+}
+
+// UserIDFromContext returns the authenticated user ID, if any.
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	claims, ok := AccessClaimsFromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	return claims.UserID, true
+}
@@ -32,12 +32,14 @@ type AccessClaims struct {
 	Username string    `json:"username"`
 	Email    string    `json:"email"`
 	Exp      time.Time `json:"exp"`
+	IssuedAt time.Time `json:"iat"`
 }
 
 type RefreshClaims struct {
-	JWTID  string    `json:"jti"`
-	UserID int64     `json:"user_id"`
-	Exp    time.Time `json:"exp"`
+	JWTID    string    `json:"jti"`
+	UserID   int64     `json:"user_id"`
+	Exp      time.Time `json:"exp"`
+	IssuedAt time.Time `json:"iat"`
 }
 
 // при генерации передаются в claims
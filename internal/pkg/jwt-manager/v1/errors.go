@@ -17,4 +17,5 @@ var (
 	ErrTokenInvalid  = errors.New("invalid token")
 	ErrInvalidData   = errors.New("invalid token data")
 	ErrSigningMethod = errors.New("unexpected signing method")
+	ErrTokenRevoked  = errors.New("token revoked")
 )
@@ -0,0 +1,53 @@
+package jwtv1
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/secrets"
+)
+
+// LoadPrivateKey получает PEM ключа из provider по имени и разбирает его.
+func LoadPrivateKey(ctx context.Context, provider secrets.Provider, name string) (*rsa.PrivateKey, error) {
+	key, err := provider.Secret(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("load private key %s: %w", name, err)
+	}
+	return ParsePrivateKey(key)
+}
+
+// ParsePrivateKey разбирает RSA ключ в PEM: PKCS#1 (scripts/gen-private.go)
+// или PKCS#8 (openssl genpkey).
+func ParsePrivateKey(key secrets.Secret) (*rsa.PrivateKey, error) {
+	data := key.Bytes()
+	defer clear(data)
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	defer clear(block.Bytes)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type %T, want RSA", parsed)
+		}
+		return rsaKey, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
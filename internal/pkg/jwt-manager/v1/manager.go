@@ -1,6 +1,7 @@
 package jwtv1
 
 import (
+	"context"
 	"crypto/rsa"
 	"errors"
 	"fmt"
@@ -19,6 +20,7 @@ type Manager struct {
 	privateKey *rsa.PrivateKey
 	accessTTL  time.Duration
 	refreshTTL time.Duration
+	revocation RevocationChecker
 }
 
 type Option func(*Manager)
@@ -35,6 +37,14 @@ func WithRefreshTTL(ttl time.Duration) Option {
 	}
 }
 
+// WithRevocationChecker enables the per-user watermark check
+// in ParseAccessContext and ParseRefreshContext.
+func WithRevocationChecker(checker RevocationChecker) Option {
+	return func(m *Manager) {
+		m.revocation = checker
+	}
+}
+
 func New(private *rsa.PrivateKey, public *rsa.PublicKey, opts ...Option) (*Manager, error) {
 	if private == nil {
 		return nil, errors.New("private key is required")
@@ -140,6 +150,7 @@ func (m *Manager) ParseAccess(tokenString string) (*AccessClaims, error) {
 		Username: claims.Username,
 		Email:    claims.Email,
 		Exp:      claims.ExpiresAt.Time,
+		IssuedAt: numericTime(claims.IssuedAt),
 	}, nil
 }
 
@@ -152,12 +163,42 @@ func (m *Manager) ParseRefresh(tokenString string) (*RefreshClaims, error) {
 	}
 
 	return &RefreshClaims{
-		JWTID:  claims.ID,
-		UserID: claims.UserID,
-		Exp:    claims.ExpiresAt.Time,
+		JWTID:    claims.ID,
+		UserID:   claims.UserID,
+		Exp:      claims.ExpiresAt.Time,
+		IssuedAt: numericTime(claims.IssuedAt),
 	}, nil
 }
 
+// ParseAccessContext parses an access token and rejects it with ErrTokenRevoked
+// if it was issued before the user's watermark.
+func (m *Manager) ParseAccessContext(ctx context.Context, tokenString string) (*AccessClaims, error) {
+	claims, err := m.ParseAccess(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkRevocation(ctx, m.revocation, claims.UserID, claims.IssuedAt); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// ParseRefreshContext is ParseRefresh with the watermark check.
+func (m *Manager) ParseRefreshContext(ctx context.Context, tokenString string) (*RefreshClaims, error) {
+	claims, err := m.ParseRefresh(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkRevocation(ctx, m.revocation, claims.UserID, claims.IssuedAt); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
 func (m *Manager) keyFunc(token *jwt.Token) (interface{}, error) {
 	if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 		return nil, fmt.Errorf("%w: %v", ErrSigningMethod, token.Header["alg"])
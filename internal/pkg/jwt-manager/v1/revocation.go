@@ -0,0 +1,46 @@
+package jwtv1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RevocationChecker returns the moment before which all tokens of the user
+// are considered revoked (password change, ban, role change).
+// Zero time means the user has no watermark.
+//
+// The check runs on every request, so implementations should be cheap,
+// e.g. backed by an in-process cache in front of Redis.
+type RevocationChecker interface {
+	TokensValidAfter(ctx context.Context, userID int64) (time.Time, error)
+}
+
+// checkRevocation rejects tokens issued before the user's watermark.
+// iat имеет секундную точность, поэтому водяной знак сравнивается
+// с усечённым до секунды временем.
+func checkRevocation(ctx context.Context, checker RevocationChecker, userID int64, issuedAt time.Time) error {
+	if checker == nil {
+		return nil
+	}
+
+	validAfter, err := checker.TokensValidAfter(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("check revocation: %w", err)
+	}
+
+	if !validAfter.IsZero() && issuedAt.Before(validAfter.Truncate(time.Second)) {
+		return ErrTokenRevoked
+	}
+
+	return nil
+}
+
+func numericTime(d *jwt.NumericDate) time.Time {
+	if d == nil {
+		return time.Time{}
+	}
+	return d.Time
+}
@@ -0,0 +1,80 @@
+package jwtv1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type revocationFunc func(ctx context.Context, userID int64) (time.Time, error)
+
+func (f revocationFunc) TokensValidAfter(ctx context.Context, userID int64) (time.Time, error) {
+	return f(ctx, userID)
+}
+
+func watermark(at time.Time, err error) RevocationChecker {
+	return revocationFunc(func(context.Context, int64) (time.Time, error) {
+		return at, err
+	})
+}
+
+func TestCheckRevocation(t *testing.T) {
+	mark := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	errRedis := errors.New("redis down")
+
+	tests := []struct {
+		name     string
+		checker  RevocationChecker
+		issuedAt time.Time
+		wantErr  error
+	}{
+		{
+			name:     "no checker",
+			issuedAt: mark.Add(-time.Hour),
+		},
+		{
+			name:     "no watermark",
+			checker:  watermark(time.Time{}, nil),
+			issuedAt: mark.Add(-time.Hour),
+		},
+		{
+			name:     "issued before watermark",
+			checker:  watermark(mark, nil),
+			issuedAt: mark.Add(-time.Second),
+			wantErr:  ErrTokenRevoked,
+		},
+		{
+			name:     "issued at watermark",
+			checker:  watermark(mark, nil),
+			issuedAt: mark,
+		},
+		{
+			name:     "issued after watermark",
+			checker:  watermark(mark, nil),
+			issuedAt: mark.Add(time.Second),
+		},
+		{
+			// iat без долей секунды: токен, выданный сразу после отзыва в ту же
+			// секунду, не должен считаться отозванным
+			name:     "same second as watermark",
+			checker:  watermark(mark.Add(700*time.Millisecond), nil),
+			issuedAt: mark,
+		},
+		{
+			name:     "checker error",
+			checker:  watermark(time.Time{}, errRedis),
+			issuedAt: mark,
+			wantErr:  errRedis,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRevocation(context.Background(), tt.checker, 42, tt.issuedAt)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
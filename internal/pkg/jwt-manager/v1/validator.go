@@ -1,6 +1,7 @@
 package jwtv1
 
 import (
+	"context"
 	"crypto/rsa"
 	"errors"
 	"fmt"
@@ -11,14 +12,32 @@ import (
 // Validator can only parse/validate tokens (no generation).
 // Use in services that receive tokens but don't issue them.
 type Validator struct {
-	publicKey *rsa.PublicKey
+	publicKey  *rsa.PublicKey
+	revocation RevocationChecker
 }
 
-func NewValidator(publicKey *rsa.PublicKey) (*Validator, error) {
+type ValidatorOption func(*Validator)
+
+// WithValidatorRevocationChecker enables the per-user watermark check
+// in ValidateAccessContext and ValidateRefreshContext.
+func WithValidatorRevocationChecker(checker RevocationChecker) ValidatorOption {
+	return func(v *Validator) {
+		v.revocation = checker
+	}
+}
+
+func NewValidator(publicKey *rsa.PublicKey, opts ...ValidatorOption) (*Validator, error) {
 	if publicKey == nil {
 		return nil, errors.New("public key is required")
 	}
-	return &Validator{publicKey: publicKey}, nil
+
+	v := &Validator{publicKey: publicKey}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v, nil
 }
 
 // ValidateAccess parses and validates an access token.
@@ -35,6 +54,7 @@ func (v *Validator) ValidateAccess(tokenString string) (*AccessClaims, error) {
 		Username: claims.Username,
 		Email:    claims.Email,
 		Exp:      claims.ExpiresAt.Time,
+		IssuedAt: numericTime(claims.IssuedAt),
 	}, nil
 }
 
@@ -48,12 +68,42 @@ func (v *Validator) ValidateRefresh(tokenString string) (*RefreshClaims, error)
 	}
 
 	return &RefreshClaims{
-		JWTID:  claims.ID,
-		UserID: claims.UserID,
-		Exp:    claims.ExpiresAt.Time,
+		JWTID:    claims.ID,
+		UserID:   claims.UserID,
+		Exp:      claims.ExpiresAt.Time,
+		IssuedAt: numericTime(claims.IssuedAt),
 	}, nil
 }
 
+// ValidateAccessContext validates an access token and rejects it with
+// ErrTokenRevoked if it was issued before the user's watermark.
+func (v *Validator) ValidateAccessContext(ctx context.Context, tokenString string) (*AccessClaims, error) {
+	claims, err := v.ValidateAccess(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkRevocation(ctx, v.revocation, claims.UserID, claims.IssuedAt); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// ValidateRefreshContext is ValidateRefresh with the watermark check.
+func (v *Validator) ValidateRefreshContext(ctx context.Context, tokenString string) (*RefreshClaims, error) {
+	claims, err := v.ValidateRefresh(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkRevocation(ctx, v.revocation, claims.UserID, claims.IssuedAt); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
 func (v *Validator) keyFunc(token *jwt.Token) (interface{}, error) {
 	if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 		return nil, fmt.Errorf("%w: %v", ErrSigningMethod, token.Header["alg"])
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncWriter пишет в w из отдельной горутины через буфер на size записей.
+// Write не блокируется никогда: если буфер полон (stdout не успевает),
+// запись отбрасывается и учитывается в Dropped. После Close пишет напрямую.
+type AsyncWriter struct {
+	w    io.Writer
+	ch   chan []byte
+	done chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+
+	dropped atomic.Uint64
+}
+
+func NewAsyncWriter(w io.Writer, size int) *AsyncWriter {
+	a := &AsyncWriter{
+		w:    w,
+		ch:   make(chan []byte, size),
+		done: make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return a.w.Write(p)
+	}
+
+	// slog переиспользует буфер после Write, поэтому копия обязательна
+	buf := make([]byte, len(p))
+	copy(buf, p)
+
+	select {
+	case a.ch <- buf:
+	default:
+		a.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+func (a *AsyncWriter) run() {
+	defer close(a.done)
+	for buf := range a.ch {
+		_, _ = a.w.Write(buf)
+	}
+}
+
+// Dropped возвращает число отброшенных из-за переполнения записей.
+func (a *AsyncWriter) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Close дописывает буфер и переключает Write на синхронную запись.
+func (a *AsyncWriter) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.ch)
+	a.mu.Unlock()
+
+	<-a.done
+	return nil
+}
@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// contextHandler добавляет в каждую запись атрибуты запроса (request_id,
+// user_id, method) и trace_id/span_id активного спана, чтобы логи можно было
+// найти по запросу и трейсу. Работает только с *Context методами
+// (log.InfoContext(ctx, ...)), иначе ctx в записи нет — тогда нужен FromContext.
+type contextHandler struct {
+	slog.Handler
+}
+
+func newContextHandler(h slog.Handler) slog.Handler {
+	return contextHandler{Handler: h}
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(requestAttrs(ctx)...)
+	r.AddAttrs(traceAttrs(ctx)...)
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
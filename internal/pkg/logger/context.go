@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	requestIDCtxKey struct{}
+	userIDCtxKey    struct{}
+	methodCtxKey    struct{}
+)
+
+// WithRequestID сохраняет идентификатор запроса в ctx.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, id)
+}
+
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDCtxKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithUserID сохраняет идентификатор аутентифицированного пользователя в ctx.
+// Выставляется auth интерсептором после проверки токена.
+func WithUserID(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, userIDCtxKey{}, id)
+}
+
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(userIDCtxKey{}).(int64)
+	return id, ok
+}
+
+// WithMethod сохраняет вызванный метод (gRPC FullMethod или "GET /path") в ctx.
+func WithMethod(ctx context.Context, method string) context.Context {
+	return context.WithValue(ctx, methodCtxKey{}, method)
+}
+
+func MethodFromContext(ctx context.Context) (string, bool) {
+	method, ok := ctx.Value(methodCtxKey{}).(string)
+	return method, ok && method != ""
+}
+
+// requestAttrs возвращает атрибуты запроса из ctx: request_id, user_id, method.
+func requestAttrs(ctx context.Context) []slog.Attr {
+	var attrs []slog.Attr
+	if id, ok := RequestIDFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	if id, ok := UserIDFromContext(ctx); ok {
+		attrs = append(attrs, slog.Int64("user_id", id))
+	}
+	if method, ok := MethodFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("method", method))
+	}
+	return attrs
+}
+
+// traceAttrs возвращает trace_id и span_id активного спана.
+func traceAttrs(ctx context.Context) []slog.Attr {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []slog.Attr{
+		slog.String("trace_id", sc.TraceID().String()),
+		slog.String("span_id", sc.SpanID().String()),
+	}
+}
+
+// FromContext возвращает log с атрибутами запроса и трейса из ctx.
+// Нужен там, где логируют без ctx (log.Info вместо log.InfoContext):
+// *Context методы получают те же атрибуты от обработчика Setup.
+// Если log равен nil, берётся slog.Default().
+func FromContext(ctx context.Context, log *slog.Logger) *slog.Logger {
+	if log == nil {
+		log = slog.Default()
+	}
+
+	attrs := append(requestAttrs(ctx), traceAttrs(ctx)...)
+	if len(attrs) == 0 {
+		return log
+	}
+
+	args := make([]any, 0, len(attrs))
+	for _, a := range attrs {
+		args = append(args, a)
+	}
+	return log.With(args...)
+}
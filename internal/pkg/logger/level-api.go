@@ -0,0 +1,208 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// DefaultLevelTTL — через сколько уровень, изменённый через API, откатывается.
+	// Забытый debug в проде не должен жить вечно.
+	DefaultLevelTTL = 15 * time.Minute
+	MaxLevelTTL     = 24 * time.Hour
+
+	// LevelServiceName — gRPC сервис управления уровнями. Описан вручную
+	// на well-known типах (Empty, Struct), чтобы не тащить кодогенерацию в пакет.
+	LevelServiceName = "logger.v1.LevelService"
+)
+
+var ErrInvalidLevelRequest = errors.New("invalid level request")
+
+// LevelRequest — изменение уровня через API. Пустой Component меняет
+// глобальный уровень; пустой Level при заданном Component снимает
+// переопределение компонента. TTL — длительность ("10m"), по умолчанию
+// DefaultLevelTTL: изменения через API всегда временные.
+type LevelRequest struct {
+	Level     string `json:"level"`
+	Component string `json:"component"`
+	TTL       string `json:"ttl"`
+}
+
+// ApplyLevel применяет изменение уровня и возвращает новое состояние.
+func (l *Logger) ApplyLevel(req LevelRequest) (LevelState, error) {
+	if req.Level == "" {
+		if req.Component == "" {
+			return LevelState{}, fmt.Errorf("%w: level is required", ErrInvalidLevelRequest)
+		}
+		l.ResetComponentLevel(req.Component)
+		return l.Levels(), nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+		return LevelState{}, fmt.Errorf("%w: %v", ErrInvalidLevelRequest, err)
+	}
+
+	ttl := DefaultLevelTTL
+	if req.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.TTL); err != nil {
+			return LevelState{}, fmt.Errorf("%w: ttl: %v", ErrInvalidLevelRequest, err)
+		}
+	}
+	if ttl <= 0 || ttl > MaxLevelTTL {
+		return LevelState{}, fmt.Errorf("%w: ttl must be in (0, %s], got %s", ErrInvalidLevelRequest, MaxLevelTTL, ttl)
+	}
+
+	if req.Component == "" {
+		l.SetLevelFor(level, ttl)
+	} else {
+		l.SetComponentLevel(req.Component, level, ttl)
+	}
+	return l.Levels(), nil
+}
+
+// LevelHandler — HTTP API уровней: GET возвращает состояние,
+// PUT принимает LevelRequest. Аутентификацию добавляет вызывающий.
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, l.Levels())
+
+		case http.MethodPut:
+			var req LevelRequest
+			dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&req); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			state, err := l.ApplyLevel(req)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, state)
+
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// RegisterLevelService регистрирует gRPC API уровней:
+// GetLevel(Empty) Struct и SetLevel(Struct) Struct, где Struct — это
+// LevelRequest и LevelState в JSON представлении. Аутентификацию
+// добавляет интерсептор вызывающего (методы начинаются с "/"+LevelServiceName).
+func (l *Logger) RegisterLevelService(s grpc.ServiceRegistrar) {
+	s.RegisterService(&levelServiceDesc, levelServer{l: l})
+}
+
+type levelService interface {
+	getLevel(ctx context.Context, _ *emptypb.Empty) (*structpb.Struct, error)
+	setLevel(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+}
+
+type levelServer struct {
+	l *Logger
+}
+
+func (s levelServer) getLevel(context.Context, *emptypb.Empty) (*structpb.Struct, error) {
+	return toStruct(s.l.Levels())
+}
+
+func (s levelServer) setLevel(_ context.Context, in *structpb.Struct) (*structpb.Struct, error) {
+	raw, err := json.Marshal(in.AsMap())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var req LevelRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	state, err := s.l.ApplyLevel(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return toStruct(state)
+}
+
+func toStruct(state LevelState) (*structpb.Struct, error) {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	st, err := structpb.NewStruct(m)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return st, nil
+}
+
+var levelServiceDesc = grpc.ServiceDesc{
+	ServiceName: LevelServiceName,
+	HandlerType: (*levelService)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLevel",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				in := new(emptypb.Empty)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(levelService).getLevel(ctx, in)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + LevelServiceName + "/GetLevel"}
+				return interceptor(ctx, in, info, func(ctx context.Context, req any) (any, error) {
+					return srv.(levelService).getLevel(ctx, req.(*emptypb.Empty))
+				})
+			},
+		},
+		{
+			MethodName: "SetLevel",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				in := new(structpb.Struct)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(levelService).setLevel(ctx, in)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + LevelServiceName + "/SetLevel"}
+				return interceptor(ctx, in, info, func(ctx context.Context, req any) (any, error) {
+					return srv.(levelService).setLevel(ctx, req.(*structpb.Struct))
+				})
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
@@ -0,0 +1,245 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ComponentKey — атрибут, по которому выбирается уровень компонента:
+// log.With(slog.String(logger.ComponentKey, "repository.redis")).
+const ComponentKey = "component"
+
+// Component возвращает log с атрибутом компонента.
+func Component(log *slog.Logger, name string) *slog.Logger {
+	return log.With(slog.String(ComponentKey, name))
+}
+
+// LevelState — текущие уровни: глобальный и переопределения компонентов.
+// Expires нулевой, если уровень не временный.
+type LevelState struct {
+	Level      slog.Level       `json:"level"`
+	Expires    time.Time        `json:"expires,omitzero"`
+	Components []ComponentLevel `json:"components"`
+}
+
+type ComponentLevel struct {
+	Component string     `json:"component"`
+	Level     slog.Level `json:"level"`
+	Expires   time.Time  `json:"expires,omitzero"`
+}
+
+type override struct {
+	level   slog.Level
+	expires time.Time
+	timer   *time.Timer
+}
+
+// levels хранит глобальный уровень и переопределения компонентов.
+// Запись под mu, чтение из обработчика — без блокировок через снимок.
+type levels struct {
+	global *slog.LevelVar
+
+	mu            sync.Mutex
+	globalTimer   *time.Timer
+	globalExpires time.Time
+	globalRevert  slog.Level
+	overrides     map[string]override
+
+	snapshot atomic.Pointer[map[string]slog.Level]
+	// min — минимальный уровень среди глобального и переопределений:
+	// ниже него запись не нужна ни одному компоненту.
+	min atomic.Int64
+}
+
+func newLevels(level slog.Level) *levels {
+	l := &levels{
+		global:    &slog.LevelVar{},
+		overrides: make(map[string]override),
+	}
+	l.global.Set(level)
+	l.publish()
+	return l
+}
+
+// set меняет глобальный уровень. ttl > 0 — временно: по истечении
+// возвращается уровень, действовавший до первого временного изменения.
+func (l *levels) set(level slog.Level, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.globalTimer != nil {
+		l.globalTimer.Stop()
+		l.globalTimer = nil
+	} else if ttl > 0 {
+		l.globalRevert = l.global.Level()
+	}
+	l.globalExpires = time.Time{}
+
+	l.global.Set(level)
+	if ttl > 0 {
+		l.globalExpires = time.Now().Add(ttl)
+		var timer *time.Timer
+		timer = time.AfterFunc(ttl, func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if l.globalTimer != timer {
+				return
+			}
+			l.globalTimer = nil
+			l.globalExpires = time.Time{}
+			l.global.Set(l.globalRevert)
+			l.publishLocked()
+		})
+		l.globalTimer = timer
+	}
+	l.publishLocked()
+}
+
+// setComponent переопределяет уровень компонента. ttl > 0 — временно.
+func (l *levels) setComponent(component string, level slog.Level, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stopLocked(component)
+
+	o := override{level: level}
+	if ttl > 0 {
+		o.expires = time.Now().Add(ttl)
+		var timer *time.Timer
+		timer = time.AfterFunc(ttl, func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if cur, ok := l.overrides[component]; !ok || cur.timer != timer {
+				return
+			}
+			delete(l.overrides, component)
+			l.publishLocked()
+		})
+		o.timer = timer
+	}
+	l.overrides[component] = o
+	l.publishLocked()
+}
+
+func (l *levels) resetComponent(component string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stopLocked(component)
+	delete(l.overrides, component)
+	l.publishLocked()
+}
+
+func (l *levels) stopLocked(component string) {
+	if o, ok := l.overrides[component]; ok && o.timer != nil {
+		o.timer.Stop()
+	}
+}
+
+func (l *levels) state() LevelState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := LevelState{
+		Level:      l.global.Level(),
+		Expires:    l.globalExpires,
+		Components: make([]ComponentLevel, 0, len(l.overrides)),
+	}
+	for _, name := range slices.Sorted(maps.Keys(l.overrides)) {
+		o := l.overrides[name]
+		s.Components = append(s.Components, ComponentLevel{Component: name, Level: o.level, Expires: o.expires})
+	}
+	return s
+}
+
+func (l *levels) publish() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.publishLocked()
+}
+
+func (l *levels) publishLocked() {
+	snap := make(map[string]slog.Level, len(l.overrides))
+	minLevel := l.global.Level()
+	for name, o := range l.overrides {
+		snap[name] = o.level
+		minLevel = min(minLevel, o.level)
+	}
+	l.snapshot.Store(&snap)
+	l.min.Store(int64(minLevel))
+}
+
+// levelFor возвращает уровень компонента: переопределение самого компонента
+// или ближайшего родителя ("repository" для "repository.redis"), иначе глобальный.
+func (l *levels) levelFor(component string) slog.Level {
+	snap := *l.snapshot.Load()
+	for component != "" && len(snap) > 0 {
+		if level, ok := snap[component]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(component, '.')
+		if i < 0 {
+			break
+		}
+		component = component[:i]
+	}
+	return l.global.Level()
+}
+
+// levelHandler фильтрует записи по уровню их компонента. Компонент берётся
+// из атрибутов логгера (With) или самой записи. Нижележащий обработчик
+// уровень не фильтрует.
+type levelHandler struct {
+	slog.Handler
+	levels    *levels
+	component string
+}
+
+func newLevelHandler(h slog.Handler, l *levels) slog.Handler {
+	return levelHandler{Handler: h, levels: l}
+}
+
+func (h levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.component != "" {
+		return level >= h.levels.levelFor(h.component)
+	}
+	// компонент может прийти в атрибутах записи — окончательно решает Handle
+	return level >= slog.Level(h.levels.min.Load())
+}
+
+func (h levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	component := h.component
+	if component == "" {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == ComponentKey {
+				component = a.Value.String()
+				return false
+			}
+			return true
+		})
+	}
+	if r.Level < h.levels.levelFor(component) {
+		return nil
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	component := h.component
+	for _, a := range attrs {
+		if a.Key == ComponentKey {
+			component = a.Value.String()
+		}
+	}
+	return levelHandler{Handler: h.Handler.WithAttrs(attrs), levels: h.levels, component: component}
+}
+
+func (h levelHandler) WithGroup(name string) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithGroup(name), levels: h.levels, component: h.component}
+}
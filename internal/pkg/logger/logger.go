@@ -3,7 +3,8 @@ package logger
 
 import (
 	"log/slog"
-	"os"
+	"math"
+	"time"
 )
 
 const (
@@ -12,65 +13,160 @@ const (
 	EnvProd  = "prod"
 )
 
+// allLevels отключает фильтрацию в конечном обработчике: уровни,
+// в том числе по компонентам, проверяет levelHandler.
+const allLevels = slog.Level(math.MinInt)
+
 type Logger struct {
 	*slog.Logger
-	level *slog.LevelVar
+	levels  *levels
+	sampler *sampler
+	async   *AsyncWriter
 }
 
-func Init(env string) *Logger {
-	l := Setup(env)
+func Init(env string, opts ...Option) *Logger {
+	l := Setup(env, opts...)
 	slog.SetDefault(l.Logger)
 	return l
 }
 
-func Setup(env string) *Logger {
-	lvl := &slog.LevelVar{}
+// DefaultLevel — уровень логирования окружения, если он не задан явно.
+func DefaultLevel(env string) slog.Level {
+	switch env {
+	case EnvLocal, EnvDev:
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func Setup(env string, opts ...Option) *Logger {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	lvls := newLevels(DefaultLevel(env))
+
+	var async *AsyncWriter
+	out := cfg.output
+	if cfg.bufferSize > 0 {
+		async = NewAsyncWriter(cfg.output, cfg.bufferSize)
+		out = async
+	}
 
 	var handler slog.Handler
 
 	switch env {
 	case EnvLocal:
-		lvl.Set(slog.LevelDebug)
-		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-			Level:     lvl,
+		handler = slog.NewTextHandler(out, &slog.HandlerOptions{
+			Level:     allLevels,
 			AddSource: true,
 		})
 
 	case EnvDev:
-		lvl.Set(slog.LevelDebug)
-		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level:     lvl,
+		handler = slog.NewJSONHandler(out, &slog.HandlerOptions{
+			Level:     allLevels,
 			AddSource: true,
 		})
 
 	case EnvProd:
-		lvl.Set(slog.LevelInfo)
-		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level:     lvl,
+		handler = slog.NewJSONHandler(out, &slog.HandlerOptions{
+			Level:     allLevels,
 			AddSource: false,
 		})
 
 	default:
-		lvl.Set(slog.LevelInfo)
-		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-			Level:     lvl,
+		handler = slog.NewTextHandler(out, &slog.HandlerOptions{
+			Level:     allLevels,
 			AddSource: true,
 		})
 	}
 
+	handler = newContextHandler(newRedactHandler(handler, cfg.redactKeys))
+
+	var smp *sampler
+	if cfg.sampleInterval > 0 && cfg.sampleBurst > 0 {
+		smp = newSampler(cfg.sampleInterval, cfg.sampleBurst)
+		handler = newSamplingHandler(handler, smp)
+	}
+
 	return &Logger{
-		Logger: slog.New(handler),
-		level:  lvl,
+		Logger:  slog.New(newLevelHandler(handler, lvls)),
+		levels:  lvls,
+		sampler: smp,
+		async:   async,
 	}
 }
 
+// Close дописывает сводки семплирования и буфер асинхронной записи.
+// Записи после Close пишутся синхронно, так что логировать можно и дальше.
+func (l *Logger) Close() error {
+	if l.sampler != nil {
+		l.sampler.flush()
+	}
+	if l.async == nil {
+		return nil
+	}
+	return l.async.Close()
+}
+
+// Dropped — сколько записей потеряно из-за переполнения буфера WithAsync.
+func (l *Logger) Dropped() uint64 {
+	if l.async == nil {
+		return 0
+	}
+	return l.async.Dropped()
+}
+
+// Suppressed — сколько записей подавлено семплированием WithSampling.
+func (l *Logger) Suppressed() uint64 {
+	if l.sampler == nil {
+		return 0
+	}
+	return l.sampler.suppressed.Load()
+}
+
+// SetLevel меняет глобальный уровень и отменяет временный, если он был.
 func (l *Logger) SetLevel(level slog.Level) {
-	l.level.Set(level)
+	l.levels.set(level, 0)
 	l.Info("log level changed", slog.String("new_level", level.String()))
 }
 
+// SetLevelFor временно меняет глобальный уровень: через ttl вернётся
+// уровень, действовавший до первого временного изменения.
+func (l *Logger) SetLevelFor(level slog.Level, ttl time.Duration) {
+	l.levels.set(level, ttl)
+	l.Info("log level changed",
+		slog.String("new_level", level.String()),
+		slog.Duration("ttl", ttl),
+	)
+}
+
 func (l *Logger) GetLevel() slog.Level {
-	return l.level.Level()
+	return l.levels.global.Level()
+}
+
+// SetComponentLevel переопределяет уровень компонента и его потомков
+// ("repository" действует и на "repository.redis"). ttl == 0 — бессрочно.
+func (l *Logger) SetComponentLevel(component string, level slog.Level, ttl time.Duration) {
+	l.levels.setComponent(component, level, ttl)
+	l.Info("component log level changed",
+		slog.String("target", component),
+		slog.String("new_level", level.String()),
+		slog.Duration("ttl", ttl),
+	)
+}
+
+// ResetComponentLevel возвращает компоненту глобальный уровень.
+func (l *Logger) ResetComponentLevel(component string) {
+	l.levels.resetComponent(component)
+	l.Info("component log level reset", slog.String("target", component))
+}
+
+// Levels возвращает глобальный уровень и переопределения компонентов.
+func (l *Logger) Levels() LevelState {
+	return l.levels.state()
 }
 
 func Err(err error) slog.Attr {
@@ -86,6 +182,8 @@ func (l *Logger) WithService(name, version string) *Logger {
 			slog.String("service", name),
 			slog.String("version", version),
 		),
-		level: l.level,
+		levels:  l.levels,
+		sampler: l.sampler,
+		async:   l.async,
 	}
 }
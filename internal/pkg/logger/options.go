@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+type config struct {
+	output         io.Writer
+	redactKeys     []string
+	sampleInterval time.Duration
+	sampleBurst    int
+	bufferSize     int
+}
+
+func defaultConfig() *config {
+	return &config{
+		output: os.Stdout,
+	}
+}
+
+type Option func(*config)
+
+// WithOutput задаёт, куда пишутся записи (по умолчанию os.Stdout).
+func WithOutput(w io.Writer) Option {
+	return func(c *config) {
+		c.output = w
+	}
+}
+
+// WithRedactKeys добавляет атрибуты, значения которых скрываются в логе,
+// к встроенному списку (password, token, secret, ...).
+func WithRedactKeys(keys ...string) Option {
+	return func(c *config) {
+		c.redactKeys = append(c.redactKeys, keys...)
+	}
+}
+
+// WithSampling пропускает не больше burst одинаковых записей (уровень +
+// сообщение) за interval, об остальных пишет сводку. Нулевые значения
+// отключают семплирование.
+func WithSampling(interval time.Duration, burst int) Option {
+	return func(c *config) {
+		c.sampleInterval = interval
+		c.sampleBurst = burst
+	}
+}
+
+// WithAsync пишет через AsyncWriter с буфером на size записей:
+// логирование не блокирует запросы, при переполнении записи теряются.
+// Ноль — синхронная запись.
+func WithAsync(size int) Option {
+	return func(c *config) {
+		c.bufferSize = size
+	}
+}
@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// defaultRedactKeys — атрибуты, значения которых не пишутся в лог никогда.
+// Ключи сравниваются без регистра, '_' и '-': refresh_token == refreshToken.
+var defaultRedactKeys = []string{
+	"password", "passwd", "newPassword", "oldPassword",
+	"secret", "token", "accessToken", "refreshToken", "idToken",
+	"authorization", "cookie", "apiKey", "privateKey", "passphrase",
+}
+
+// emailKeys маскируются частично: по i***@example.com ещё можно разобрать
+// инцидент, но не собрать базу адресов из логов.
+var emailKeys = map[string]bool{"email": true, "mail": true}
+
+var (
+	keyReplacer = strings.NewReplacer("_", "", "-", "")
+
+	emailRe = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	jwtRe   = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)
+)
+
+// redactHandler скрывает чувствительные данные до того, как запись попадёт
+// в обработчик: значения по ключам из списка, адреса почты и JWT в любых
+// строковых значениях, ошибках, fmt.Stringer и в сообщении. Группы и slog.LogValuer обходятся рекурсивно.
+type redactHandler struct {
+	slog.Handler
+	keys map[string]bool
+}
+
+func newRedactHandler(h slog.Handler, extra []string) slog.Handler {
+	keys := make(map[string]bool, len(defaultRedactKeys)+len(extra))
+	for _, k := range defaultRedactKeys {
+		keys[normalizeKey(k)] = true
+	}
+	for _, k := range extra {
+		keys[normalizeKey(k)] = true
+	}
+	return redactHandler{Handler: h, keys: keys}
+}
+
+func (h redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, redactString(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.redact(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, out)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		out = append(out, h.redact(a))
+	}
+	return redactHandler{Handler: h.Handler.WithAttrs(out), keys: h.keys}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{Handler: h.Handler.WithGroup(name), keys: h.keys}
+}
+
+func (h redactHandler) redact(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	key := normalizeKey(a.Key)
+
+	switch {
+	case a.Value.Kind() == slog.KindGroup:
+		group := a.Value.Group()
+		out := make([]slog.Attr, 0, len(group))
+		for _, ga := range group {
+			out = append(out, h.redact(ga))
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(out...)}
+
+	case h.keys[key]:
+		return slog.String(a.Key, redacted)
+
+	case emailKeys[key] && a.Value.Kind() == slog.KindString:
+		return slog.String(a.Key, maskEmail(a.Value.String()))
+
+	case a.Value.Kind() == slog.KindString:
+		if s := a.Value.String(); s != "" {
+			if masked := redactString(s); masked != s {
+				return slog.String(a.Key, masked)
+			}
+		}
+
+	case a.Value.Kind() == slog.KindAny:
+		// "error", err: JSON обработчик напечатает err.Error() как есть
+		var s string
+		switch v := a.Value.Any().(type) {
+		case error:
+			s = v.Error()
+		case fmt.Stringer:
+			s = v.String()
+		default:
+			return a
+		}
+		if masked := redactString(s); masked != s {
+			return slog.String(a.Key, masked)
+		}
+	}
+
+	return a
+}
+
+// redactString маскирует адреса почты и вырезает JWT в произвольном тексте.
+func redactString(s string) string {
+	if strings.Contains(s, "eyJ") {
+		s = jwtRe.ReplaceAllString(s, redacted)
+	}
+	if strings.Contains(s, "@") {
+		s = emailRe.ReplaceAllStringFunc(s, maskEmail)
+	}
+	return s
+}
+
+// maskEmail оставляет первый символ локальной части и домен: i***@example.com.
+func maskEmail(email string) string {
+	at := strings.LastIndexByte(email, '@')
+	if at <= 0 {
+		return redacted
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+func normalizeKey(key string) string {
+	return keyReplacer.Replace(strings.ToLower(key))
+}
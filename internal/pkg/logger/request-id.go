@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDHeader — заголовок (и ключ gRPC метаданных) с идентификатором запроса.
+const RequestIDHeader = "x-request-id"
+
+// maxRequestIDLen ограничивает чужой идентификатор: он попадает в каждую строку лога.
+const maxRequestIDLen = 128
+
+// UnaryServerInterceptor берёт x-request-id из метаданных или генерирует новый,
+// кладёт его и метод в ctx и возвращает клиенту в заголовках ответа.
+// Ставится первым в цепочке, чтобы идентификатор был у всех следующих интерсепторов.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ctx, id := grpcRequestContext(ctx, info.FullMethod)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor — то же для стримов.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, id := grpcRequestContext(ss.Context(), info.FullMethod)
+		_ = ss.SetHeader(metadata.Pairs(RequestIDHeader, id))
+
+		return handler(srv, &requestStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// HTTPMiddleware — то же для HTTP: X-Request-ID из запроса или новый, в ctx и в ответ.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := WithRequestID(r.Context(), id)
+		ctx = WithMethod(ctx, r.Method+" "+r.URL.Path)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func grpcRequestContext(ctx context.Context, method string) (context.Context, string) {
+	var id string
+	if ids := metadata.ValueFromIncomingContext(ctx, RequestIDHeader); len(ids) > 0 {
+		id = ids[0]
+	}
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+
+	ctx = WithRequestID(ctx, id)
+	ctx = WithMethod(ctx, method)
+	return ctx, id
+}
+
+// validRequestID пропускает только печатный ASCII разумной длины,
+// чтобы клиент не мог подделать строки лога переводом строки.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+type requestStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type sampleKey struct {
+	level slog.Level
+	msg   string
+}
+
+type sampleWindow struct {
+	start      time.Time
+	count      int
+	suppressed int
+	// handler, через который прошла первая запись окна: сводка
+	// о подавленных уйдёт с теми же атрибутами логгера (op, component)
+	handler slog.Handler
+}
+
+type sampleSummary struct {
+	key        sampleKey
+	suppressed int
+	handler    slog.Handler
+}
+
+// sampler ограничивает одинаковые записи (уровень + сообщение): за interval
+// проходят первые burst, остальные считаются, и по окончании окна пишется
+// сводка "N similar messages suppressed". Окна закрываются при следующей
+// записи после interval, а если записей больше нет — по таймеру, который
+// взводит первая подавленная запись: иначе сводка о прекратившейся атаке
+// не вышла бы никогда. Close дописывает сводки незакрытых окон.
+type sampler struct {
+	interval time.Duration
+	burst    int
+	now      func() time.Time
+
+	mu        sync.Mutex
+	windows   map[sampleKey]*sampleWindow
+	lastSweep time.Time
+	timer     *time.Timer
+
+	suppressed atomic.Uint64
+}
+
+func newSampler(interval time.Duration, burst int) *sampler {
+	return &sampler{
+		interval: interval,
+		burst:    burst,
+		now:      time.Now,
+		windows:  make(map[sampleKey]*sampleWindow),
+	}
+}
+
+// allow решает судьбу записи и возвращает сводки закрытых окон.
+func (s *sampler) allow(key sampleKey, h slog.Handler) (bool, []sampleSummary) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summaries []sampleSummary
+	if now.Sub(s.lastSweep) >= s.interval {
+		s.lastSweep = now
+		summaries = s.sweep(now, false)
+	}
+
+	w := s.windows[key]
+	if w == nil {
+		w = &sampleWindow{start: now, handler: h}
+		s.windows[key] = w
+	} else if now.Sub(w.start) >= s.interval {
+		summaries = appendSummary(summaries, key, w)
+		*w = sampleWindow{start: now, handler: h}
+	}
+
+	w.count++
+	if w.count <= s.burst {
+		return true, summaries
+	}
+
+	w.suppressed++
+	s.suppressed.Add(1)
+	if s.timer == nil {
+		s.timer = time.AfterFunc(w.start.Add(s.interval).Sub(now), s.flushExpired)
+	}
+	return false, summaries
+}
+
+// sweep закрывает истёкшие окна (все, если all) и возвращает их сводки.
+// Вызывается под s.mu.
+func (s *sampler) sweep(now time.Time, all bool) []sampleSummary {
+	var summaries []sampleSummary
+	for k, w := range s.windows {
+		if all || now.Sub(w.start) >= s.interval {
+			summaries = appendSummary(summaries, k, w)
+			delete(s.windows, k)
+		}
+	}
+	return summaries
+}
+
+// flushExpired пишет сводки истёкших окон и, если остались окна
+// с подавленными записями, взводит таймер на ближайшее из них.
+func (s *sampler) flushExpired() {
+	now := s.now()
+
+	s.mu.Lock()
+	s.timer = nil
+	summaries := s.sweep(now, false)
+
+	var next time.Time
+	for _, w := range s.windows {
+		if end := w.start.Add(s.interval); w.suppressed > 0 && (next.IsZero() || end.Before(next)) {
+			next = end
+		}
+	}
+	if !next.IsZero() {
+		s.timer = time.AfterFunc(next.Sub(now), s.flushExpired)
+	}
+	s.mu.Unlock()
+
+	s.write(summaries)
+}
+
+// flush пишет сводки всех окон, в том числе незакрытых.
+func (s *sampler) flush() {
+	s.mu.Lock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	summaries := s.sweep(s.now(), true)
+	s.mu.Unlock()
+
+	s.write(summaries)
+}
+
+func (s *sampler) write(summaries []sampleSummary) {
+	for _, sm := range summaries {
+		r := slog.NewRecord(s.now(), sm.key.level, fmt.Sprintf("%d similar messages suppressed", sm.suppressed), 0)
+		r.AddAttrs(
+			slog.String("suppressed_msg", sm.key.msg),
+			slog.Int("suppressed", sm.suppressed),
+			slog.Duration("interval", s.interval),
+		)
+		// не ctx текущей записи: её request_id к сводке отношения не имеет
+		_ = sm.handler.Handle(context.Background(), r)
+	}
+}
+
+func appendSummary(summaries []sampleSummary, key sampleKey, w *sampleWindow) []sampleSummary {
+	if w.suppressed == 0 {
+		return summaries
+	}
+	return append(summaries, sampleSummary{key: key, suppressed: w.suppressed, handler: w.handler})
+}
+
+type samplingHandler struct {
+	slog.Handler
+	sampler *sampler
+}
+
+func newSamplingHandler(h slog.Handler, s *sampler) slog.Handler {
+	return samplingHandler{Handler: h, sampler: s}
+}
+
+func (h samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	ok, summaries := h.sampler.allow(sampleKey{level: r.Level, msg: r.Message}, h.Handler)
+	h.sampler.write(summaries)
+
+	if !ok {
+		return nil
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return samplingHandler{Handler: h.Handler.WithAttrs(attrs), sampler: h.sampler}
+}
+
+func (h samplingHandler) WithGroup(name string) slog.Handler {
+	return samplingHandler{Handler: h.Handler.WithGroup(name), sampler: h.sampler}
+}
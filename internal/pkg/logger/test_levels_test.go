@@ -0,0 +1,204 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newLevelsLogger(buf *bytes.Buffer, level slog.Level) *Logger {
+	lv := newLevels(level)
+	h := slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: allLevels})
+	return &Logger{Logger: slog.New(newLevelHandler(h, lv)), levels: lv}
+}
+
+func TestComponentLevels(t *testing.T) {
+	var buf bytes.Buffer
+	l := newLevelsLogger(&buf, slog.LevelInfo)
+	l.SetComponentLevel("repository", slog.LevelDebug, 0)
+	l.SetComponentLevel("business", slog.LevelError, 0)
+	buf.Reset()
+
+	tests := []struct {
+		name string
+		log  func()
+		want bool
+	}{
+		{
+			name: "override via With",
+			log:  func() { Component(l.Logger, "repository").Debug("msg") },
+			want: true,
+		},
+		{
+			name: "parent override",
+			log:  func() { Component(l.Logger, "repository.redis").Debug("msg") },
+			want: true,
+		},
+		{
+			name: "override in record",
+			log:  func() { l.Debug("msg", slog.String(ComponentKey, "repository.redis")) },
+			want: true,
+		},
+		{
+			name: "stricter override",
+			log:  func() { Component(l.Logger, "business").Warn("msg") },
+			want: false,
+		},
+		{
+			name: "global without component",
+			log:  func() { l.Debug("msg") },
+			want: false,
+		},
+		{
+			name: "global for other component",
+			log:  func() { Component(l.Logger, "migrator").Info("msg") },
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.log()
+			if got := buf.Len() > 0; got != tt.want {
+				t.Errorf("got logged %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	l.ResetComponentLevel("repository")
+	buf.Reset()
+	Component(l.Logger, "repository.redis").Debug("msg")
+	if buf.Len() > 0 {
+		t.Error("got debug record after reset")
+	}
+}
+
+func TestLevelTTL(t *testing.T) {
+	var buf bytes.Buffer
+	l := newLevelsLogger(&buf, slog.LevelInfo)
+
+	l.SetLevelFor(slog.LevelDebug, 20*time.Millisecond)
+	// повторное временное изменение не сдвигает уровень отката
+	l.SetLevelFor(slog.LevelWarn, 20*time.Millisecond)
+	l.SetComponentLevel("repository", slog.LevelDebug, 20*time.Millisecond)
+
+	state := l.Levels()
+	if state.Level != slog.LevelWarn || state.Expires.IsZero() || len(state.Components) != 1 {
+		t.Fatalf("got state %+v, want temporary warn and one component", state)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		state = l.Levels()
+		if state.Level == slog.LevelInfo && len(state.Components) == 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if state.Level != slog.LevelInfo || !state.Expires.IsZero() || len(state.Components) != 0 {
+		t.Errorf("got state %+v, want reverted to info without components", state)
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := newLevelsLogger(&buf, slog.LevelInfo)
+	h := l.LevelHandler()
+
+	tests := []struct {
+		name     string
+		method   string
+		body     string
+		wantCode int
+	}{
+		{name: "get", method: http.MethodGet, wantCode: http.StatusOK},
+		{name: "set global", method: http.MethodPut, body: `{"level":"debug","ttl":"1m"}`, wantCode: http.StatusOK},
+		{name: "set component", method: http.MethodPut, body: `{"level":"debug","component":"repository.redis"}`, wantCode: http.StatusOK},
+		{name: "reset component", method: http.MethodPut, body: `{"component":"repository.redis"}`, wantCode: http.StatusOK},
+		{name: "bad level", method: http.MethodPut, body: `{"level":"loud"}`, wantCode: http.StatusBadRequest},
+		{name: "ttl too long", method: http.MethodPut, body: `{"level":"debug","ttl":"48h"}`, wantCode: http.StatusBadRequest},
+		{name: "unknown field", method: http.MethodPut, body: `{"lvl":"debug"}`, wantCode: http.StatusBadRequest},
+		{name: "method", method: http.MethodDelete, wantCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, "/admin/log-level", strings.NewReader(tt.body)))
+			if rec.Code != tt.wantCode {
+				t.Errorf("got code %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/log-level", nil))
+
+	var state LevelState
+	if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Level != slog.LevelDebug || len(state.Components) != 0 {
+		t.Errorf("got state %+v, want debug without components", state)
+	}
+}
+
+func TestLevelService(t *testing.T) {
+	var buf bytes.Buffer
+	l := newLevelsLogger(&buf, slog.LevelInfo)
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	l.RegisterLevelService(srv)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx := context.Background()
+	req, _ := structpb.NewStruct(map[string]any{"level": "debug", "component": "business"})
+	var resp structpb.Struct
+	if err := conn.Invoke(ctx, "/"+LevelServiceName+"/SetLevel", req, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(resp.Fields["components"].GetListValue().GetValues()); got != 1 {
+		t.Errorf("got %d components, want 1", got)
+	}
+
+	if err := conn.Invoke(ctx, "/"+LevelServiceName+"/GetLevel", &emptypb.Empty{}, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Fields["level"].GetStringValue(); got != "INFO" {
+		t.Errorf("got level %q, want INFO", got)
+	}
+
+	bad, _ := structpb.NewStruct(map[string]any{"level": "loud"})
+	if err := conn.Invoke(ctx, "/"+LevelServiceName+"/SetLevel", bad, &resp); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v, want %v", err, codes.InvalidArgument)
+	}
+}
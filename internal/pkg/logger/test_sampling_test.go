@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Unix(0, 0)
+
+	s := newSampler(time.Second, 2)
+	s.now = func() time.Time { return now }
+	log := slog.New(newSamplingHandler(slog.NewJSONHandler(&buf, nil), s)).With(slog.String("op", "guard"))
+
+	for range 5 {
+		log.Warn("rate limit exceeded")
+	}
+	log.Warn("other message")
+
+	if got := strings.Count(buf.String(), "rate limit exceeded"); got != 2 {
+		t.Errorf("got %d records in window, want 2", got)
+	}
+	if s.suppressed.Load() != 3 {
+		t.Errorf("got suppressed %d, want 3", s.suppressed.Load())
+	}
+
+	// следующее окно: сводка о прошлом, затем снова пропускаются первые burst
+	buf.Reset()
+	now = now.Add(time.Second)
+	log.Warn("rate limit exceeded")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want summary and record: %s", len(lines), buf.String())
+	}
+
+	var summary map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary["msg"] != "3 similar messages suppressed" ||
+		summary["suppressed_msg"] != "rate limit exceeded" ||
+		summary["op"] != "guard" ||
+		summary["level"] != "WARN" {
+		t.Errorf("got summary %v", summary)
+	}
+}
+
+func TestSamplingSweep(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Unix(0, 0)
+
+	s := newSampler(time.Second, 1)
+	s.now = func() time.Time { return now }
+	log := slog.New(newSamplingHandler(slog.NewJSONHandler(&buf, nil), s))
+
+	log.Error("failed login")
+	log.Error("failed login")
+
+	// атака закончилась: сводку выпускает любая запись после интервала
+	buf.Reset()
+	now = now.Add(2 * time.Second)
+	log.Info("unrelated")
+
+	if !strings.Contains(buf.String(), "1 similar messages suppressed") {
+		t.Errorf("got %s, want summary on sweep", buf.String())
+	}
+}
+
+// syncBuffer — bytes.Buffer для записи из другой горутины.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Атака закончилась и логи затихли: сводку пишет таймер, а не следующая запись.
+func TestSamplingFlushesQuietWindow(t *testing.T) {
+	var buf syncBuffer
+	log := slog.New(newSamplingHandler(slog.NewJSONHandler(&buf, nil), newSampler(20*time.Millisecond, 1)))
+
+	for range 3 {
+		log.Error("failed login")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(buf.String(), "2 similar messages suppressed") {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %s, want summary without further records", buf.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLoggerCloseFlushesSummaries(t *testing.T) {
+	var buf bytes.Buffer
+	l := Setup(EnvProd, WithOutput(&buf), WithSampling(time.Hour, 1))
+
+	l.Warn("rate limit exceeded")
+	l.Warn("rate limit exceeded")
+	if strings.Contains(buf.String(), "suppressed") {
+		t.Fatalf("got summary before the window closed: %s", buf.String())
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "1 similar messages suppressed") {
+		t.Errorf("got %s, want summary on Close", buf.String())
+	}
+}
+
+// blockingWriter не пишет, пока не закрыт release.
+type blockingWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func TestAsyncWriter(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	a := NewAsyncWriter(w, 2)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// первая запись забрана горутиной и ждёт, две в буфере, остальные теряются
+		for range 10 {
+			_, _ = io.WriteString(a, "line\n")
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Write blocked on a stuck writer")
+	}
+
+	if a.Dropped() < 7 {
+		t.Errorf("got dropped %d, want at least 7", a.Dropped())
+	}
+
+	close(w.release)
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	written := strings.Count(w.buf.String(), "line")
+	if uint64(written)+a.Dropped() != 10 {
+		t.Errorf("got written %d + dropped %d, want 10", written, a.Dropped())
+	}
+
+	// после Close запись синхронная
+	_, _ = io.WriteString(a, "after close\n")
+	if !strings.Contains(w.buf.String(), "after close") {
+		t.Error("got no synchronous write after Close")
+	}
+}
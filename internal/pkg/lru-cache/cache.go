@@ -0,0 +1,133 @@
+// Package lrucache provides a thread-safe in-memory LRU cache with per-entry TTL.
+package lrucache
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+type Cache[K comparable, V any] struct {
+	mu    sync.Mutex
+	cfg   config
+	ll    *list.List
+	items map[K]*list.Element
+}
+
+func New[K comparable, V any](opts ...Option) (*Cache[K, V], error) {
+	cfg := defaultConfig()
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := cfg.valid(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &Cache[K, V]{
+		cfg:   cfg,
+		ll:    list.New(),
+		items: make(map[K]*list.Element, cfg.capacity),
+	}, nil
+}
+
+// Get returns the value for key if it is present and not expired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := el.Value.(*entry[K, V])
+	if c.expired(e) {
+		c.removeElement(el)
+		return zero, false
+	}
+
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores value with the default TTL.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.cfg.ttl)
+}
+
+// SetWithTTL stores value with the given TTL. Zero TTL means no expiration.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.cfg.now().Add(ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value = value
+		e.expiresAt = expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&entry[K, V]{
+		key:       key,
+		value:     value,
+		expiresAt: expiresAt,
+	})
+	c.items[key] = el
+
+	// вытесняем самый давно использованный элемент
+	if c.ll.Len() > c.cfg.capacity {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// Purge removes all entries.
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ll.Init()
+	clear(c.items)
+}
+
+// Len returns the number of entries, including expired ones not yet evicted.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len()
+}
+
+func (c *Cache[K, V]) expired(e *entry[K, V]) bool {
+	return !e.expiresAt.IsZero() && !c.cfg.now().Before(e.expiresAt)
+}
+
+func (c *Cache[K, V]) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,38 @@
+package lrucache
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultCapacity = 1024
+	defaultTTL      = time.Minute
+)
+
+type config struct {
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+func defaultConfig() config {
+	return config{
+		capacity: defaultCapacity,
+		ttl:      defaultTTL,
+		now:      time.Now,
+	}
+}
+
+func (c config) valid() error {
+	if c.capacity < 1 {
+		return errors.New("capacity must be >= 1")
+	}
+	if c.ttl < 0 {
+		return errors.New("ttl must be >= 0")
+	}
+	if c.now == nil {
+		return errors.New("clock is required")
+	}
+	return nil
+}
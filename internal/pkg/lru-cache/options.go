@@ -0,0 +1,24 @@
+package lrucache
+
+import "time"
+
+type Option func(*config)
+
+func WithCapacity(capacity int) Option {
+	return func(c *config) {
+		c.capacity = capacity
+	}
+}
+
+// WithTTL sets the default entry lifetime. Zero disables expiration.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(c *config) {
+		c.now = now
+	}
+}
@@ -0,0 +1,120 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/testclock"
+)
+
+func newTestCache(t *testing.T, opts ...Option) (*Cache[string, int], *testclock.Clock) {
+	t.Helper()
+
+	clock := testclock.New()
+	c, err := New[string, int](append([]Option{WithClock(clock.Now)}, opts...)...)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	return c, clock
+}
+
+func TestGetSet(t *testing.T) {
+	c, _ := newTestCache(t)
+
+	if _, ok := c.Get("missing"); ok {
+		t.Error("expected miss for absent key")
+	}
+
+	c.Set("a", 1)
+	c.Set("a", 2)
+
+	got, ok := c.Get("a")
+	if !ok || got != 2 {
+		t.Errorf("got (%d, %v), want (2, true)", got, ok)
+	}
+	if c.Len() != 1 {
+		t.Errorf("got len %d, want 1", c.Len())
+	}
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c, _ := newTestCache(t, WithCapacity(2))
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // "b" становится самым старым
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Error("expected a to survive")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Error("expected c to be present")
+	}
+}
+
+func TestExpiration(t *testing.T) {
+	c, clock := newTestCache(t, WithTTL(10*time.Second))
+
+	c.Set("default", 1)
+	c.SetWithTTL("short", 2, time.Second)
+	c.SetWithTTL("forever", 3, 0)
+
+	clock.Advance(time.Second)
+	if _, ok := c.Get("short"); ok {
+		t.Error("expected short to expire")
+	}
+	if _, ok := c.Get("default"); !ok {
+		t.Error("expected default to be present")
+	}
+
+	clock.Advance(time.Hour)
+	if _, ok := c.Get("default"); ok {
+		t.Error("expected default to expire")
+	}
+	if _, ok := c.Get("forever"); !ok {
+		t.Error("expected entry without ttl to be present")
+	}
+}
+
+func TestDeleteAndPurge(t *testing.T) {
+	c, _ := newTestCache(t)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	c.Delete("a")
+	if _, ok := c.Get("a"); ok {
+		t.Error("expected a to be deleted")
+	}
+
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("got len %d after purge, want 0", c.Len())
+	}
+}
+
+func TestConfigValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{"defaults", nil, false},
+		{"zero capacity", []Option{WithCapacity(0)}, true},
+		{"negative ttl", []Option{WithTTL(-time.Second)}, true},
+		{"nil clock", []Option{WithClock(nil)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New[string, int](tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	ratelimiterv1 "github.com/Krokozabra213/schools_backend/internal/pkg/rate-limiter/v1"
+)
+
+const resultSuccess = "success"
+
+// loginFailureReasons — причины отказа во входе (business), их серии
+// создаются нулевыми вместе с успешными.
+var loginFailureReasons = []string{"invalid_credentials", "user_locked", "internal"}
+
+// ObserveRateLimit реализует ratelimiterv1.Observer:
+//
+//	cfg.SetObserver(m)
+func (m *Metrics) ObserveRateLimit(method, rule string, outcome ratelimiterv1.Outcome) {
+	m.rateLimitDecisions.WithLabelValues(method, rule, string(outcome)).Inc()
+}
+
+// UserRegistered и остальные методы ниже реализуют business.Metrics.
+func (m *Metrics) UserRegistered() {
+	m.registrations.WithLabelValues(resultSuccess).Inc()
+}
+
+// RegistrationFailed — reason из небольшого фиксированного набора
+// (email_exists, user_exists, internal), чтобы не раздувать кардинальность.
+func (m *Metrics) RegistrationFailed(reason string) {
+	m.registrations.WithLabelValues(reason).Inc()
+}
+
+// LoginSucceeded и LoginFailed пока не вызываются: в бизнес-слое ещё нет Login.
+func (m *Metrics) LoginSucceeded() {
+	m.logins.WithLabelValues(resultSuccess).Inc()
+}
+
+func (m *Metrics) LoginFailed(reason string) {
+	m.logins.WithLabelValues(reason).Inc()
+}
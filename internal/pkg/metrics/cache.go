@@ -0,0 +1,38 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// CacheStats — счётчики обращений к кешу (business.CacheStats приводится
+// к этому типу напрямую).
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Errors uint64
+}
+
+// RegisterCacheStats регистрирует счётчики кеша с label cache=name.
+// stats вызывается в момент скрейпа.
+func (m *Metrics) RegisterCacheStats(name string, stats func() CacheStats) error {
+	counter := func(metric, help string, value func(CacheStats) uint64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   m.cfg.namespace,
+			Subsystem:   "cache",
+			Name:        metric,
+			Help:        help,
+			ConstLabels: prometheus.Labels{"cache": name},
+		}, func() float64 { return float64(value(stats())) })
+	}
+
+	collectors := []prometheus.Collector{
+		counter("hits_total", "Cache lookups served from the cache.", func(s CacheStats) uint64 { return s.Hits }),
+		counter("misses_total", "Cache lookups that went to the source.", func(s CacheStats) uint64 { return s.Misses }),
+		counter("errors_total", "Failed cache reads, writes and invalidations.", func(s CacheStats) uint64 { return s.Errors }),
+	}
+
+	for _, c := range collectors {
+		if err := m.registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	namespace      string
+	buckets        []float64
+	runtimeMetrics bool
+}
+
+func defaultConfig() config {
+	return config{
+		buckets:        prometheus.DefBuckets,
+		runtimeMetrics: true,
+	}
+}
+
+func (c config) valid() error {
+	if len(c.buckets) == 0 {
+		return errors.New("buckets must not be empty")
+	}
+	for i := 1; i < len(c.buckets); i++ {
+		if c.buckets[i] <= c.buckets[i-1] {
+			return errors.New("buckets must be sorted in increasing order")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor считает запросы и латентность по методу и коду ответа.
+// Ставится первым в цепочке, чтобы учитывать и отказы rate limiter и auth.
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observeGRPC(info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor — то же для стримов; латентность — время жизни стрима.
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observeGRPC(info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+func (m *Metrics) observeGRPC(method string, err error, elapsed time.Duration) {
+	code := status.Code(err).String()
+
+	m.grpcRequests.WithLabelValues(method, code).Inc()
+	m.grpcDuration.WithLabelValues(method, code).Observe(elapsed.Seconds())
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// LogStater — счётчики потерянных записей логгера (logger.Logger).
+type LogStater interface {
+	Dropped() uint64
+	Suppressed() uint64
+}
+
+// RegisterLogStats регистрирует счётчики записей, отброшенных асинхронным
+// буфером и подавленных семплированием. Читаются в момент скрейпа.
+func (m *Metrics) RegisterLogStats(stats LogStater) error {
+	collectors := []prometheus.Collector{
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: m.cfg.namespace,
+			Subsystem: "log",
+			Name:      "dropped_total",
+			Help:      "Log records dropped because the async buffer was full.",
+		}, func() float64 { return float64(stats.Dropped()) }),
+
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: m.cfg.namespace,
+			Subsystem: "log",
+			Name:      "suppressed_total",
+			Help:      "Log records suppressed by sampling of identical messages.",
+		}, func() float64 { return float64(stats.Suppressed()) }),
+	}
+
+	for _, c := range collectors {
+		if err := m.registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics — метрики сервиса в собственном реестре (не в глобальном
+// prometheus.DefaultRegisterer), чтобы тесты и несколько инстансов не конфликтовали.
+type Metrics struct {
+	cfg      config
+	registry *prometheus.Registry
+
+	grpcRequests *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+
+	rateLimitDecisions *prometheus.CounterVec
+
+	registrations *prometheus.CounterVec
+	logins        *prometheus.CounterVec
+}
+
+func New(opts ...Option) (*Metrics, error) {
+	cfg := defaultConfig()
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := cfg.valid(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	m := &Metrics{
+		cfg:      cfg,
+		registry: prometheus.NewRegistry(),
+
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Subsystem: "grpc",
+			Name:      "requests_total",
+			Help:      "Total number of gRPC requests by method and status code.",
+		}, []string{"method", "code"}),
+
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.namespace,
+			Subsystem: "grpc",
+			Name:      "request_duration_seconds",
+			Help:      "gRPC request latency by method and status code.",
+			Buckets:   cfg.buckets,
+		}, []string{"method", "code"}),
+
+		rateLimitDecisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Subsystem: "ratelimit",
+			Name:      "decisions_total",
+			Help:      "Rate limiter decisions by method, rule and outcome (allowed, denied, error).",
+		}, []string{"method", "rule", "outcome"}),
+
+		registrations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Subsystem: "business",
+			Name:      "registrations_total",
+			Help:      "User registrations by result.",
+		}, []string{"result"}),
+
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Subsystem: "business",
+			Name:      "logins_total",
+			Help:      "Login attempts by result.",
+		}, []string{"result"}),
+	}
+
+	m.registry.MustRegister(
+		m.grpcRequests,
+		m.grpcDuration,
+		m.rateLimitDecisions,
+		m.registrations,
+		m.logins,
+	)
+
+	// нулевые серии: дашборды и алерты по входам работают до первой попытки
+	m.logins.WithLabelValues(resultSuccess)
+	for _, reason := range loginFailureReasons {
+		m.logins.WithLabelValues(reason)
+	}
+
+	if cfg.runtimeMetrics {
+		m.registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+
+	return m, nil
+}
+
+// Registry возвращает реестр для регистрации дополнительных коллекторов.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler отдаёт метрики в формате Prometheus, монтируется на /metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		Registry: m.registry,
+	})
+}
@@ -0,0 +1,24 @@
+package metrics
+
+type Option func(*config)
+
+// WithNamespace задаёт префикс всех метрик, например "sso".
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets задаёт границы гистограмм латентности (в секундах).
+func WithBuckets(buckets ...float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// WithRuntimeMetrics включает go_* и process_* метрики (по умолчанию включены).
+func WithRuntimeMetrics(enabled bool) Option {
+	return func(c *config) {
+		c.runtimeMetrics = enabled
+	}
+}
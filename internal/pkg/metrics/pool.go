@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// PgxPoolStater — источник статистики пула Postgres (pgxclient.Client.Pool()).
+type PgxPoolStater interface {
+	Stat() *pgxpool.Stat
+}
+
+// RedisPoolStater — источник статистики пула Redis (gorediscli.Client).
+type RedisPoolStater interface {
+	PoolStats() *redis.PoolStats
+}
+
+// RegisterPgxPool регистрирует метрики пула Postgres. name попадает в label
+// pool и различает пулы, например "primary" и "replica".
+// Статистика читается в момент скрейпа.
+func (m *Metrics) RegisterPgxPool(name string, pool PgxPoolStater) error {
+	return m.registry.Register(newPgxPoolCollector(m.cfg.namespace, name, pool))
+}
+
+// RegisterRedisPool регистрирует метрики пула Redis.
+func (m *Metrics) RegisterRedisPool(name string, pool RedisPoolStater) error {
+	return m.registry.Register(newRedisPoolCollector(m.cfg.namespace, name, pool))
+}
+
+// constMetric — метрика коллектора, значение которой читается из
+// источника в момент скрейпа.
+type constMetric struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+}
+
+func newConstMetric(namespace, subsystem, name, help string, valueType prometheus.ValueType,
+	variableLabels []string, constLabels prometheus.Labels,
+) constMetric {
+	return constMetric{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, name),
+			help, variableLabels, constLabels,
+		),
+		valueType: valueType,
+	}
+}
+
+func (m constMetric) collect(ch chan<- prometheus.Metric, value float64, labelValues ...string) {
+	ch <- prometheus.MustNewConstMetric(m.desc, m.valueType, value, labelValues...)
+}
+
+func describe(ch chan<- *prometheus.Desc, metrics []constMetric) {
+	for _, m := range metrics {
+		ch <- m.desc
+	}
+}
+
+func newPoolMetric(namespace, subsystem, pool, name, help string, valueType prometheus.ValueType) constMetric {
+	return newConstMetric(namespace, subsystem, name, help, valueType, nil, prometheus.Labels{"pool": pool})
+}
+
+type pgxPoolCollector struct {
+	pool PgxPoolStater
+
+	acquiredConns     constMetric
+	idleConns         constMetric
+	totalConns        constMetric
+	maxConns          constMetric
+	constructingConns constMetric
+	acquireCount      constMetric
+	acquireDuration   constMetric
+	emptyAcquireCount constMetric
+	canceledAcquire   constMetric
+	newConnsCount     constMetric
+	lifetimeDestroy   constMetric
+	idleDestroy       constMetric
+}
+
+func newPgxPoolCollector(namespace, name string, pool PgxPoolStater) *pgxPoolCollector {
+	const subsystem = "pgx_pool"
+
+	return &pgxPoolCollector{
+		pool: pool,
+
+		acquiredConns:     newPoolMetric(namespace, subsystem, name, "acquired_conns", "Currently acquired connections.", prometheus.GaugeValue),
+		idleConns:         newPoolMetric(namespace, subsystem, name, "idle_conns", "Currently idle connections.", prometheus.GaugeValue),
+		totalConns:        newPoolMetric(namespace, subsystem, name, "total_conns", "Total connections in the pool.", prometheus.GaugeValue),
+		maxConns:          newPoolMetric(namespace, subsystem, name, "max_conns", "Maximum pool size.", prometheus.GaugeValue),
+		constructingConns: newPoolMetric(namespace, subsystem, name, "constructing_conns", "Connections being established.", prometheus.GaugeValue),
+		acquireCount:      newPoolMetric(namespace, subsystem, name, "acquire_total", "Successful acquires from the pool.", prometheus.CounterValue),
+		acquireDuration:   newPoolMetric(namespace, subsystem, name, "acquire_duration_seconds_total", "Total time spent acquiring connections.", prometheus.CounterValue),
+		emptyAcquireCount: newPoolMetric(namespace, subsystem, name, "empty_acquire_total", "Acquires that had to wait for a connection.", prometheus.CounterValue),
+		canceledAcquire:   newPoolMetric(namespace, subsystem, name, "canceled_acquire_total", "Acquires canceled by context.", prometheus.CounterValue),
+		newConnsCount:     newPoolMetric(namespace, subsystem, name, "new_conns_total", "New connections opened.", prometheus.CounterValue),
+		lifetimeDestroy:   newPoolMetric(namespace, subsystem, name, "max_lifetime_destroy_total", "Connections closed due to MaxConnLifetime.", prometheus.CounterValue),
+		idleDestroy:       newPoolMetric(namespace, subsystem, name, "max_idle_destroy_total", "Connections closed due to MaxConnIdleTime.", prometheus.CounterValue),
+	}
+}
+
+func (c *pgxPoolCollector) metrics() []constMetric {
+	return []constMetric{
+		c.acquiredConns, c.idleConns, c.totalConns, c.maxConns, c.constructingConns,
+		c.acquireCount, c.acquireDuration, c.emptyAcquireCount, c.canceledAcquire,
+		c.newConnsCount, c.lifetimeDestroy, c.idleDestroy,
+	}
+}
+
+func (c *pgxPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	describe(ch, c.metrics())
+}
+
+func (c *pgxPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+
+	values := []float64{
+		float64(s.AcquiredConns()),
+		float64(s.IdleConns()),
+		float64(s.TotalConns()),
+		float64(s.MaxConns()),
+		float64(s.ConstructingConns()),
+		float64(s.AcquireCount()),
+		s.AcquireDuration().Seconds(),
+		float64(s.EmptyAcquireCount()),
+		float64(s.CanceledAcquireCount()),
+		float64(s.NewConnsCount()),
+		float64(s.MaxLifetimeDestroyCount()),
+		float64(s.MaxIdleDestroyCount()),
+	}
+
+	for i, m := range c.metrics() {
+		m.collect(ch, values[i])
+	}
+}
+
+type redisPoolCollector struct {
+	pool RedisPoolStater
+
+	hits       constMetric
+	misses     constMetric
+	timeouts   constMetric
+	totalConns constMetric
+	idleConns  constMetric
+	staleConns constMetric
+}
+
+func newRedisPoolCollector(namespace, name string, pool RedisPoolStater) *redisPoolCollector {
+	const subsystem = "redis_pool"
+
+	return &redisPoolCollector{
+		pool: pool,
+
+		hits:       newPoolMetric(namespace, subsystem, name, "hits_total", "Free connection found in the pool.", prometheus.CounterValue),
+		misses:     newPoolMetric(namespace, subsystem, name, "misses_total", "Free connection not found in the pool.", prometheus.CounterValue),
+		timeouts:   newPoolMetric(namespace, subsystem, name, "timeouts_total", "Wait timeouts while acquiring a connection.", prometheus.CounterValue),
+		totalConns: newPoolMetric(namespace, subsystem, name, "total_conns", "Total connections in the pool.", prometheus.GaugeValue),
+		idleConns:  newPoolMetric(namespace, subsystem, name, "idle_conns", "Idle connections in the pool.", prometheus.GaugeValue),
+		staleConns: newPoolMetric(namespace, subsystem, name, "stale_conns_total", "Stale connections removed from the pool.", prometheus.CounterValue),
+	}
+}
+
+func (c *redisPoolCollector) metrics() []constMetric {
+	return []constMetric{c.hits, c.misses, c.timeouts, c.totalConns, c.idleConns, c.staleConns}
+}
+
+func (c *redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	describe(ch, c.metrics())
+}
+
+func (c *redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.PoolStats()
+
+	values := []float64{
+		float64(s.Hits),
+		float64(s.Misses),
+		float64(s.Timeouts),
+		float64(s.TotalConns),
+		float64(s.IdleConns),
+		float64(s.StaleConns),
+	}
+
+	for i, m := range c.metrics() {
+		m.collect(ch, values[i])
+	}
+}
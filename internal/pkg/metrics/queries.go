@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	pgxclient "github.com/Krokozabra213/schools_backend/internal/pkg/pgx-client"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PgxQueryStater — источник статистики запросов (pgxclient.Client с WithQueryStats).
+type PgxQueryStater interface {
+	QueryStats() []pgxclient.QueryStat
+}
+
+// RegisterPgxQueries регистрирует метрики запросов Postgres с label query —
+// имя запроса sqlc. Статистика читается в момент скрейпа.
+func (m *Metrics) RegisterPgxQueries(src PgxQueryStater) error {
+	return m.registry.Register(newPgxQueryCollector(m.cfg.namespace, src))
+}
+
+func newQueryMetric(namespace, name, help string, valueType prometheus.ValueType) constMetric {
+	return newConstMetric(namespace, "pgx_query", name, help, valueType, []string{"query"}, nil)
+}
+
+type pgxQueryCollector struct {
+	src PgxQueryStater
+
+	count       constMetric
+	errors      constMetric
+	slow        constMetric
+	duration    constMetric
+	maxDuration constMetric
+}
+
+func newPgxQueryCollector(namespace string, src PgxQueryStater) *pgxQueryCollector {
+	return &pgxQueryCollector{
+		src: src,
+
+		count:       newQueryMetric(namespace, "total", "Executed queries.", prometheus.CounterValue),
+		errors:      newQueryMetric(namespace, "errors_total", "Failed queries, pgx.ErrNoRows excluded.", prometheus.CounterValue),
+		slow:        newQueryMetric(namespace, "slow_total", "Queries slower than the slow query threshold.", prometheus.CounterValue),
+		duration:    newQueryMetric(namespace, "duration_seconds_total", "Total time spent in queries.", prometheus.CounterValue),
+		maxDuration: newQueryMetric(namespace, "duration_max_seconds", "Slowest execution since start.", prometheus.GaugeValue),
+	}
+}
+
+func (c *pgxQueryCollector) metrics() []constMetric {
+	return []constMetric{c.count, c.errors, c.slow, c.duration, c.maxDuration}
+}
+
+func (c *pgxQueryCollector) Describe(ch chan<- *prometheus.Desc) {
+	describe(ch, c.metrics())
+}
+
+func (c *pgxQueryCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c.src.QueryStats() {
+		values := []float64{
+			float64(s.Count),
+			float64(s.Errors),
+			float64(s.Slow),
+			s.Total.Seconds(),
+			s.Max.Seconds(),
+		}
+
+		for i, m := range c.metrics() {
+			m.collect(ch, values[i], s.Name)
+		}
+	}
+}
@@ -2,7 +2,11 @@ package pgxclient
 
 import (
 	"errors"
+	"log/slog"
 	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/resilience"
+	"github.com/jackc/pgx/v5"
 )
 
 const (
@@ -15,6 +19,9 @@ const (
 	defaultMinConns        = 2
 	defaultMaxConnLifeTime = 2 * time.Hour
 	defaultMaxConnIdleTime = 15 * time.Minute
+
+	defaultReplicaMaxLag        = 10 * time.Second
+	defaultReplicaCheckInterval = 5 * time.Second
 )
 
 type config struct {
@@ -24,11 +31,27 @@ type config struct {
 	password        *string
 	database        string
 	sslMode         string
+	sslRootCert     string
+	sslCert         string
+	sslKey          string
+	sslServerName   string
 	connectTimeout  time.Duration
 	maxConns        int32
 	minConns        int32
 	maxConnLifeTime time.Duration
 	maxConnIdleTime time.Duration
+	queryTracers    []pgx.QueryTracer
+
+	replicas             []string
+	replicaMaxLag        time.Duration
+	replicaCheckInterval time.Duration
+	log                  *slog.Logger
+
+	useResilience bool
+	resilience    []resilience.Option
+
+	useQueryStats      bool
+	slowQueryThreshold time.Duration
 }
 
 func defaultConfig() config {
@@ -42,6 +65,10 @@ func defaultConfig() config {
 		maxConnIdleTime: defaultMaxConnIdleTime,
 		maxConns:        defaultMaxConns,
 		minConns:        defaultMinConns,
+
+		replicaMaxLag:        defaultReplicaMaxLag,
+		replicaCheckInterval: defaultReplicaCheckInterval,
+		log:                  slog.Default(),
 	}
 }
 
@@ -64,5 +91,21 @@ func (c config) valid() error {
 	if c.minConns > c.maxConns {
 		return errors.New("minConns must be <= maxConns")
 	}
+	for _, r := range c.replicas {
+		if r == "" {
+			return errors.New("replica address must not be empty")
+		}
+	}
+	if len(c.replicas) > 0 && c.replicaMaxLag <= 0 {
+		return errors.New("replicaMaxLag must be > 0")
+	}
+	if len(c.replicas) > 0 && c.replicaCheckInterval <= 0 {
+		return errors.New("replicaCheckInterval must be > 0")
+	}
+	for _, t := range c.queryTracers {
+		if t == nil {
+			return errors.New("query tracer must not be nil")
+		}
+	}
 	return nil
 }
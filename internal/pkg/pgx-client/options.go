@@ -1,6 +1,13 @@
 package pgxclient
 
-import "time"
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/resilience"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/trace"
+)
 
 type Option func(*config)
 
@@ -34,12 +41,39 @@ func WithDatabase(database string) Option {
 	}
 }
 
+// WithSSL задаёт sslmode в смысле libpq: disable, allow, prefer, require,
+// verify-ca или verify-full.
 func WithSSL(sslmode string) Option {
 	return func(c *config) {
 		c.sslMode = sslmode
 	}
 }
 
+// WithSSLRootCert задаёт CA bundle (PEM) для проверки сервера вместо
+// системных корневых сертификатов. Как и в libpq, с ним require
+// проверяет цепочку (как verify-ca).
+func WithSSLRootCert(path string) Option {
+	return func(c *config) {
+		c.sslRootCert = path
+	}
+}
+
+// WithSSLCert задаёт клиентский сертификат и ключ (PEM) для mTLS.
+func WithSSLCert(certPath, keyPath string) Option {
+	return func(c *config) {
+		c.sslCert = certPath
+		c.sslKey = keyPath
+	}
+}
+
+// WithSSLServerName задаёт имя для SNI и проверки verify-full, если оно
+// отличается от хоста подключения (IP, прокси, балансировщик).
+func WithSSLServerName(name string) Option {
+	return func(c *config) {
+		c.sslServerName = name
+	}
+}
+
 func WithConnectionTimeout(timeout time.Duration) Option {
 	return func(c *config) {
 		c.connectTimeout = timeout
@@ -69,3 +103,66 @@ func WithMaxConnIdletime(idletime time.Duration) Option {
 		c.maxConnIdleTime = idletime
 	}
 }
+
+// WithQueryTracer добавляет pgx.QueryTracer; несколько трассировщиков
+// вызываются по очереди в порядке добавления.
+func WithQueryTracer(tracer pgx.QueryTracer) Option {
+	return func(c *config) {
+		c.queryTracers = append(c.queryTracers, tracer)
+	}
+}
+
+// WithTracerProvider включает OpenTelemetry спаны на каждый запрос.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return WithQueryTracer(NewOTelQueryTracer(tp))
+}
+
+// WithQueryStats включает статистику времени запросов по именам sqlc
+// (Client.QueryStats) и лог запросов не быстрее threshold через WithLogger;
+// threshold <= 0 — только статистика. Общая для primary и реплик.
+func WithQueryStats(threshold time.Duration) Option {
+	return func(c *config) {
+		c.useQueryStats = true
+		c.slowQueryThreshold = threshold
+	}
+}
+
+// WithReplicas добавляет реплики для чтения: "host" (порт primary) или
+// "host:port". Учётные данные, база и настройки пула — как у primary.
+func WithReplicas(addrs ...string) Option {
+	return func(c *config) {
+		c.replicas = append(c.replicas, addrs...)
+	}
+}
+
+// WithReplicaMaxLag задаёт отставание, после которого реплика исключается из чтения.
+func WithReplicaMaxLag(lag time.Duration) Option {
+	return func(c *config) {
+		c.replicaMaxLag = lag
+	}
+}
+
+// WithReplicaCheckInterval задаёт период фоновой проверки реплик.
+func WithReplicaCheckInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.replicaCheckInterval = interval
+	}
+}
+
+// WithLogger задаёт логгер для событий клиента (исключение реплик).
+func WithLogger(log *slog.Logger) Option {
+	return func(c *config) {
+		c.log = log
+	}
+}
+
+// WithResilience включает повторы при временных сбоях и автомат для
+// запросов вне транзакций; у primary и каждой реплики свой автомат.
+// Чтения через Reader и Begin повторяются всегда, остальное — только
+// безопасное (см. IsRetryable) или помеченное resilience.ContextWithIdempotent.
+func WithResilience(opts ...resilience.Option) Option {
+	return func(c *config) {
+		c.useResilience = true
+		c.resilience = append(c.resilience, opts...)
+	}
+}
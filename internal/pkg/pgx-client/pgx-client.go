@@ -2,9 +2,12 @@ package pgxclient
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
+	"strings"
+	"sync/atomic"
 
+	"github.com/Krokozabra213/schools_backend/internal/pkg/resilience"
+	tlsconfig "github.com/Krokozabra213/schools_backend/internal/pkg/tls-config"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -17,13 +20,25 @@ type Database interface {
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
 	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
 	Ping(ctx context.Context) error
 	Pool() *pgxpool.Pool
 	Close()
 }
 
+// Client — пул primary и, если заданы WithReplicas, пулы реплик.
+// Методы Database всегда работают с primary; чтения с реплик идут через Reader.
+// С WithResilience запросы вне транзакций проходят через политику повторов.
 type Client struct {
-	pool *pgxpool.Pool
+	cfg    config
+	pool   *pgxpool.Pool
+	policy *resilience.Policy
+	stats  *QueryStats
+
+	replicas  []*replica
+	next      atomic.Uint64
+	stopWatch context.CancelFunc
+	watchDone chan struct{}
 }
 
 func New(ctx context.Context, opts ...Option) (*Client, error) {
@@ -37,6 +52,17 @@ func New(ctx context.Context, opts ...Option) (*Client, error) {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
+	policy, err := newPolicy(&cfg, "primary")
+	if err != nil {
+		return nil, fmt.Errorf("resilience: %w", err)
+	}
+
+	var stats *QueryStats
+	if cfg.useQueryStats {
+		stats = NewQueryStats(cfg.log, cfg.slowQueryThreshold)
+		cfg.queryTracers = append(cfg.queryTracers, stats)
+	}
+
 	poolCfg, err := createPGXConfig(&cfg)
 	if err != nil {
 		return nil, fmt.Errorf("parse config: %w", err)
@@ -57,9 +83,30 @@ func New(ctx context.Context, opts ...Option) (*Client, error) {
 		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
-	return &Client{
-		pool: pool,
-	}, nil
+	c := &Client{
+		cfg:    cfg,
+		pool:   pool,
+		policy: policy,
+		stats:  stats,
+	}
+
+	if len(cfg.replicas) > 0 {
+		c.replicas, err = newReplicas(ctx, &cfg)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("create replica pool: %w", err)
+		}
+
+		// первая проверка синхронно, чтобы чтения сразу шли на реплики
+		c.checkReplicas(ctx)
+
+		watchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c.stopWatch = cancel
+		c.watchDone = make(chan struct{})
+		go c.watchReplicas(watchCtx)
+	}
+
+	return c, nil
 }
 
 func createPGXConfig(cfg *config) (*pgxpool.Config, error) {
@@ -74,7 +121,7 @@ func createPGXConfig(cfg *config) (*pgxpool.Config, error) {
 	pgxConf.ConnConfig.Password = *cfg.password
 	pgxConf.ConnConfig.Database = cfg.database
 
-	if err := configureTLS(pgxConf.ConnConfig, cfg.sslMode); err != nil {
+	if err := configureTLS(pgxConf.ConnConfig, cfg); err != nil {
 		return nil, fmt.Errorf("configure TLS: %w", err)
 	}
 
@@ -82,28 +129,84 @@ func createPGXConfig(cfg *config) (*pgxpool.Config, error) {
 	pgxConf.MinConns = cfg.minConns
 	pgxConf.MaxConnLifetime = cfg.maxConnLifeTime
 	pgxConf.MaxConnIdleTime = cfg.maxConnIdleTime
+	pgxConf.ConnConfig.Tracer = queryTracer(cfg.queryTracers)
 
 	return pgxConf, nil
 }
 
-func configureTLS(connConfig *pgx.ConnConfig, sslMode string) error {
-	switch sslMode {
-	case "disable":
+// configureTLS повторяет sslmode libpq:
+//
+//	disable     — без TLS
+//	allow       — без TLS, при отказе сервера — TLS без проверки
+//	prefer      — TLS без проверки, при отказе сервера — без TLS
+//	require     — TLS без проверки; с sslRootCert — как verify-ca
+//	verify-ca   — TLS, цепочка до доверенного CA
+//	verify-full — TLS, цепочка и имя хоста
+func configureTLS(connConfig *pgx.ConnConfig, cfg *config) error {
+	// ParseConfig("") оставляет запасные подключения к хосту по умолчанию
+	// (localhost), они нам не нужны ни в одном режиме
+	connConfig.Fallbacks = nil
+
+	if cfg.sslMode == "disable" {
 		connConfig.TLSConfig = nil
+		return nil
+	}
 
+	verify := tlsconfig.VerifyNone
+	switch cfg.sslMode {
+	case "allow", "prefer":
 	case "require":
-		connConfig.TLSConfig = &tls.Config{
-			InsecureSkipVerify: true, // шифрование есть, проверки сертификата нет
+		if cfg.sslRootCert != "" {
+			verify = tlsconfig.VerifyCA
 		}
+	case "verify-ca":
+		verify = tlsconfig.VerifyCA
+	case "verify-full":
+		verify = tlsconfig.VerifyFull
+	default:
+		return fmt.Errorf("unknown sslmode: %s", cfg.sslMode)
+	}
 
+	serverName := cfg.sslServerName
+	if serverName == "" && !strings.HasPrefix(connConfig.Host, "/") {
+		serverName = connConfig.Host
+	}
+
+	tlsCfg, err := tlsconfig.Config{
+		Verify:     verify,
+		CAFile:     cfg.sslRootCert,
+		CertFile:   cfg.sslCert,
+		KeyFile:    cfg.sslKey,
+		ServerName: serverName,
+	}.Build()
+	if err != nil {
+		return err
+	}
+
+	switch cfg.sslMode {
+	case "allow":
+		connConfig.TLSConfig = nil
+		connConfig.Fallbacks = []*pgconn.FallbackConfig{
+			{Host: connConfig.Host, Port: connConfig.Port, TLSConfig: tlsCfg},
+		}
+	case "prefer":
+		connConfig.TLSConfig = tlsCfg
+		connConfig.Fallbacks = []*pgconn.FallbackConfig{
+			{Host: connConfig.Host, Port: connConfig.Port},
+		}
 	default:
-		return fmt.Errorf("unknown sslmode: %s", sslMode)
+		connConfig.TLSConfig = tlsCfg
 	}
 
 	return nil
 }
 
 func (c *Client) Close() {
+	if c.stopWatch != nil {
+		c.stopWatch()
+		<-c.watchDone
+	}
+	closeReplicas(c.replicas)
 	c.pool.Close()
 }
 
@@ -112,21 +215,53 @@ func (c *Client) Pool() *pgxpool.Pool {
 }
 
 func (c *Client) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
-	return c.pool.Query(ctx, sql, args...)
+	return guard(c.pool, c.policy, false).Query(ctx, sql, args...)
 }
 
 func (c *Client) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
-	return c.pool.Exec(ctx, sql, args...)
+	return guard(c.pool, c.policy, false).Exec(ctx, sql, args...)
 }
 
 func (c *Client) Begin(ctx context.Context) (pgx.Tx, error) {
-	return c.pool.Begin(ctx)
+	return c.BeginTx(ctx, pgx.TxOptions{})
 }
 
+// BeginTx повторяется при временных сбоях: неудачный BEGIN транзакцию
+// не открывает. Запросы внутри транзакции не повторяются — после сбоя
+// повторять нужно её целиком (TxManager).
+func (c *Client) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	if c.policy == nil {
+		return c.pool.BeginTx(ctx, txOptions)
+	}
+
+	var tx pgx.Tx
+	err := c.policy.Do(ctx, true, func(ctx context.Context) error {
+		var err error
+		tx, err = c.pool.BeginTx(ctx, txOptions)
+		return err
+	})
+	return tx, err
+}
+
+// Ping идёт мимо политики: проверкам здоровья нужен честный ответ.
 func (c *Client) Ping(ctx context.Context) error {
 	return c.pool.Ping(ctx)
 }
 
 func (c *Client) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
-	return c.pool.QueryRow(ctx, sql, args...)
+	return guard(c.pool, c.policy, false).QueryRow(ctx, sql, args...)
+}
+
+// Resilience возвращает политику primary или nil без WithResilience.
+func (c *Client) Resilience() *resilience.Policy {
+	return c.policy
+}
+
+// QueryStats возвращает статистику запросов primary и реплик, самые
+// затратные первыми; nil без WithQueryStats.
+func (c *Client) QueryStats() []QueryStat {
+	if c.stats == nil {
+		return nil
+	}
+	return c.stats.Snapshot()
 }
@@ -0,0 +1,167 @@
+package pgxclient
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxLoggedSQL — сколько символов SQL без имени sqlc попадает в лог.
+const maxLoggedSQL = 200
+
+// QueryStat — накопленная статистика запроса с одним именем.
+type QueryStat struct {
+	// Name — имя запроса sqlc, иначе SQL-операция (SELECT, BEGIN...).
+	Name   string
+	Count  uint64
+	Errors uint64
+	// Slow — сколько выполнений было не быстрее порога медленного запроса.
+	Slow  uint64
+	Total time.Duration
+	Max   time.Duration
+}
+
+// Mean возвращает среднее время выполнения.
+func (s QueryStat) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	at   time.Time
+	name string
+	// sql — текст запроса без имени sqlc, иначе пусто
+	sql  string
+	args []any
+}
+
+// QueryStats — pgx.QueryTracer, который считает время запросов по именам
+// sqlc и пишет в лог запросы дольше порога. Значения аргументов в лог не
+// попадают: там могут быть пароли и персональные данные, только их типы.
+type QueryStats struct {
+	log       *slog.Logger
+	threshold time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	stats map[string]*QueryStat
+}
+
+var _ pgx.QueryTracer = (*QueryStats)(nil)
+
+// NewQueryStats создаёт трассировщик; threshold <= 0 отключает лог
+// медленных запросов, статистика собирается всегда.
+func NewQueryStats(log *slog.Logger, threshold time.Duration) *QueryStats {
+	return &QueryStats{
+		log:       log,
+		threshold: threshold,
+		now:       time.Now,
+		stats:     make(map[string]*QueryStat),
+	}
+}
+
+func (q *QueryStats) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	start := queryStart{at: q.now(), args: data.Args}
+
+	var operation string
+	start.name, operation = statementName(data.SQL)
+	// по одной операции запрос не найти, поэтому в лог идёт сам SQL
+	if start.name == operation {
+		start.sql = truncate(strings.Join(strings.Fields(data.SQL), " "), maxLoggedSQL)
+	}
+	return context.WithValue(ctx, queryStartKey{}, start)
+}
+
+func (q *QueryStats) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	elapsed := q.now().Sub(start.at)
+	slow := q.threshold > 0 && elapsed >= q.threshold
+	// pgx.ErrNoRows — штатный результат, а не ошибка запроса
+	failed := data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows)
+
+	q.mu.Lock()
+	s := q.stats[start.name]
+	if s == nil {
+		s = &QueryStat{Name: start.name}
+		q.stats[start.name] = s
+	}
+	s.Count++
+	s.Total += elapsed
+	s.Max = max(s.Max, elapsed)
+	if failed {
+		s.Errors++
+	}
+	if slow {
+		s.Slow++
+	}
+	q.mu.Unlock()
+
+	if !slow {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("query", start.name),
+		slog.Duration("duration", elapsed),
+		slog.Duration("threshold", q.threshold),
+		slog.Int64("rows", data.CommandTag.RowsAffected()),
+		slog.Any("args", redactArgs(start.args)),
+	}
+	if start.sql != "" {
+		attrs = append(attrs, slog.String("sql", start.sql))
+	}
+	if failed {
+		attrs = append(attrs, slog.String("error", data.Err.Error()))
+	}
+	q.log.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs...)
+}
+
+// Snapshot возвращает статистику по всем запросам, самые затратные
+// по суммарному времени — первыми.
+func (q *QueryStats) Snapshot() []QueryStat {
+	q.mu.Lock()
+	out := make([]QueryStat, 0, len(q.stats))
+	for _, s := range q.stats {
+		out = append(out, *s)
+	}
+	q.mu.Unlock()
+
+	slices.SortFunc(out, func(a, b QueryStat) int {
+		if c := cmp.Compare(b.Total, a.Total); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return out
+}
+
+// redactArgs заменяет значения аргументов их типами.
+func redactArgs(args []any) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		out[i] = fmt.Sprintf("%T", arg)
+	}
+	return out
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,225 @@
+package pgxclient
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/resilience"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Reader — соединение для чтения: реплика, primary или транзакция из ctx.
+// Совпадает с sqlc.DBTX, поэтому подходит для sqlc.New.
+type Reader interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type primaryCtxKey struct{}
+
+// ContextWithPrimary заставляет Reader читать с primary. Нужен после записи,
+// когда реплика могла её ещё не получить (read-your-writes), и там, где
+// устаревшие данные опасны: проверка пароля, прав, заполнение кеша.
+func ContextWithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryCtxKey{}, true)
+}
+
+// PrimaryFromContext сообщает, помечен ли ctx через ContextWithPrimary.
+func PrimaryFromContext(ctx context.Context) bool {
+	forced, _ := ctx.Value(primaryCtxKey{}).(bool)
+	return forced
+}
+
+// replicaLagQuery возвращает отставание реплики в секундах. Если всё
+// полученное уже применено, отставание нулевое, даже когда primary давно
+// ничего не писал и pg_last_xact_replay_timestamp() старый.
+const replicaLagQuery = `SELECT
+	CASE
+		WHEN NOT pg_is_in_recovery() THEN 0
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END::float8`
+
+type replica struct {
+	name    string
+	pool    *pgxpool.Pool
+	policy  *resilience.Policy
+	healthy atomic.Bool
+	lag     atomic.Int64
+}
+
+// ReplicaStatus — состояние реплики по последней проверке.
+type ReplicaStatus struct {
+	Name    string
+	Healthy bool
+	Lag     time.Duration
+	Pool    *pgxpool.Pool
+}
+
+// Reader выбирает соединение для чтения: транзакцию из ctx, primary при
+// ContextWithPrimary, иначе исправную реплику по кругу. Если исправных
+// реплик нет (или их автоматы разомкнуты), читает с primary.
+// Возвращённый Reader — только для чтения: с WithResilience его запросы
+// повторяются как идемпотентные.
+func (c *Client) Reader(ctx context.Context) Reader {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	if len(c.replicas) == 0 || PrimaryFromContext(ctx) {
+		return guard(c.pool, c.policy, true)
+	}
+
+	start := c.next.Add(1)
+	for i := range c.replicas {
+		r := c.replicas[(int(start)+i)%len(c.replicas)]
+		if r.healthy.Load() && available(r.policy) {
+			return guard(r.pool, r.policy, true)
+		}
+	}
+	return guard(c.pool, c.policy, true)
+}
+
+// Replicas возвращает состояние реплик, например для метрик пулов.
+func (c *Client) Replicas() []ReplicaStatus {
+	out := make([]ReplicaStatus, 0, len(c.replicas))
+	for _, r := range c.replicas {
+		out = append(out, ReplicaStatus{
+			Name:    r.name,
+			Healthy: r.healthy.Load(),
+			Lag:     time.Duration(r.lag.Load()),
+			Pool:    r.pool,
+		})
+	}
+	return out
+}
+
+// newReplicas создаёт пулы реплик с настройками primary. Соединения
+// ленивые: недоступная реплика — не ошибка старта, а исключённая реплика.
+func newReplicas(ctx context.Context, cfg *config) ([]*replica, error) {
+	replicas := make([]*replica, 0, len(cfg.replicas))
+	for _, addr := range cfg.replicas {
+		host, port, err := replicaAddr(addr, cfg.port)
+		if err != nil {
+			closeReplicas(replicas)
+			return nil, err
+		}
+
+		rcfg := *cfg
+		rcfg.host = host
+		rcfg.port = port
+
+		poolCfg, err := createPGXConfig(&rcfg)
+		if err != nil {
+			closeReplicas(replicas)
+			return nil, err
+		}
+
+		pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+		if err != nil {
+			closeReplicas(replicas)
+			return nil, err
+		}
+
+		name := net.JoinHostPort(host, strconv.Itoa(int(port)))
+		policy, err := newPolicy(cfg, "replica "+name)
+		if err != nil {
+			pool.Close()
+			closeReplicas(replicas)
+			return nil, err
+		}
+
+		r := &replica{
+			name:   name,
+			pool:   pool,
+			policy: policy,
+		}
+		// исправна до первой проверки: так недоступная при старте реплика
+		// попадёт в лог как исключённая
+		r.healthy.Store(true)
+		replicas = append(replicas, r)
+	}
+	return replicas, nil
+}
+
+func replicaAddr(addr string, defaultPort uint16) (string, uint16, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		// без порта — порт primary
+		return addr, defaultPort, nil
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil || port == 0 {
+		return "", 0, &net.AddrError{Err: "invalid port", Addr: addr}
+	}
+	return host, uint16(port), nil
+}
+
+func closeReplicas(replicas []*replica) {
+	for _, r := range replicas {
+		r.pool.Close()
+	}
+}
+
+// checkReplicas проверяет все реплики: недоступные или отстающие больше
+// maxLag исключаются из чтения, восстановившиеся возвращаются.
+func (c *Client) checkReplicas(ctx context.Context) {
+	for _, r := range c.replicas {
+		c.checkReplica(ctx, r)
+	}
+}
+
+func (c *Client) checkReplica(ctx context.Context, r *replica) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.connectTimeout)
+	defer cancel()
+
+	var lagSeconds float64
+	err := r.pool.QueryRow(ctx, replicaLagQuery).Scan(&lagSeconds)
+
+	lag := time.Duration(lagSeconds * float64(time.Second))
+	if err == nil {
+		r.lag.Store(int64(lag))
+	}
+
+	healthy := err == nil && lag <= c.cfg.replicaMaxLag
+	if was := r.healthy.Swap(healthy); was == healthy {
+		return
+	}
+
+	log := c.cfg.log.With(slog.String("replica", r.name))
+	switch {
+	case healthy:
+		log.Info("replica is back in rotation", slog.Duration("lag", lag))
+	case err != nil:
+		log.Warn("replica ejected: check failed", slog.String("error", err.Error()))
+	default:
+		log.Warn("replica ejected: lag too high",
+			slog.Duration("lag", lag),
+			slog.Duration("max_lag", c.cfg.replicaMaxLag),
+		)
+	}
+}
+
+// watchReplicas проверяет реплики каждые replicaCheckInterval до Close.
+func (c *Client) watchReplicas(ctx context.Context) {
+	defer close(c.watchDone)
+
+	ticker := time.NewTicker(c.cfg.replicaCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.checkReplicas(ctx)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package pgxclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/resilience"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE коды недоступности сервера.
+const (
+	codeAdminShutdown    = "57P01"
+	codeCannotConnectNow = "57P03"
+	classConnection      = "08"
+)
+
+// IsRetryable — resilience.Classifier для Postgres. Сериализация и
+// дедлок откатывают оператор целиком, а ошибки до отправки запроса
+// (pgconn.SafeToRetry) и cannot_connect_now означают, что он не
+// выполнялся, — это повторяется всегда. Обрыв соединения посреди
+// запроса повторяется только для идемпотентных операций.
+func IsRetryable(err error, idempotent bool) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if IsRetryableTxError(err) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == codeCannotConnectNow:
+			return true
+		case pgErr.Code == codeAdminShutdown, strings.HasPrefix(pgErr.Code, classConnection):
+			return idempotent
+		default:
+			return false
+		}
+	}
+
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+	return idempotent && resilience.IsTransient(err)
+}
+
+// isFailure — отказ для автомата: сервер недоступен. Сериализация
+// и дедлок — нормальная работа под нагрузкой, а не отказ.
+func isFailure(err error) bool {
+	return IsRetryable(err, true) && !IsRetryableTxError(err)
+}
+
+func newPolicy(cfg *config, name string) (*resilience.Policy, error) {
+	if !cfg.useResilience {
+		return nil, nil
+	}
+
+	opts := []resilience.Option{
+		resilience.WithClassifier(IsRetryable),
+		resilience.WithFailureClassifier(isFailure),
+	}
+	opts = append(opts, cfg.resilience...)
+	opts = append(opts, resilience.WithName(name))
+
+	return resilience.New(opts...)
+}
+
+// available сообщает, пропустит ли автомат запрос к пулу.
+func available(p *resilience.Policy) bool {
+	return p == nil || p.Breaker() == nil || p.Breaker().State() != resilience.StateOpen
+}
+
+// guard оборачивает db политикой p. Для always=false идемпотентность
+// берётся из ctx (resilience.ContextWithIdempotent).
+func guard(db Reader, p *resilience.Policy, always bool) Reader {
+	if p == nil {
+		return db
+	}
+	return guardedReader{db: db, policy: p, always: always}
+}
+
+type guardedReader struct {
+	db     Reader
+	policy *resilience.Policy
+	always bool
+}
+
+func (g guardedReader) idempotent(ctx context.Context) bool {
+	return g.always || resilience.IsIdempotent(ctx)
+}
+
+func (g guardedReader) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	var tag pgconn.CommandTag
+	err := g.policy.Do(ctx, g.idempotent(ctx), func(ctx context.Context) error {
+		var err error
+		tag, err = g.db.Exec(ctx, sql, args...)
+		return err
+	})
+	return tag, err
+}
+
+// Query повторяет только ошибки до получения строк: прочитанное
+// повторить нельзя, ошибки чтения строк возвращаются как есть.
+func (g guardedReader) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	var rows pgx.Rows
+	err := g.policy.Do(ctx, g.idempotent(ctx), func(ctx context.Context) error {
+		var err error
+		rows, err = g.db.Query(ctx, sql, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (g guardedReader) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return guardedRow{g: g, ctx: ctx, sql: sql, args: args}
+}
+
+// guardedRow выполняет запрос в Scan: до него pgx.Row ошибок не отдаёт.
+type guardedRow struct {
+	g    guardedReader
+	ctx  context.Context
+	sql  string
+	args []any
+}
+
+func (r guardedRow) Scan(dest ...any) error {
+	return r.g.policy.Do(r.ctx, r.g.idempotent(r.ctx), func(ctx context.Context) error {
+		return r.g.db.QueryRow(ctx, r.sql, r.args...).Scan(dest...)
+	})
+}
@@ -4,27 +4,59 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Krokozabra213/schools_backend/internal/pkg/tls-config/tlstest"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 func TestConfigureTLS(t *testing.T) {
 	tests := []struct {
-		name       string
-		sslMode    string
-		wantNilTLS bool
-		wantErr    bool
+		name         string
+		sslMode      string
+		rootCert     string
+		wantNilTLS   bool
+		wantInsecure bool
+		wantFallback bool
+		fallbackTLS  bool
+		wantErr      bool
 	}{
 		{
 			name:       "disable",
 			sslMode:    "disable",
 			wantNilTLS: true,
-			wantErr:    false,
 		},
 		{
-			name:       "require",
-			sslMode:    "require",
-			wantNilTLS: false,
-			wantErr:    false,
+			name:         "allow",
+			sslMode:      "allow",
+			wantNilTLS:   true,
+			wantFallback: true,
+			fallbackTLS:  true,
+		},
+		{
+			name:         "prefer",
+			sslMode:      "prefer",
+			wantInsecure: true,
+			wantFallback: true,
+		},
+		{
+			name:         "require",
+			sslMode:      "require",
+			wantInsecure: true,
+		},
+		{
+			name:         "require with root cert verifies chain",
+			sslMode:      "require",
+			rootCert:     "ca.crt",
+			wantInsecure: true,
+		},
+		{
+			name:         "verify-ca",
+			sslMode:      "verify-ca",
+			wantInsecure: true,
+		},
+		{
+			name:    "verify-full",
+			sslMode: "verify-full",
 		},
 		{
 			name:    "unknown mode",
@@ -33,11 +65,23 @@ func TestConfigureTLS(t *testing.T) {
 		},
 	}
 
+	ca := tlstest.NewCA(t, "test CA")
+	caFile := ca.CAFile(t)
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			connConfig := &pgx.ConnConfig{}
+			connConfig.Host = "db.example.com"
+			connConfig.Port = 5432
+			// ParseConfig("") оставляет запасное подключение к localhost
+			connConfig.Fallbacks = []*pgconn.FallbackConfig{{Host: "localhost", Port: 5432}}
+
+			cfg := &config{sslMode: tt.sslMode}
+			if tt.rootCert != "" {
+				cfg.sslRootCert = caFile
+			}
 
-			err := configureTLS(connConfig, tt.sslMode)
+			err := configureTLS(connConfig, cfg)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("configureTLS() error = %v, wantErr %v", err, tt.wantErr)
@@ -49,16 +93,37 @@ func TestConfigureTLS(t *testing.T) {
 			}
 
 			if tt.wantNilTLS && connConfig.TLSConfig != nil {
-				t.Error("expected nil TLSConfig for disable mode")
+				t.Errorf("expected nil TLSConfig for %s mode", tt.sslMode)
 			}
 
 			if !tt.wantNilTLS {
 				if connConfig.TLSConfig == nil {
 					t.Fatal("expected non-nil TLSConfig")
 				}
-				if !connConfig.TLSConfig.InsecureSkipVerify {
-					t.Error("require mode should set InsecureSkipVerify=true")
+				if connConfig.TLSConfig.InsecureSkipVerify != tt.wantInsecure {
+					t.Errorf("got InsecureSkipVerify %v, want %v", connConfig.TLSConfig.InsecureSkipVerify, tt.wantInsecure)
+				}
+				if connConfig.TLSConfig.ServerName != "db.example.com" {
+					t.Errorf("got ServerName %q, want %q", connConfig.TLSConfig.ServerName, "db.example.com")
+				}
+			}
+
+			if !tt.wantFallback {
+				if len(connConfig.Fallbacks) != 0 {
+					t.Errorf("got %d fallbacks, want none", len(connConfig.Fallbacks))
 				}
+				return
+			}
+
+			if len(connConfig.Fallbacks) != 1 {
+				t.Fatalf("got %d fallbacks, want 1", len(connConfig.Fallbacks))
+			}
+			fb := connConfig.Fallbacks[0]
+			if fb.Host != "db.example.com" || fb.Port != 5432 {
+				t.Errorf("got fallback %s:%d, want db.example.com:5432", fb.Host, fb.Port)
+			}
+			if (fb.TLSConfig != nil) != tt.fallbackTLS {
+				t.Errorf("got fallback TLS %v, want %v", fb.TLSConfig != nil, tt.fallbackTLS)
 			}
 		})
 	}
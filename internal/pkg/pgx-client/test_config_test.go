@@ -22,6 +22,8 @@ func TestDefaultConfig(t *testing.T) {
 		{"minConns", cfg.minConns, int32(2)},
 		{"maxConnLifeTime", cfg.maxConnLifeTime, 2 * time.Hour},
 		{"maxConnIdleTime", cfg.maxConnIdleTime, 15 * time.Minute},
+		{"replicaMaxLag", cfg.replicaMaxLag, 10 * time.Second},
+		{"replicaCheckInterval", cfg.replicaCheckInterval, 5 * time.Second},
 		{"user is nil", cfg.user == nil, true},
 		{"password is nil", cfg.password == nil, true},
 	}
@@ -15,11 +15,18 @@ func TestOptions(t *testing.T) {
 		WithPassword("secret"),
 		WithDatabase("mydb"),
 		WithSSL("require"),
+		WithSSLRootCert("/etc/ssl/pg/ca.crt"),
+		WithSSLCert("/etc/ssl/pg/client.crt", "/etc/ssl/pg/client.key"),
+		WithSSLServerName("pg.internal"),
 		WithConnectionTimeout(10 * time.Second),
 		WithMaxConns(20),
 		WithMinConns(5),
 		WithMaxConnLifetime(1 * time.Hour),
 		WithMaxConnIdletime(30 * time.Minute),
+		WithReplicas("replica-1", "replica-2:5433"),
+		WithReplicaMaxLag(3 * time.Second),
+		WithReplicaCheckInterval(time.Second),
+		WithQueryStats(200 * time.Millisecond),
 	}
 
 	for _, opt := range opts {
@@ -37,11 +44,20 @@ func TestOptions(t *testing.T) {
 		{"password", *cfg.password, "secret"},
 		{"database", cfg.database, "mydb"},
 		{"sslMode", cfg.sslMode, "require"},
+		{"sslRootCert", cfg.sslRootCert, "/etc/ssl/pg/ca.crt"},
+		{"sslCert", cfg.sslCert, "/etc/ssl/pg/client.crt"},
+		{"sslKey", cfg.sslKey, "/etc/ssl/pg/client.key"},
+		{"sslServerName", cfg.sslServerName, "pg.internal"},
 		{"connectTimeout", cfg.connectTimeout, 10 * time.Second},
 		{"maxConns", cfg.maxConns, int32(20)},
 		{"minConns", cfg.minConns, int32(5)},
 		{"maxConnLifeTime", cfg.maxConnLifeTime, 1 * time.Hour},
 		{"maxConnIdleTime", cfg.maxConnIdleTime, 30 * time.Minute},
+		{"replicas", len(cfg.replicas), 2},
+		{"replicaMaxLag", cfg.replicaMaxLag, 3 * time.Second},
+		{"replicaCheckInterval", cfg.replicaCheckInterval, time.Second},
+		{"useQueryStats", cfg.useQueryStats, true},
+		{"slowQueryThreshold", cfg.slowQueryThreshold, 200 * time.Millisecond},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,101 @@
+package pgxclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestQueryStats(t *testing.T) {
+	var buf bytes.Buffer
+	stats := NewQueryStats(slog.New(slog.NewTextHandler(&buf, nil)), 100*time.Millisecond)
+
+	now := time.Unix(0, 0)
+	stats.now = func() time.Time { return now }
+
+	run := func(sql string, args []any, took time.Duration, tag string, err error) {
+		ctx := stats.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql, Args: args})
+		now = now.Add(took)
+		stats.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag(tag), Err: err})
+	}
+
+	const getUser = "-- name: GetUserByUsername :one\nSELECT id FROM users WHERE username = $1"
+	run(getUser, []any{"alice"}, 10*time.Millisecond, "SELECT 1", nil)
+	run(getUser, []any{"bob"}, 150*time.Millisecond, "SELECT 1", nil)
+	run(getUser, []any{"carol"}, 20*time.Millisecond, "SELECT 0", pgx.ErrNoRows)
+	run("-- name: CreateUser :one\nINSERT INTO users (username, password) VALUES ($1, $2)",
+		[]any{"dave", "hunter2"}, 300*time.Millisecond, "INSERT 0 0", errors.New("unique violation"))
+	run("SELECT pg_sleep($1)", []any{1}, 100*time.Millisecond, "SELECT 1", nil)
+
+	snapshot := stats.Snapshot()
+	if len(snapshot) != 3 {
+		t.Fatalf("got %d queries, want 3", len(snapshot))
+	}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"most expensive first", snapshot[0].Name, "CreateUser"},
+		{"sqlc name", snapshot[1].Name, "GetUserByUsername"},
+		{"count", snapshot[1].Count, uint64(3)},
+		{"no rows is not an error", snapshot[1].Errors, uint64(0)},
+		{"slow", snapshot[1].Slow, uint64(1)},
+		{"total", snapshot[1].Total, 180 * time.Millisecond},
+		{"max", snapshot[1].Max, 150 * time.Millisecond},
+		{"mean", snapshot[1].Mean(), 60 * time.Millisecond},
+		{"errors", snapshot[0].Errors, uint64(1)},
+		{"operation without sqlc name", snapshot[2].Name, "SELECT"},
+		{"slow on threshold", snapshot[2].Slow, uint64(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+
+	logs := buf.String()
+	if got := strings.Count(logs, "slow query"); got != 3 {
+		t.Errorf("got %d slow query records, want 3:\n%s", got, logs)
+	}
+	for _, want := range []string{
+		"query=GetUserByUsername", "duration=150ms", "rows=1", "args=[string]",
+		"query=CreateUser", `error="unique violation"`, "args=\"[string string]\"",
+		`sql="SELECT pg_sleep($1)"`,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("log has no %s:\n%s", want, logs)
+		}
+	}
+	for _, secret := range []string{"bob", "hunter2"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("argument value %q leaked to log:\n%s", secret, logs)
+		}
+	}
+}
+
+func TestQueryStatsWithoutThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	stats := NewQueryStats(slog.New(slog.NewTextHandler(&buf, nil)), 0)
+
+	ctx := stats.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	stats.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+
+	if buf.Len() != 0 {
+		t.Errorf("got log %q, want none", buf.String())
+	}
+	if got := stats.Snapshot(); len(got) != 1 || got[0].Count != 1 {
+		t.Errorf("got %+v, want one query counted", got)
+	}
+}
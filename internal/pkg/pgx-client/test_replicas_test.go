@@ -0,0 +1,120 @@
+package pgxclient
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lazyPool создаёт пул без соединений: pgxpool подключается при первом запросе.
+func lazyPool(t *testing.T, port string) *pgxpool.Pool {
+	t.Helper()
+
+	pool, err := pgxpool.New(context.Background(), "postgres://u:p@127.0.0.1:"+port+"/db?pool_min_conns=0&connect_timeout=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestReplicaAddr(t *testing.T) {
+	tests := []struct {
+		addr     string
+		wantHost string
+		wantPort uint16
+		wantErr  bool
+	}{
+		{addr: "replica-1", wantHost: "replica-1", wantPort: 5432},
+		{addr: "replica-1:5433", wantHost: "replica-1", wantPort: 5433},
+		{addr: "[::1]:5434", wantHost: "::1", wantPort: 5434},
+		{addr: "replica-1:0", wantErr: true},
+		{addr: "replica-1:port", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			host, port, err := replicaAddr(tt.addr, 5432)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (host != tt.wantHost || port != tt.wantPort) {
+				t.Errorf("got %s:%d, want %s:%d", host, port, tt.wantHost, tt.wantPort)
+			}
+		})
+	}
+}
+
+func TestReader(t *testing.T) {
+	primary := lazyPool(t, "1")
+	r1 := &replica{name: "r1", pool: lazyPool(t, "2")}
+	r2 := &replica{name: "r2", pool: lazyPool(t, "3")}
+	r1.healthy.Store(true)
+	r2.healthy.Store(true)
+
+	c := &Client{pool: primary, replicas: []*replica{r1, r2}}
+	ctx := context.Background()
+
+	// по кругу между исправными репликами
+	seen := map[Reader]bool{}
+	for range 4 {
+		seen[c.Reader(ctx)] = true
+	}
+	if len(seen) != 2 || seen[primary] {
+		t.Errorf("got readers %v, want both replicas", seen)
+	}
+
+	if got := c.Reader(ContextWithPrimary(ctx)); got != primary {
+		t.Error("got replica with ContextWithPrimary, want primary")
+	}
+
+	tx := &fakeTx{}
+	if got := c.Reader(ContextWithTx(ctx, tx)); got != tx {
+		t.Error("got pool inside transaction, want tx")
+	}
+
+	r1.healthy.Store(false)
+	for range 4 {
+		if got := c.Reader(ctx); got != r2.pool {
+			t.Fatal("got ejected replica or primary, want r2")
+		}
+	}
+
+	r2.healthy.Store(false)
+	if got := c.Reader(ctx); got != primary {
+		t.Error("got replica with none healthy, want primary fallback")
+	}
+}
+
+func TestCheckReplicaEjects(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := defaultConfig()
+	cfg.connectTimeout = time.Second
+	cfg.log = slog.New(slog.NewTextHandler(&buf, nil))
+
+	// на порту 1 никто не слушает
+	r := &replica{name: "127.0.0.1:1", pool: lazyPool(t, "1")}
+	r.healthy.Store(true)
+
+	c := &Client{cfg: cfg, replicas: []*replica{r}}
+	c.checkReplicas(context.Background())
+
+	if r.healthy.Load() {
+		t.Error("got healthy unreachable replica")
+	}
+	if !strings.Contains(buf.String(), "replica ejected") {
+		t.Errorf("got log %q, want ejection", buf.String())
+	}
+
+	// повторная неудача не пишет в лог снова
+	buf.Reset()
+	c.checkReplicas(context.Background())
+	if buf.Len() != 0 {
+		t.Errorf("got log %q on repeated failure, want none", buf.String())
+	}
+}
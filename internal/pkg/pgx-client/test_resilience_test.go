@@ -0,0 +1,224 @@
+package pgxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/resilience"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var errConnReset = &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+
+// scriptedDB возвращает ошибки из errs по очереди, потом nil.
+type scriptedDB struct {
+	errs  []error
+	calls int
+}
+
+func (d *scriptedDB) next() error {
+	d.calls++
+	if d.calls <= len(d.errs) {
+		return d.errs[d.calls-1]
+	}
+	return nil
+}
+
+func (d *scriptedDB) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.NewCommandTag("UPDATE 1"), d.next()
+}
+
+func (d *scriptedDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, d.next()
+}
+
+func (d *scriptedDB) QueryRow(context.Context, string, ...any) pgx.Row {
+	return scriptedRow{err: d.next()}
+}
+
+type scriptedRow struct {
+	err error
+}
+
+func (r scriptedRow) Scan(dest ...any) error {
+	return r.err
+}
+
+func testPolicy(t *testing.T, opts ...resilience.Option) *resilience.Policy {
+	t.Helper()
+
+	cfg := defaultConfig()
+	WithResilience(append([]resilience.Option{resilience.WithBackoff(0, 0)}, opts...)...)(&cfg)
+
+	p, err := newPolicy(&cfg, "test")
+	if err != nil {
+		t.Fatalf("newPolicy() error: %v", err)
+	}
+	return p
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		want          bool
+		wantNonIdemp  bool
+		wantIsFailure bool
+	}{
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true, true, false},
+		{"deadlock", &pgconn.PgError{Code: "40P01"}, true, true, false},
+		{"cannot connect now", &pgconn.PgError{Code: "57P03"}, true, true, true},
+		{"admin shutdown", &pgconn.PgError{Code: "57P01"}, true, false, true},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, true, false, true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false, false, false},
+		{"no rows", pgx.ErrNoRows, false, false, false},
+		{"connection reset", errConnReset, true, false, true},
+		{"unexpected EOF", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true, false, true},
+		{"context canceled", context.Canceled, false, false, false},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err, true); got != tt.want {
+				t.Errorf("idempotent: got %v, want %v", got, tt.want)
+			}
+			if got := IsRetryable(tt.err, false); got != tt.wantNonIdemp {
+				t.Errorf("non-idempotent: got %v, want %v", got, tt.wantNonIdemp)
+			}
+			if got := isFailure(tt.err); got != tt.wantIsFailure {
+				t.Errorf("isFailure: got %v, want %v", got, tt.wantIsFailure)
+			}
+		})
+	}
+}
+
+func TestGuard(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		always    bool
+		ctx       context.Context
+		call      func(ctx context.Context, r Reader) error
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name: "exec retries safe error",
+			ctx:  ctx,
+			call: func(ctx context.Context, r Reader) error {
+				_, err := r.Exec(ctx, "UPDATE")
+				return err
+			},
+			errs:      []error{&pgconn.PgError{Code: "40001"}},
+			wantCalls: 2,
+		},
+		{
+			name: "exec does not retry reset",
+			ctx:  ctx,
+			call: func(ctx context.Context, r Reader) error {
+				_, err := r.Exec(ctx, "INSERT")
+				return err
+			},
+			errs:      []error{errConnReset},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name: "exec retries reset when marked idempotent",
+			ctx:  resilience.ContextWithIdempotent(ctx),
+			call: func(ctx context.Context, r Reader) error {
+				_, err := r.Exec(ctx, "UPDATE")
+				return err
+			},
+			errs:      []error{errConnReset},
+			wantCalls: 2,
+		},
+		{
+			name:   "reader query retries reset",
+			always: true,
+			ctx:    ctx,
+			call: func(ctx context.Context, r Reader) error {
+				_, err := r.Query(ctx, "SELECT")
+				return err
+			},
+			errs:      []error{errConnReset, errConnReset},
+			wantCalls: 3,
+		},
+		{
+			name:   "query row retries in scan",
+			always: true,
+			ctx:    ctx,
+			call: func(ctx context.Context, r Reader) error {
+				return r.QueryRow(ctx, "SELECT").Scan()
+			},
+			errs:      []error{errConnReset},
+			wantCalls: 2,
+		},
+		{
+			name:   "no rows is not retried",
+			always: true,
+			ctx:    ctx,
+			call: func(ctx context.Context, r Reader) error {
+				return r.QueryRow(ctx, "SELECT").Scan()
+			},
+			errs:      []error{pgx.ErrNoRows},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &scriptedDB{errs: tt.errs}
+			err := tt.call(tt.ctx, guard(db, testPolicy(t), tt.always))
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if db.calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", db.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestGuardWithoutPolicy(t *testing.T) {
+	db := &scriptedDB{}
+	if got := guard(db, nil, true); got != Reader(db) {
+		t.Error("guard without policy must return db as is")
+	}
+}
+
+func TestReaderSkipsOpenBreaker(t *testing.T) {
+	primary := lazyPool(t, "1")
+	r1 := &replica{name: "r1", pool: lazyPool(t, "2"), policy: testPolicy(t, resilience.WithBreaker(1, time.Hour))}
+	r1.healthy.Store(true)
+
+	c := &Client{pool: primary, replicas: []*replica{r1}}
+	ctx := context.Background()
+
+	if got := c.Reader(ctx).(guardedReader); got.db != Reader(r1.pool) || !got.always {
+		t.Fatalf("got %v, want guarded replica while breaker is closed", got)
+	}
+
+	// отказ реплики размыкает её автомат, чтения уходят на primary
+	_ = r1.policy.Do(ctx, true, func(context.Context) error { return errConnReset })
+	if got := c.Reader(ctx); got != Reader(primary) {
+		t.Errorf("got %v, want primary while replica breaker is open", got)
+	}
+
+	if !errors.Is(r1.policy.Do(ctx, true, func(context.Context) error { return nil }), resilience.ErrCircuitOpen) {
+		t.Error("expected open breaker")
+	}
+}
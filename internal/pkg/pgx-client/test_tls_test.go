@@ -0,0 +1,216 @@
+package pgxclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/tls-config/tlstest"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+)
+
+// handshakeOK — сообщение фейкового сервера: до него клиент доходит
+// только после успешного TLS рукопожатия.
+const handshakeOK = "tls handshake ok"
+
+// fakeTLSServer отвечает на SSLRequest согласием, проходит рукопожатие
+// и отклоняет startup сообщение ошибкой handshakeOK.
+func fakeTLSServer(t *testing.T, tlsCfg *tls.Config) (host string, port uint16) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveTLS(conn, tlsCfg)
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), uint16(addr.Port)
+}
+
+func serveTLS(conn net.Conn, tlsCfg *tls.Config) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	msg, err := pgproto3.NewBackend(conn, conn).ReceiveStartupMessage()
+	if err != nil {
+		return
+	}
+	if _, ok := msg.(*pgproto3.SSLRequest); !ok {
+		return
+	}
+	if _, err := conn.Write([]byte("S")); err != nil {
+		return
+	}
+
+	tlsConn := tls.Server(conn, tlsCfg)
+	if err := tlsConn.Handshake(); err != nil {
+		return
+	}
+
+	backend := pgproto3.NewBackend(tlsConn, tlsConn)
+	if _, err := backend.ReceiveStartupMessage(); err != nil {
+		return
+	}
+	backend.Send(&pgproto3.ErrorResponse{Severity: "FATAL", Code: "28000", Message: handshakeOK})
+	_ = backend.Flush()
+}
+
+func TestTLSHandshake(t *testing.T) {
+	ca := tlstest.NewCA(t, "test CA")
+	other := tlstest.NewCA(t, "other CA")
+
+	caFile := ca.CAFile(t)
+	otherFile := other.CAFile(t)
+	certFile, keyFile := ca.IssueFiles(t, "client")
+
+	host, port := fakeTLSServer(t, ca.ServerConfig(t, nil, "127.0.0.1"))
+	mtlsHost, mtlsPort := fakeTLSServer(t, ca.ServerConfig(t, ca.Pool, "127.0.0.1"))
+
+	tests := []struct {
+		name    string
+		mtls    bool
+		modify  func(*config)
+		wantErr bool
+	}{
+		{
+			name:   "verify-full",
+			modify: func(c *config) { c.sslMode = "verify-full"; c.sslRootCert = caFile },
+		},
+		{
+			name: "verify-full wrong server name",
+			modify: func(c *config) {
+				c.sslMode = "verify-full"
+				c.sslRootCert = caFile
+				c.sslServerName = "pg.internal"
+			},
+			wantErr: true,
+		},
+		{
+			name:    "verify-full unknown CA",
+			modify:  func(c *config) { c.sslMode = "verify-full"; c.sslRootCert = otherFile },
+			wantErr: true,
+		},
+		{
+			name: "verify-ca ignores server name",
+			modify: func(c *config) {
+				c.sslMode = "verify-ca"
+				c.sslRootCert = caFile
+				c.sslServerName = "pg.internal"
+			},
+		},
+		{
+			name:    "verify-ca unknown CA",
+			modify:  func(c *config) { c.sslMode = "verify-ca"; c.sslRootCert = otherFile },
+			wantErr: true,
+		},
+		{
+			name:   "require skips verification",
+			modify: func(c *config) { c.sslMode = "require" },
+		},
+		{
+			name:    "require with root cert verifies chain",
+			modify:  func(c *config) { c.sslMode = "require"; c.sslRootCert = otherFile },
+			wantErr: true,
+		},
+		{
+			name: "mtls",
+			mtls: true,
+			modify: func(c *config) {
+				c.sslMode = "verify-full"
+				c.sslRootCert = caFile
+				c.sslCert = certFile
+				c.sslKey = keyFile
+			},
+		},
+		{
+			name:    "mtls without client cert",
+			mtls:    true,
+			modify:  func(c *config) { c.sslMode = "verify-full"; c.sslRootCert = caFile },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, password := "user", "password"
+			cfg := defaultConfig()
+			cfg.user = &user
+			cfg.password = &password
+			cfg.host, cfg.port = host, port
+			if tt.mtls {
+				cfg.host, cfg.port = mtlsHost, mtlsPort
+			}
+			tt.modify(&cfg)
+
+			poolCfg, err := createPGXConfig(&cfg)
+			if err != nil {
+				t.Fatalf("createPGXConfig() error: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			_, err = pgconn.ConnectConfig(ctx, &poolCfg.ConnConfig.Config)
+			if err == nil {
+				t.Fatal("fake server must reject startup")
+			}
+
+			var pgErr *pgconn.PgError
+			ok := errors.As(err, &pgErr) && pgErr.Message == handshakeOK
+			if ok == tt.wantErr {
+				t.Errorf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTLSConfigFiles(t *testing.T) {
+	ca := tlstest.NewCA(t, "test CA")
+	certFile, keyFile := ca.IssueFiles(t, "client")
+
+	user, password := "user", "password"
+	cfg := defaultConfig()
+	cfg.user = &user
+	cfg.password = &password
+	cfg.sslMode = "verify-full"
+	cfg.sslRootCert = ca.CAFile(t)
+	cfg.sslCert, cfg.sslKey = certFile, keyFile
+	cfg.sslServerName = "pg.internal"
+
+	poolCfg, err := createPGXConfig(&cfg)
+	if err != nil {
+		t.Fatalf("createPGXConfig() error: %v", err)
+	}
+
+	tlsCfg := poolCfg.ConnConfig.TLSConfig
+	if tlsCfg.ServerName != "pg.internal" {
+		t.Errorf("got ServerName %q, want %q", tlsCfg.ServerName, "pg.internal")
+	}
+	if len(tlsCfg.Certificates) != 1 {
+		t.Errorf("got %d client certificates, want 1", len(tlsCfg.Certificates))
+	}
+	if tlsCfg.RootCAs == nil || !tlsCfg.RootCAs.Equal(ca.Pool) {
+		t.Error("RootCAs must contain only the configured CA")
+	}
+
+	cfg.sslRootCert = certFile + ".missing"
+	if _, err := createPGXConfig(&cfg); err == nil || !strings.Contains(err.Error(), "CA file") {
+		t.Errorf("got error %v, want CA file error", err)
+	}
+}
@@ -0,0 +1,72 @@
+package pgxclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStatementName(t *testing.T) {
+	tests := []struct {
+		sql           string
+		wantName      string
+		wantOperation string
+	}{
+		{"-- name: GetUserByID :one\nSELECT id FROM users WHERE id = $1", "GetUserByID", "SELECT"},
+		{"-- name: CreateUser :one\nINSERT INTO users (username) VALUES ($1)", "CreateUser", "INSERT"},
+		{"update users set name = $1", "UPDATE", "UPDATE"},
+		{"  begin isolation level serializable", "BEGIN", "BEGIN"},
+		{"", "query", ""},
+	}
+
+	for _, tt := range tests {
+		name, operation := statementName(tt.sql)
+		if name != tt.wantName || operation != tt.wantOperation {
+			t.Errorf("statementName(%q) = (%q, %q), want (%q, %q)",
+				tt.sql, name, operation, tt.wantName, tt.wantOperation)
+		}
+	}
+}
+
+func TestOTelQueryTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := NewOTelQueryTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	run := func(sql string, err error) {
+		ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1"), Err: err})
+	}
+
+	run("-- name: GetUserByID :one\nSELECT 1", nil)
+	run("-- name: GetUserByUsername :one\nSELECT 1", pgx.ErrNoRows)
+	run("-- name: CreateUser :one\nINSERT 1", errors.New("unique violation"))
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+
+	tests := []struct {
+		name       string
+		wantStatus codes.Code
+	}{
+		{"GetUserByID", codes.Unset},
+		{"GetUserByUsername", codes.Unset},
+		{"CreateUser", codes.Error},
+	}
+
+	for i, tt := range tests {
+		if got := spans[i].Name(); got != tt.name {
+			t.Errorf("span %d: got name %q, want %q", i, got, tt.name)
+		}
+		if got := spans[i].Status().Code; got != tt.wantStatus {
+			t.Errorf("span %s: got status %v, want %v", tt.name, got, tt.wantStatus)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package pgxclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type fakeTx struct {
+	pgx.Tx
+	committed  bool
+	rolledBack bool
+	commitErr  error
+}
+
+func (t *fakeTx) Commit(ctx context.Context) error {
+	t.committed = true
+	return t.commitErr
+}
+
+func (t *fakeTx) Rollback(ctx context.Context) error {
+	t.rolledBack = true
+	return nil
+}
+
+type fakeDB struct {
+	Database
+	txs       []*fakeTx
+	commitErr []error
+	isoLevels []pgx.TxIsoLevel
+}
+
+func (d *fakeDB) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	tx := &fakeTx{}
+	if len(d.txs) < len(d.commitErr) {
+		tx.commitErr = d.commitErr[len(d.txs)]
+	}
+	d.txs = append(d.txs, tx)
+	d.isoLevels = append(d.isoLevels, opts.IsoLevel)
+	return tx, nil
+}
+
+func TestWithinTxCommit(t *testing.T) {
+	db := &fakeDB{}
+	m := NewTxManager(db, WithIsolation(Serializable))
+
+	var inner pgx.Tx
+	err := m.WithinTx(context.Background(), func(ctx context.Context) error {
+		inner, _ = TxFromContext(ctx)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(db.txs) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(db.txs))
+	}
+	if inner != db.txs[0] {
+		t.Error("transaction was not propagated through context")
+	}
+	if !db.txs[0].committed {
+		t.Error("transaction was not committed")
+	}
+	if db.isoLevels[0] != pgx.Serializable {
+		t.Errorf("got isolation %q, want %q", db.isoLevels[0], pgx.Serializable)
+	}
+}
+
+func TestWithinTxRollbackOnError(t *testing.T) {
+	db := &fakeDB{}
+	m := NewTxManager(db)
+
+	wantErr := errors.New("boom")
+	err := m.WithinTx(context.Background(), func(ctx context.Context) error {
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("got %v, want %v", err, wantErr)
+	}
+
+	if db.txs[0].committed {
+		t.Error("transaction should not be committed")
+	}
+	if !db.txs[0].rolledBack {
+		t.Error("transaction was not rolled back")
+	}
+}
+
+func TestWithinTxRetriesSerializationFailure(t *testing.T) {
+	serialization := &pgconn.PgError{Code: codeSerializationFailure}
+
+	tests := []struct {
+		name       string
+		commitErr  []error
+		maxRetries int
+		wantCalls  int
+		wantErr    bool
+	}{
+		{
+			name:       "succeeds after retry",
+			commitErr:  []error{serialization, serialization},
+			maxRetries: 3,
+			wantCalls:  3,
+		},
+		{
+			name:       "gives up after max retries",
+			commitErr:  []error{serialization, serialization, serialization},
+			maxRetries: 2,
+			wantCalls:  3,
+			wantErr:    true,
+		},
+		{
+			name:       "non retryable error",
+			commitErr:  []error{errors.New("connection refused")},
+			maxRetries: 3,
+			wantCalls:  1,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{commitErr: tt.commitErr}
+			m := NewTxManager(db, WithMaxRetries(tt.maxRetries), WithRetryDelay(0))
+
+			calls := 0
+			err := m.WithinTx(context.Background(), func(ctx context.Context) error {
+				calls++
+				return nil
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithinTx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWithinTxJoinsExisting(t *testing.T) {
+	db := &fakeDB{}
+	m := NewTxManager(db)
+
+	err := m.WithinTx(context.Background(), func(ctx context.Context) error {
+		return m.WithinTxLevel(ctx, Serializable, func(ctx context.Context) error {
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(db.txs) != 1 {
+		t.Errorf("nested call started %d transactions, want 1", len(db.txs))
+	}
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"deadlock", &pgconn.PgError{Code: "40P01"}, true},
+		{"wrapped", errors.Join(errors.New("commit tx"), &pgconn.PgError{Code: "40001"}), true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"plain error", errors.New("boom"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryableTxError(tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package pgxclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/Krokozabra213/schools_backend/internal/pkg/pgx-client"
+
+// otelQueryTracer создаёт клиентский спан на каждый запрос. Имя спана —
+// имя запроса sqlc ("-- name: GetUserByID :one"), иначе первое слово SQL.
+type otelQueryTracer struct {
+	tracer trace.Tracer
+}
+
+// NewOTelQueryTracer возвращает pgx.QueryTracer для OpenTelemetry.
+// Аргументы запроса в спан не попадают: там могут быть пароли и персональные данные.
+func NewOTelQueryTracer(tp trace.TracerProvider) pgx.QueryTracer {
+	return &otelQueryTracer{
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *otelQueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	name, operation := statementName(data.SQL)
+
+	ctx, _ = t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(data.SQL),
+		),
+	)
+	return ctx
+}
+
+func (t *otelQueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	// pgx.ErrNoRows — штатный результат, а не ошибка запроса
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+		return
+	}
+	span.SetAttributes(semconv.DBResponseReturnedRows(int(data.CommandTag.RowsAffected())))
+}
+
+// statementName извлекает имя запроса sqlc и SQL-операцию.
+func statementName(sql string) (name, operation string) {
+	sql = strings.TrimSpace(sql)
+
+	if rest, ok := strings.CutPrefix(sql, "-- name: "); ok {
+		line, body, _ := strings.Cut(rest, "\n")
+		if fields := strings.Fields(line); len(fields) > 0 {
+			name = fields[0]
+		}
+		sql = strings.TrimSpace(body)
+	}
+
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+
+	if name == "" {
+		name = operation
+	}
+	if name == "" {
+		name = "query"
+	}
+	return name, operation
+}
+
+// multiQueryTracer вызывает несколько трассировщиков по очереди.
+type multiQueryTracer []pgx.QueryTracer
+
+func (m multiQueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	for _, t := range m {
+		ctx = t.TraceQueryStart(ctx, conn, data)
+	}
+	return ctx
+}
+
+func (m multiQueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	for i := len(m) - 1; i >= 0; i-- {
+		m[i].TraceQueryEnd(ctx, conn, data)
+	}
+}
+
+func queryTracer(tracers []pgx.QueryTracer) pgx.QueryTracer {
+	switch len(tracers) {
+	case 0:
+		return nil
+	case 1:
+		return tracers[0]
+	default:
+		return multiQueryTracer(tracers)
+	}
+}
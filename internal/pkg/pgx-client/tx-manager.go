@@ -0,0 +1,187 @@
+package pgxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	defaultTxMaxRetries = 3
+	defaultTxRetryDelay = 20 * time.Millisecond
+)
+
+// SQLSTATE коды, при которых транзакцию безопасно повторить целиком.
+const (
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+// IsolationLevel уровень изоляции транзакции.
+type IsolationLevel string
+
+const (
+	ReadCommitted  IsolationLevel = IsolationLevel(pgx.ReadCommitted)
+	RepeatableRead IsolationLevel = IsolationLevel(pgx.RepeatableRead)
+	Serializable   IsolationLevel = IsolationLevel(pgx.Serializable)
+)
+
+type txCtxKey struct{}
+
+// ContextWithTx returns a copy of ctx carrying tx.
+func ContextWithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txCtxKey{}, tx)
+}
+
+// TxFromContext returns the transaction started by TxManager, if any.
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txCtxKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+type txManagerConfig struct {
+	isolation  IsolationLevel
+	maxRetries int
+	retryDelay time.Duration
+}
+
+type TxManagerOption func(*txManagerConfig)
+
+// WithIsolation sets the isolation level used by WithinTx.
+func WithIsolation(level IsolationLevel) TxManagerOption {
+	return func(c *txManagerConfig) {
+		c.isolation = level
+	}
+}
+
+// WithMaxRetries sets how many times a transaction is retried on
+// serialization failures and deadlocks.
+func WithMaxRetries(retries int) TxManagerOption {
+	return func(c *txManagerConfig) {
+		c.maxRetries = retries
+	}
+}
+
+// WithRetryDelay sets the base delay between retries.
+func WithRetryDelay(delay time.Duration) TxManagerOption {
+	return func(c *txManagerConfig) {
+		c.retryDelay = delay
+	}
+}
+
+// TxManager runs functions inside a transaction propagated through context.
+type TxManager struct {
+	db  Database
+	cfg txManagerConfig
+}
+
+func NewTxManager(db Database, opts ...TxManagerOption) *TxManager {
+	cfg := txManagerConfig{
+		isolation:  ReadCommitted,
+		maxRetries: defaultTxMaxRetries,
+		retryDelay: defaultTxRetryDelay,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &TxManager{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// WithinTx runs fn in a transaction with the default isolation level.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.WithinTxLevel(ctx, m.cfg.isolation, fn)
+}
+
+// WithinTxLevel runs fn in a transaction with the given isolation level.
+// If ctx already carries a transaction, fn joins it and level is ignored.
+// fn may be called several times, so it must not have side effects
+// outside the database.
+func (m *TxManager) WithinTxLevel(ctx context.Context, level IsolationLevel, fn func(ctx context.Context) error) error {
+	if _, ok := TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = m.runTx(ctx, level, fn)
+		if err == nil || !IsRetryableTxError(err) || attempt >= m.cfg.maxRetries {
+			return err
+		}
+
+		if waitErr := sleepCtx(ctx, m.backoff(attempt)); waitErr != nil {
+			return errors.Join(err, waitErr)
+		}
+	}
+}
+
+func (m *TxManager) runTx(ctx context.Context, level IsolationLevel, fn func(ctx context.Context) error) (err error) {
+	tx, err := m.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.TxIsoLevel(level)})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+			panic(p)
+		}
+	}()
+
+	if err := fn(ContextWithTx(ctx, tx)); err != nil {
+		if rbErr := tx.Rollback(context.WithoutCancel(ctx)); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			return errors.Join(err, fmt.Errorf("rollback tx: %w", rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+func (m *TxManager) backoff(attempt int) time.Duration {
+	base := m.cfg.retryDelay << attempt
+	if base <= 0 {
+		return 0
+	}
+	// джиттер, чтобы конкурирующие транзакции не повторялись синхронно
+	return base/2 + rand.N(base/2+1)
+}
+
+// IsRetryableTxError reports whether err is a serialization failure or
+// a deadlock, after which the whole transaction can be retried.
+func IsRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == codeSerializationFailure || pgErr.Code == codeDeadlockDetected
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
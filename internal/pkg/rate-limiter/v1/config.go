@@ -1,64 +1,228 @@
 package ratelimiterv1
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 )
 
+// FailPolicy определяет поведение при недоступности лимитера.
+type FailPolicy string
+
+const (
+	// FailOpen пропускает запрос, если лимитер вернул ошибку.
+	FailOpen FailPolicy = "open"
+	// FailClosed отклоняет запрос, если лимитер вернул ошибку
+	// (для чувствительных методов вроде Login).
+	FailClosed FailPolicy = "closed"
+)
+
+func (p FailPolicy) valid() bool {
+	return p == FailOpen || p == FailClosed
+}
+
 // Limit описывает ограничение: количество запросов за интервал времени.
+// burst — сколько запросов можно выполнить подряд без ожидания
+// (используется token bucket и GCRA; по умолчанию равен count).
 type Limit struct {
 	count  int
 	window time.Duration
+	burst  int
 }
 
 func NewLimit(count int, window time.Duration) Limit {
 	return Limit{
 		count:  count,
 		window: window,
+		burst:  count,
+	}
+}
+
+// NewLimitWithBurst создаёт лимит с отдельным размером всплеска.
+func NewLimitWithBurst(count int, window time.Duration, burst int) Limit {
+	return Limit{
+		count:  count,
+		window: window,
+		burst:  burst,
+	}
+}
+
+func (l Limit) valid() error {
+	if l.count <= 0 {
+		return errors.New("count must be positive")
+	}
+	if l.window <= 0 {
+		return errors.New("window must be positive")
 	}
+	if l.burst <= 0 {
+		return errors.New("burst must be positive")
+	}
+	return nil
+}
+
+// Rule — лимит со своим ключом. На метод можно навесить несколько правил,
+// например по IP и по пользователю одновременно; запрос проходит,
+// только если его пропускают все правила.
+type Rule struct {
+	Name  string
+	Key   KeyFunc
+	Limit Limit
+}
+
+func (r Rule) valid() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if r.Key == nil {
+		return errors.New("key is required")
+	}
+	if err := r.Limit.valid(); err != nil {
+		return fmt.Errorf("limit: %w", err)
+	}
+	return nil
 }
 
 // Config хранит настройки ограничений для методов.
+// Копии Config разделяют одно состояние, поэтому изменения (в том числе
+// Apply при hot reload) сразу видны уже созданным интерсепторам.
 type Config struct {
-	defaultLimit Limit
+	*configState
+}
+
+type configState struct {
+	defaultLimit  Limit
+	defaultPolicy FailPolicy
+	// keyFunc — ключ основного лимита (по умолчанию IP клиента)
+	keyFunc KeyFunc
+	// customKey — keyFunc задан через SetKeyFunc, и Apply его не заменяет
+	customKey bool
 
 	// Лимиты для конкретных gRPC методов
 	// Ключ: полное имя метода, например "/sso.AuthService/Login"
-	methodLimits map[string]Limit
-	mu           *sync.RWMutex
+	methodLimits   map[string]Limit
+	methodPolicies map[string]FailPolicy
+	// Дополнительные правила для методов
+	methodRules map[string][]Rule
+	observer    Observer
+	mu          sync.RWMutex
 }
 
 // NewConfig создаёт конфигурацию с указанным лимитом по умолчанию.
-func NewConfig(count int, window time.Duration) *Config {
-
-	if count <= 0 || window <= 0 {
-		panic("invalid count or window: must be positive")
+func NewConfig(count int, window time.Duration) (*Config, error) {
+	limit := NewLimit(count, window)
+	if err := limit.valid(); err != nil {
+		return nil, fmt.Errorf("invalid default limit: %w", err)
 	}
 
-	return &Config{
-		defaultLimit: NewLimit(count, window),
-		methodLimits: make(map[string]Limit),
-		mu:           &sync.RWMutex{},
+	return &Config{&configState{
+		defaultLimit:   limit,
+		defaultPolicy:  FailOpen,
+		keyFunc:        IPKey(),
+		methodLimits:   make(map[string]Limit),
+		methodPolicies: make(map[string]FailPolicy),
+		methodRules:    make(map[string][]Rule),
+	}}, nil
+}
+
+// SetKeyFunc задаёт ключ основного лимита, например IPKey с доверенными прокси.
+// Заданный ключ переживает Apply: trustedProxies из Spec относятся только к
+// IPKey по умолчанию.
+func (c *Config) SetKeyFunc(fn KeyFunc) error {
+	if fn == nil {
+		return errors.New("invalid key func: must not be nil")
 	}
+
+	c.mu.Lock()
+	c.keyFunc = fn
+	c.customKey = true
+	c.mu.Unlock()
+	return nil
 }
 
 // SetMethod устанавливает лимит для конкретного метода.
 // Если метод уже был настроен, лимит перезаписывается.
-func (c *Config) SetMethod(method string, count int, window time.Duration) {
-	if count <= 0 || window <= 0 {
-		panic("invalid limit: count and window must be positive")
+func (c *Config) SetMethod(method string, count int, window time.Duration) error {
+	return c.SetMethodLimit(method, NewLimit(count, window))
+}
+
+// SetMethodLimit устанавливает лимит (в том числе с burst) для конкретного метода.
+func (c *Config) SetMethodLimit(method string, limit Limit) error {
+	if err := limit.valid(); err != nil {
+		return fmt.Errorf("invalid limit for %s: %w", method, err)
+	}
+
+	c.mu.Lock()
+	c.methodLimits[method] = limit
+	c.mu.Unlock()
+	return nil
+}
+
+// SetDefaultFailPolicy задаёт поведение при ошибке лимитера для всех методов.
+func (c *Config) SetDefaultFailPolicy(policy FailPolicy) error {
+	if !policy.valid() {
+		return fmt.Errorf("invalid fail policy %q", policy)
+	}
+
+	c.mu.Lock()
+	c.defaultPolicy = policy
+	c.mu.Unlock()
+	return nil
+}
+
+// SetMethodFailPolicy задаёт поведение при ошибке лимитера для метода.
+func (c *Config) SetMethodFailPolicy(method string, policy FailPolicy) error {
+	if !policy.valid() {
+		return fmt.Errorf("invalid fail policy %q for %s", policy, method)
 	}
 
 	c.mu.Lock()
-	c.methodLimits[method] = NewLimit(count, window)
+	c.methodPolicies[method] = policy
 	c.mu.Unlock()
+	return nil
 }
 
-func (c *Config) getMethodLimitOrDefault(method string) Limit {
+// AddMethodRule добавляет методу ещё одно правило поверх основного лимита.
+func (c *Config) AddMethodRule(method string, rule Rule) error {
+	if err := rule.valid(); err != nil {
+		return fmt.Errorf("invalid rule for %s: %w", method, err)
+	}
+
+	c.mu.Lock()
+	c.methodRules[method] = append(c.methodRules[method], rule)
+	c.mu.Unlock()
+	return nil
+}
+
+// hasMethod сообщает, есть ли у метода собственные настройки.
+func (c *Config) hasMethod(method string) bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	if ml, ok := c.methodLimits[method]; ok {
-		return ml
+
+	_, limit := c.methodLimits[method]
+	_, policy := c.methodPolicies[method]
+	_, rules := c.methodRules[method]
+	return limit || policy || rules
+}
+
+// rulesFor возвращает основной лимит метода, его дополнительные правила
+// и политику на случай ошибки лимитера.
+func (c *Config) rulesFor(method string) ([]Rule, FailPolicy) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	limit, ok := c.methodLimits[method]
+	if !ok {
+		limit = c.defaultLimit
 	}
-	return c.defaultLimit
+
+	policy, ok := c.methodPolicies[method]
+	if !ok {
+		policy = c.defaultPolicy
+	}
+
+	extra := c.methodRules[method]
+	rules := make([]Rule, 0, 1+len(extra))
+	rules = append(rules, Rule{Name: "default", Key: c.keyFunc, Limit: limit})
+	return append(rules, extra...), policy
 }
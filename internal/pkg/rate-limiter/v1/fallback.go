@@ -0,0 +1,40 @@
+package ratelimiterv1
+
+import (
+	"context"
+	"log/slog"
+)
+
+// FallbackLimiter uses primary and switches to fallback when primary fails.
+// Обычно primary — Redis, fallback — MemoryLimiter: при недоступности Redis
+// лимиты продолжают действовать, но уже отдельно на каждом инстансе.
+type FallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+	log      *slog.Logger
+}
+
+func NewFallbackLimiter(primary, fallback Limiter, log *slog.Logger) *FallbackLimiter {
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return &FallbackLimiter{
+		primary:  primary,
+		fallback: fallback,
+		log:      log,
+	}
+}
+
+func (l *FallbackLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	res, err := l.primary.Allow(ctx, key, limit)
+	if err == nil {
+		return res, nil
+	}
+
+	l.log.Warn("primary rate limiter failed, using fallback",
+		slog.String("error", err.Error()),
+	)
+
+	return l.fallback.Allow(ctx, key, limit)
+}
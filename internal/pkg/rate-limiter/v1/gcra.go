@@ -0,0 +1,63 @@
+package ratelimiterv1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// GCRALimiter implements the generic cell rate algorithm with Redis.
+// Хранит одно число на ключ — теоретическое время прибытия (TAT)
+// следующего запроса.
+type GCRALimiter struct {
+	client redis.Scripter
+}
+
+func NewGCRALimiter(client redis.Cmdable) *GCRALimiter {
+	return &GCRALimiter{client: client}
+}
+
+var gcraScript = redis.NewScript(`
+	local key = KEYS[1]
+	local emission = tonumber(ARGV[1])
+	local burst_offset = tonumber(ARGV[2])
+	local now = tonumber(ARGV[3])
+
+	local tat = tonumber(redis.call('GET', key)) or now
+	tat = math.max(tat, now)
+
+	local new_tat = tat + emission
+	local allow_at = new_tat - burst_offset
+
+	if now < allow_at then
+		local remaining = math.floor((burst_offset - (tat - now)) / emission)
+		return {0, remaining, math.ceil(allow_at - now), math.ceil(tat - now)}
+	end
+
+	local reset = math.ceil(new_tat - now)
+	redis.call('SET', key, tostring(new_tat), 'PX', math.max(reset, 1))
+
+	local remaining = math.floor((burst_offset - (new_tat - now)) / emission)
+	return {1, remaining, 0, reset}
+`)
+
+func (l *GCRALimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now().UnixMilli()
+	// интервал между запросами при равномерной нагрузке, мс
+	emission := float64(limit.window.Milliseconds()) / float64(limit.count)
+
+	values, err := gcraScript.Run(ctx, l.client,
+		[]string{key + ":gcra"},
+		emission,
+		emission*float64(limit.burst),
+		now,
+	).Int64Slice()
+
+	if err != nil {
+		return Result{}, fmt.Errorf("gcra script: %w", err)
+	}
+
+	return parseScriptResult(values)
+}
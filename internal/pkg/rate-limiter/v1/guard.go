@@ -0,0 +1,148 @@
+package ratelimiterv1
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	gorediscli "github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client"
+)
+
+const (
+	headerLimit      = "RateLimit-Limit"
+	headerRemaining  = "RateLimit-Remaining"
+	headerReset      = "RateLimit-Reset"
+	headerRetryAfter = "Retry-After"
+)
+
+// guard — общая для всех транспортов логика: выбор лимита, ключ и вызов лимитера.
+type guard struct {
+	limiter Limiter
+	cfg     Config
+	log     *slog.Logger
+}
+
+func newGuard(limiter Limiter, cfg Config, log *slog.Logger) guard {
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return guard{
+		limiter: limiter,
+		cfg:     cfg,
+		log:     log,
+	}
+}
+
+// verdict — решение по одному запросу.
+type verdict struct {
+	limit  Limit
+	result Result
+	// skipped — ни одно правило не применилось (ошибка лимитера или нет ключа),
+	// запрос пропускается без заголовков
+	skipped bool
+	// unavailable — лимитер вернул ошибку, а метод настроен как FailClosed
+	unavailable bool
+}
+
+func (v verdict) allowed() bool {
+	if v.unavailable {
+		return false
+	}
+	return v.skipped || v.result.Allowed
+}
+
+// headers возвращает RateLimit-* и Retry-After (в секундах).
+func (v verdict) headers() map[string]string {
+	if v.skipped || v.unavailable {
+		return nil
+	}
+
+	h := map[string]string{
+		headerLimit:     strconv.Itoa(v.limit.count),
+		headerRemaining: strconv.Itoa(v.result.Remaining),
+		headerReset:     strconv.FormatInt(ceilSeconds(v.result.ResetAfter), 10),
+	}
+	if !v.result.Allowed {
+		h[headerRetryAfter] = strconv.FormatInt(ceilSeconds(v.result.RetryAfter), 10)
+	}
+	return h
+}
+
+// check применяет к запросу все правила метода. Первое отказавшее правило
+// останавливает проверку; в заголовки попадает самое строгое из применённых.
+// Ошибка возвращается, только если ключ построить не удалось.
+func (g guard) check(req *Request) (verdict, error) {
+	var result verdict
+	applied := false
+
+	rules, policy := g.cfg.rulesFor(req.Method)
+
+	for _, rule := range rules {
+		part, err := rule.Key(req)
+		if err != nil {
+			if errors.Is(err, ErrNoKey) {
+				continue
+			}
+			return verdict{}, err
+		}
+
+		key := rateLimitKey(rule.Name, part, req.Method)
+
+		res, err := g.limiter.Allow(req.Ctx, key, rule.Limit)
+		if err != nil {
+			// При ошибке Redis поведение задаёт политика метода: FailOpen пропускает
+			// правило, FailClosed отклоняет запрос.
+			// Чтобы лимиты продолжали действовать, оберните лимитер в FallbackLimiter
+			g.log.Error("rate limiter error",
+				slog.String("error", err.Error()),
+				slog.String("key", key),
+				slog.String("method", req.Method),
+				slog.String("fail_policy", string(policy)),
+			)
+			g.cfg.observe(req.Method, rule.Name, OutcomeError)
+			if policy == FailClosed {
+				return verdict{unavailable: true}, nil
+			}
+			continue
+		}
+
+		v := verdict{limit: rule.Limit, result: res}
+
+		if !res.Allowed {
+			g.cfg.observe(req.Method, rule.Name, OutcomeDenied)
+			g.log.Warn("rate limit exceeded",
+				slog.String("rule", rule.Name),
+				slog.String("key", part),
+				slog.String("method", req.Method),
+				slog.Int("limit", rule.Limit.count),
+				slog.Duration("window", rule.Limit.window),
+				slog.Duration("retry_after", res.RetryAfter),
+			)
+			return v, nil
+		}
+		g.cfg.observe(req.Method, rule.Name, OutcomeAllowed)
+
+		if !applied || res.Remaining < result.result.Remaining {
+			result = v
+		}
+		applied = true
+	}
+
+	if !applied {
+		return verdict{skipped: true}, nil
+	}
+	return result, nil
+}
+
+// rateLimitKey — ключ счётчика правила. Всё, кроме префикса, в hash tag:
+// в Redis Cluster ключи алгоритмов (":tb", ":gcra") одного счётчика
+// лежат в одном слоте, а скобки из заголовков тег не ломают.
+func rateLimitKey(rule, part, method string) string {
+	return limiterPrefix + ":" + gorediscli.HashTag(rule+":"+part+"#"+method)
+}
+
+func ceilSeconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,61 @@
+package ratelimiterv1
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// OtherHTTPPath — метод для путей без собственных настроек в Config.
+const OtherHTTPPath = "other"
+
+// HTTPMiddleware returns net/http middleware for rate limiting.
+// Лимиты методов в Config для HTTP задаются по пути запроса, например "/v1/login".
+// Остальные пути, в том числе несуществующие, делят один лимит и одну метку
+// OtherHTTPPath: иначе клиент плодил бы ключи в Redis и серии метрик,
+// а варианты пути получали бы отдельные лимиты.
+// При превышении отвечает 429 с Retry-After и RateLimit-* заголовками,
+// при недоступности лимитера для FailClosed метода — 503.
+func HTTPMiddleware(limiter Limiter, cfg Config, log *slog.Logger) func(http.Handler) http.Handler {
+	g := newGuard(limiter, cfg, log)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			v, err := g.check(httpRequest(r, cfg))
+			if err != nil {
+				http.Error(w, "failed build rate limit key", http.StatusInternalServerError)
+				return
+			}
+
+			for name, value := range v.headers() {
+				w.Header().Set(name, value)
+			}
+
+			if v.unavailable {
+				http.Error(w, "rate limiter unavailable, try again later", http.StatusServiceUnavailable)
+				return
+			}
+
+			if !v.allowed() {
+				http.Error(w, "rate limit exceeded, try again later", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// httpRequest собирает Request из HTTP запроса.
+func httpRequest(r *http.Request, cfg Config) *Request {
+	method := r.URL.Path
+	if !cfg.hasMethod(method) {
+		method = OtherHTTPPath
+	}
+
+	return &Request{
+		Ctx:      r.Context(),
+		Method:   method,
+		PeerAddr: r.RemoteAddr,
+		Header:   r.Header.Values,
+	}
+}
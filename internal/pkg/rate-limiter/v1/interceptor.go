@@ -2,10 +2,7 @@ package ratelimiterv1
 
 import (
 	"context"
-	"errors"
 	"log/slog"
-	"net"
-	"strings"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -16,12 +13,14 @@ import (
 
 const limiterPrefix = "ratelimit"
 
+var (
+	errRateLimited        = status.Error(codes.ResourceExhausted, "rate limit exceeded, try again later")
+	errLimiterUnavailable = status.Error(codes.Unavailable, "rate limiter unavailable, try again later")
+)
+
 // UnaryInterceptor returns a gRPC unary interceptor for rate limiting.
 func UnaryInterceptor(limiter Limiter, cfg Config, log *slog.Logger) grpc.UnaryServerInterceptor {
-
-	if log == nil {
-		log = slog.Default()
-	}
+	g := newGuard(limiter, cfg, log)
 
 	return func(
 		ctx context.Context,
@@ -29,74 +28,87 @@ func UnaryInterceptor(limiter Limiter, cfg Config, log *slog.Logger) grpc.UnaryS
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (any, error) {
-		clientIP, err := extractClientIP(ctx)
+		v, err := g.check(grpcRequest(ctx, info.FullMethod))
 		if err != nil {
-			return nil, status.Error(codes.Internal, "failed get ip address")
+			return nil, status.Error(codes.Internal, "failed build rate limit key")
 		}
-		method := info.FullMethod
-
-		ml := cfg.getMethodLimitOrDefault(method)
-
-		// Ключ: ip + method
-		key := rateLimitKey(clientIP, method)
 
-		allowed, err := limiter.Allow(ctx, key, ml.count, ml.window)
-		if err != nil {
-			// При ошибке Redis — пропускаем (fail open)
-			// В проде можно сделать fail closed
-			log.Error("rate limiter error",
-				slog.String("error", err.Error()),
-				slog.String("ip", clientIP),
-				slog.String("method", method),
-			)
-			return handler(ctx, req)
+		// Заголовки отдаём всегда, чтобы клиент мог подстроить темп заранее
+		if md := rateLimitMetadata(v); md != nil {
+			if err := grpc.SetHeader(ctx, md); err != nil {
+				g.log.Debug("failed set rate limit headers", slog.String("error", err.Error()))
+			}
 		}
 
-		if !allowed {
-			log.Warn("rate limit exceeded",
-				slog.String("ip", clientIP),
-				slog.String("method", method),
-				slog.Int("limit", ml.count),
-				slog.Duration("window", ml.window),
-			)
-
-			return nil, status.Error(codes.ResourceExhausted,
-				"rate limit exceeded, try again later",
-			)
+		if !v.allowed() {
+			return nil, v.grpcError()
 		}
 
 		return handler(ctx, req)
 	}
 }
 
-// extractClientIP gets client IP from gRPC context.
-// Checks X-Forwarded-For first (behind load balancer), then peer address.
-func extractClientIP(ctx context.Context) (string, error) {
-	// 1. Proxy/LB header
-	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		if xff := md.Get("x-forwarded-for"); len(xff) > 0 {
-			ips := strings.SplitN(xff[0], ",", 2)
-			return strings.TrimSpace(ips[0]), nil
+// StreamInterceptor returns a gRPC stream interceptor for rate limiting.
+// Лимит проверяется один раз при открытии стрима.
+func StreamInterceptor(limiter Limiter, cfg Config, log *slog.Logger) grpc.StreamServerInterceptor {
+	g := newGuard(limiter, cfg, log)
+
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		v, err := g.check(grpcRequest(ss.Context(), info.FullMethod))
+		if err != nil {
+			return status.Error(codes.Internal, "failed build rate limit key")
+		}
+
+		if md := rateLimitMetadata(v); md != nil {
+			if err := ss.SetHeader(md); err != nil {
+				g.log.Debug("failed set rate limit headers", slog.String("error", err.Error()))
+			}
 		}
-		if xri := md.Get("x-real-ip"); len(xri) > 0 {
-			return xri[0], nil
+
+		if !v.allowed() {
+			return v.grpcError()
 		}
+
+		return handler(srv, ss)
 	}
+}
 
-	// 2. Direct peer address
-	if p, ok := peer.FromContext(ctx); ok {
-		host, _, err := net.SplitHostPort(p.Addr.String())
-		if err == nil {
-			return host, nil
-		}
-		return p.Addr.String(), nil // fallback
+// grpcRequest собирает Request из входящего gRPC контекста.
+func grpcRequest(ctx context.Context, method string) *Request {
+	req := &Request{
+		Ctx:    ctx,
+		Method: method,
+		Header: func(name string) []string {
+			return metadata.ValueFromIncomingContext(ctx, name)
+		},
 	}
 
-	return "unknown", errors.New("bad ip")
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		req.PeerAddr = p.Addr.String()
+	}
+
+	return req
 }
 
-func rateLimitKey(ip, method string) string {
-	// Заменяем двоеточия в IPv6 на подчёркивания, чтобы не ломать разделитель
-	safeIP := strings.ReplaceAll(ip, ":", "_")
-	return limiterPrefix + safeIP + "#" + method
+// grpcError возвращает статус отказа: Unavailable при недоступном лимитере
+// (FailClosed), иначе ResourceExhausted.
+func (v verdict) grpcError() error {
+	if v.unavailable {
+		return errLimiterUnavailable
+	}
+	return errRateLimited
+}
+
+// rateLimitMetadata переводит заголовки в gRPC метаданные (ключи в нижнем регистре).
+func rateLimitMetadata(v verdict) metadata.MD {
+	h := v.headers()
+	if h == nil {
+		return nil
+	}
+	return metadata.New(h)
 }
@@ -0,0 +1,200 @@
+package ratelimiterv1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// ErrNoKey означает, что ключ для запроса построить нельзя (например,
+// запрос не аутентифицирован) и соответствующее правило не применяется.
+var ErrNoKey = errors.New("rate limit key not available")
+
+var errNoClientIP = errors.New("bad ip")
+
+const (
+	DefaultClientIDHeader = "x-client-id"
+	DefaultTenantHeader   = "x-school-id"
+)
+
+// Request — транспортно-независимое описание запроса для построения ключа.
+type Request struct {
+	Ctx    context.Context
+	Method string
+	// PeerAddr — адрес непосредственного соединения (host или host:port).
+	PeerAddr string
+	// Header возвращает все значения заголовка (метаданных) по имени.
+	Header func(name string) []string
+}
+
+func (r *Request) header(name string) string {
+	if r.Header == nil {
+		return ""
+	}
+	if values := r.Header(name); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
+
+// KeyFunc строит часть ключа лимита, например "ip:10.0.0.1" или "user:42".
+type KeyFunc func(req *Request) (string, error)
+
+// IPKey limits by client IP. X-Forwarded-For and X-Real-IP are honored only
+// when the direct peer is in trustedProxies; otherwise they are ignored,
+// since any client can set them. A peer address that is not an IP (unix
+// socket, bufconn) is used as is.
+func IPKey(trustedProxies ...netip.Prefix) KeyFunc {
+	return func(req *Request) (string, error) {
+		ip, err := clientIP(req, trustedProxies)
+		if errors.Is(err, errNoClientIP) {
+			if req.PeerAddr == "" {
+				return "", ErrNoKey
+			}
+			// все клиенты такого слушателя делят один лимит, но запросы проходят
+			return "peer:" + req.PeerAddr, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return "ip:" + ip.String(), nil
+	}
+}
+
+// ParseTrustedProxies parses CIDRs (or single addresses) of trusted proxies.
+func ParseTrustedProxies(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+
+		if !strings.Contains(cidr, "/") {
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("parse trusted proxy %q: %w", cidr, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("parse trusted proxy %q: %w", cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+// clientIP определяет адрес клиента. X-Forwarded-For разбирается справа
+// налево: пропускаем доверенные прокси, первый недоверенный адрес — клиент.
+func clientIP(req *Request, trusted []netip.Prefix) (netip.Addr, error) {
+	peerIP, err := parseHost(req.PeerAddr)
+	if err != nil {
+		return netip.Addr{}, errNoClientIP
+	}
+
+	if !isTrusted(peerIP, trusted) {
+		return peerIP, nil
+	}
+
+	if req.Header != nil {
+		var hops []string
+		for _, value := range req.Header("x-forwarded-for") {
+			hops = append(hops, strings.Split(value, ",")...)
+		}
+
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip, err := parseHost(strings.TrimSpace(hops[i]))
+			if err != nil {
+				// битый адрес в цепочке — дальше доверять ей нельзя
+				break
+			}
+			if !isTrusted(ip, trusted) {
+				return ip, nil
+			}
+		}
+	}
+
+	if ip, err := parseHost(req.header("x-real-ip")); err == nil {
+		return ip, nil
+	}
+
+	return peerIP, nil
+}
+
+func parseHost(addr string) (netip.Addr, error) {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return ip.Unmap(), nil
+}
+
+func isTrusted(ip netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// UserKey limits by authenticated user ID. userID extracts it from the
+// request context, e.g. from JWT claims put there by the auth interceptor.
+// Неаутентифицированные запросы это правило не затрагивает.
+func UserKey(userID func(ctx context.Context) (int64, bool)) KeyFunc {
+	return func(req *Request) (string, error) {
+		id, ok := userID(req.Ctx)
+		if !ok {
+			return "", ErrNoKey
+		}
+		return "user:" + strconv.FormatInt(id, 10), nil
+	}
+}
+
+// HeaderKey limits by the value of a request header (gRPC metadata).
+func HeaderKey(kind, header string) KeyFunc {
+	return func(req *Request) (string, error) {
+		value := req.header(header)
+		if value == "" {
+			return "", ErrNoKey
+		}
+		return kind + ":" + value, nil
+	}
+}
+
+// ClientIDKey limits by API client ID from the x-client-id header.
+func ClientIDKey() KeyFunc {
+	return HeaderKey("client", DefaultClientIDHeader)
+}
+
+// TenantKey limits by school (tenant) ID from the x-school-id header.
+func TenantKey() KeyFunc {
+	return HeaderKey("school", DefaultTenantHeader)
+}
+
+// Compose joins several keys, e.g. per user within a tenant.
+// Если хотя бы одна часть недоступна, недоступен и весь ключ.
+func Compose(funcs ...KeyFunc) KeyFunc {
+	return func(req *Request) (string, error) {
+		parts := make([]string, 0, len(funcs))
+		for _, fn := range funcs {
+			part, err := fn(req)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, "|"), nil
+	}
+}
@@ -8,9 +8,20 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// Result описывает решение лимитера.
+type Result struct {
+	Allowed bool
+	// Remaining — сколько запросов ещё можно выполнить прямо сейчас.
+	Remaining int
+	// RetryAfter — через сколько стоит повторить запрос (только при отказе).
+	RetryAfter time.Duration
+	// ResetAfter — через сколько квота восстановится полностью.
+	ResetAfter time.Duration
+}
+
 // Limiter checks if a request is allowed.
 type Limiter interface {
-	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
 }
 
 // RedisLimiter implements sliding window rate limiting with Redis.
@@ -42,31 +53,64 @@ var slidingWindowScript = redis.NewScript(`
 	-- Считаем текущие запросы в окне
 	local count = redis.call('ZCARD', key)
 
+	local allowed = 0
 	if count < limit then
-    -- Добавляем элемент с составным member
-    redis.call('ZADD', key, now, now .. '-' .. micro_total .. '-' .. unique)
-    redis.call('PEXPIRE', key, window)
-    return 1
+		-- Добавляем элемент с составным member
+		redis.call('ZADD', key, now, now .. '-' .. micro_total .. '-' .. unique)
+		redis.call('PEXPIRE', key, window)
+		count = count + 1
+		allowed = 1
+	end
+
+	-- Место освободится, когда истечёт самая старая запись,
+	-- квота восстановится полностью — когда истечёт самая новая
+	local retry_after = 0
+	if allowed == 0 then
+		local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+		if oldest[2] then
+			retry_after = tonumber(oldest[2]) + window - now
+		end
+	end
+
+	local reset = 0
+	local newest = redis.call('ZRANGE', key, -1, -1, 'WITHSCORES')
+	if newest[2] then
+		reset = tonumber(newest[2]) + window - now
 	end
 
-	return 0
+	return {allowed, limit - count, retry_after, reset}
 `)
 
 // Allow returns true if request is within the rate limit.
-func (r *RedisLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+func (r *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
 	now := time.Now().UnixMilli()
-	windowMs := window.Milliseconds()
+	windowMs := limit.window.Milliseconds()
 
-	result, err := slidingWindowScript.Run(ctx, r.client,
+	values, err := slidingWindowScript.Run(ctx, r.client,
 		[]string{key},
 		windowMs,
-		limit,
+		limit.count,
 		now,
-	).Int()
+	).Int64Slice()
 
 	if err != nil {
-		return false, fmt.Errorf("rate limiter script: %w", err)
+		return Result{}, fmt.Errorf("rate limiter script: %w", err)
+	}
+
+	return parseScriptResult(values)
+}
+
+// parseScriptResult разбирает ответ скриптов вида
+// {allowed, remaining, retry_after_ms, reset_ms}.
+func parseScriptResult(values []int64) (Result, error) {
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("rate limiter script: unexpected result %v", values)
 	}
 
-	return result == 1, nil
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(max(values[1], 0)),
+		RetryAfter: time.Duration(max(values[2], 0)) * time.Millisecond,
+		ResetAfter: time.Duration(max(values[3], 0)) * time.Millisecond,
+	}, nil
 }
@@ -0,0 +1,253 @@
+package ratelimiterv1
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMemoryShards    = 32
+	defaultJanitorInterval = time.Minute
+)
+
+// Algorithm выбирает алгоритм in-memory лимитера.
+type Algorithm int
+
+const (
+	SlidingWindow Algorithm = iota
+	TokenBucket
+)
+
+type memoryConfig struct {
+	algorithm       Algorithm
+	shards          int
+	janitorInterval time.Duration
+	now             func() time.Time
+}
+
+type MemoryOption func(*memoryConfig)
+
+func WithAlgorithm(algorithm Algorithm) MemoryOption {
+	return func(c *memoryConfig) {
+		c.algorithm = algorithm
+	}
+}
+
+// WithShards задаёт число шардов; каждый шард защищён своим мьютексом.
+func WithShards(shards int) MemoryOption {
+	return func(c *memoryConfig) {
+		c.shards = shards
+	}
+}
+
+// WithJanitorInterval задаёт период очистки простаивающих ключей.
+// Ноль отключает фоновую очистку.
+func WithJanitorInterval(interval time.Duration) MemoryOption {
+	return func(c *memoryConfig) {
+		c.janitorInterval = interval
+	}
+}
+
+// WithClock подменяет источник времени (для детерминированных тестов).
+func WithClock(now func() time.Time) MemoryOption {
+	return func(c *memoryConfig) {
+		c.now = now
+	}
+}
+
+type memoryEntry struct {
+	// sliding window: времена запросов внутри окна, по возрастанию
+	hits []time.Time
+	// token bucket
+	tokens float64
+	last   time.Time
+
+	// после expiresAt состояние эквивалентно отсутствующему ключу
+	expiresAt time.Time
+}
+
+type memoryShard struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+// MemoryLimiter is an in-process Limiter for single-instance deployments,
+// tests and as a fallback when Redis is unavailable.
+type MemoryLimiter struct {
+	cfg    memoryConfig
+	shards []*memoryShard
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func NewMemoryLimiter(opts ...MemoryOption) *MemoryLimiter {
+	cfg := memoryConfig{
+		algorithm:       SlidingWindow,
+		shards:          defaultMemoryShards,
+		janitorInterval: defaultJanitorInterval,
+		now:             time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.shards < 1 {
+		cfg.shards = 1
+	}
+
+	l := &MemoryLimiter{
+		cfg:    cfg,
+		shards: make([]*memoryShard, cfg.shards),
+		stop:   make(chan struct{}),
+	}
+	for i := range l.shards {
+		l.shards[i] = &memoryShard{entries: make(map[string]*memoryEntry)}
+	}
+
+	if cfg.janitorInterval > 0 {
+		l.wg.Add(1)
+		go l.janitor()
+	}
+
+	return l
+}
+
+// Close stops the janitor goroutine.
+func (l *MemoryLimiter) Close() {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+	l.wg.Wait()
+}
+
+func (l *MemoryLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	now := l.cfg.now()
+	shard := l.shard(key)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	entry, ok := shard.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		entry = &memoryEntry{}
+		shard.entries[key] = entry
+	}
+
+	if l.cfg.algorithm == TokenBucket {
+		return allowTokenBucket(entry, limit, now), nil
+	}
+	return allowSlidingWindow(entry, limit, now), nil
+}
+
+func allowSlidingWindow(e *memoryEntry, limit Limit, now time.Time) Result {
+	// Удаляем записи старше окна
+	cutoff := now.Add(-limit.window)
+	i := 0
+	for i < len(e.hits) && !e.hits[i].After(cutoff) {
+		i++
+	}
+	e.hits = e.hits[i:]
+
+	res := Result{}
+	if len(e.hits) < limit.count {
+		e.hits = append(e.hits, now)
+		res.Allowed = true
+	} else {
+		res.RetryAfter = e.hits[0].Add(limit.window).Sub(now)
+	}
+
+	res.Remaining = limit.count - len(e.hits)
+	newest := e.hits[len(e.hits)-1]
+	res.ResetAfter = newest.Add(limit.window).Sub(now)
+	e.expiresAt = newest.Add(limit.window)
+
+	return res
+}
+
+func allowTokenBucket(e *memoryEntry, limit Limit, now time.Time) Result {
+	// токенов в наносекунду
+	rate := float64(limit.count) / float64(limit.window)
+	capacity := float64(limit.burst)
+
+	if e.last.IsZero() {
+		e.tokens = capacity
+		e.last = now
+	}
+
+	// Пополняем ведро за прошедшее время
+	elapsed := max(now.Sub(e.last), 0)
+	e.tokens = math.Min(capacity, e.tokens+float64(elapsed)*rate)
+	e.last = now
+
+	res := Result{}
+	if e.tokens >= 1 {
+		e.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration(math.Ceil((1 - e.tokens) / rate))
+	}
+
+	res.Remaining = int(e.tokens)
+	res.ResetAfter = time.Duration(math.Ceil((capacity - e.tokens) / rate))
+	e.expiresAt = now.Add(res.ResetAfter)
+
+	return res
+}
+
+func (l *MemoryLimiter) shard(key string) *memoryShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return l.shards[h.Sum32()%uint32(len(l.shards))]
+}
+
+// janitor удаляет ключи, состояние которых уже вернулось к исходному.
+func (l *MemoryLimiter) janitor() {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(l.cfg.janitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.evictIdle()
+		}
+	}
+}
+
+func (l *MemoryLimiter) evictIdle() {
+	now := l.cfg.now()
+
+	for _, shard := range l.shards {
+		shard.mu.Lock()
+		for key, entry := range shard.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(shard.entries, key)
+			}
+		}
+		shard.mu.Unlock()
+	}
+}
+
+// len returns the number of tracked keys.
+func (l *MemoryLimiter) len() int {
+	n := 0
+	for _, shard := range l.shards {
+		shard.mu.Lock()
+		n += len(shard.entries)
+		shard.mu.Unlock()
+	}
+	return n
+}
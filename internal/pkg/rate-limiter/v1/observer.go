@@ -0,0 +1,34 @@
+package ratelimiterv1
+
+// Outcome — результат проверки одного правила.
+type Outcome string
+
+const (
+	OutcomeAllowed Outcome = "allowed"
+	OutcomeDenied  Outcome = "denied"
+	// OutcomeError — лимитер вернул ошибку (решение принято по FailPolicy)
+	OutcomeError Outcome = "error"
+)
+
+// Observer получает результат каждого применённого правила, например для метрик.
+// Вызывается синхронно на каждом запросе, поэтому должен быть быстрым.
+type Observer interface {
+	ObserveRateLimit(method, rule string, outcome Outcome)
+}
+
+// SetObserver подключает наблюдателя; nil отключает.
+func (c *Config) SetObserver(o Observer) {
+	c.mu.Lock()
+	c.observer = o
+	c.mu.Unlock()
+}
+
+func (c *Config) observe(method, rule string, outcome Outcome) {
+	c.mu.RLock()
+	o := c.observer
+	c.mu.RUnlock()
+
+	if o != nil {
+		o.ObserveRateLimit(method, rule, outcome)
+	}
+}
@@ -0,0 +1,141 @@
+package ratelimiterv1
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LimitSpec — декларативное описание лимита (YAML / env).
+// Burst по умолчанию равен Count.
+type LimitSpec struct {
+	Count  int           `yaml:"count" env:"COUNT"`
+	Window time.Duration `yaml:"window" env:"WINDOW"`
+	Burst  int           `yaml:"burst" env:"BURST"`
+}
+
+func (s LimitSpec) isZero() bool {
+	return s.Count == 0 && s.Window == 0 && s.Burst == 0
+}
+
+func (s LimitSpec) limit() Limit {
+	if s.Burst == 0 {
+		return NewLimit(s.Count, s.Window)
+	}
+	return NewLimitWithBurst(s.Count, s.Window, s.Burst)
+}
+
+// MethodSpec переопределяет лимит и/или политику для одного метода.
+// Пустой лимит означает лимит по умолчанию.
+type MethodSpec struct {
+	LimitSpec  `yaml:",inline"`
+	FailPolicy FailPolicy `yaml:"failPolicy"`
+}
+
+// Spec — декларативная конфигурация лимитов, например:
+//
+//	default: {count: 100, window: 1m}
+//	failPolicy: open
+//	trustedProxies: ["10.0.0.0/8"]
+//	methods:
+//	  /sso.AuthService/Login: {count: 5, window: 1m, failPolicy: closed}
+//
+// Дополнительные правила (AddMethodRule) задаются только в коде: ключи по
+// пользователю или тенанту требуют функций, которые из файла не описать.
+type Spec struct {
+	Default        LimitSpec             `yaml:"default" env-prefix:"DEFAULT_"`
+	FailPolicy     FailPolicy            `yaml:"failPolicy" env:"FAIL_POLICY" env-default:"open"`
+	TrustedProxies []string              `yaml:"trustedProxies" env:"TRUSTED_PROXIES" env-separator:","`
+	Methods        map[string]MethodSpec `yaml:"methods"`
+}
+
+// Validate проверяет Spec и возвращает все найденные ошибки разом.
+func (s Spec) Validate() error {
+	var errs []error
+
+	if err := s.Default.limit().valid(); err != nil {
+		errs = append(errs, fmt.Errorf("default: %w", err))
+	}
+	if s.FailPolicy != "" && !s.FailPolicy.valid() {
+		errs = append(errs, fmt.Errorf("failPolicy: unknown value %q", s.FailPolicy))
+	}
+	if _, err := ParseTrustedProxies(s.TrustedProxies); err != nil {
+		errs = append(errs, fmt.Errorf("trustedProxies: %w", err))
+	}
+
+	for method, m := range s.Methods {
+		if method == "" {
+			errs = append(errs, errors.New("methods: empty method name"))
+		}
+		if !m.LimitSpec.isZero() {
+			if err := m.limit().valid(); err != nil {
+				errs = append(errs, fmt.Errorf("methods[%s]: %w", method, err))
+			}
+		}
+		if m.FailPolicy != "" && !m.FailPolicy.valid() {
+			errs = append(errs, fmt.Errorf("methods[%s].failPolicy: unknown value %q", method, m.FailPolicy))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// NewConfigFromSpec создаёт Config из декларативного описания.
+func NewConfigFromSpec(spec Spec) (*Config, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rate limit spec: %w", err)
+	}
+
+	cfg, err := NewConfig(spec.Default.Count, spec.Default.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Apply(spec); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Apply атомарно заменяет лимиты, политики и доверенные прокси на описанные
+// в spec; правила из AddMethodRule и ключ из SetKeyFunc сохраняются. При
+// ошибке валидации текущая конфигурация не меняется, поэтому Apply безопасно
+// вызывать при hot reload.
+func (c *Config) Apply(spec Spec) error {
+	if err := spec.Validate(); err != nil {
+		return fmt.Errorf("invalid rate limit spec: %w", err)
+	}
+
+	trusted, err := ParseTrustedProxies(spec.TrustedProxies)
+	if err != nil {
+		return err
+	}
+
+	defaultPolicy := spec.FailPolicy
+	if defaultPolicy == "" {
+		defaultPolicy = FailOpen
+	}
+
+	limits := make(map[string]Limit, len(spec.Methods))
+	policies := make(map[string]FailPolicy, len(spec.Methods))
+	for method, m := range spec.Methods {
+		if !m.LimitSpec.isZero() {
+			limits[method] = m.limit()
+		}
+		if m.FailPolicy != "" {
+			policies[method] = m.FailPolicy
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.defaultLimit = spec.Default.limit()
+	c.defaultPolicy = defaultPolicy
+	if !c.customKey {
+		c.keyFunc = IPKey(trusted...)
+	}
+	c.methodLimits = limits
+	c.methodPolicies = policies
+	return nil
+}
@@ -0,0 +1,133 @@
+//go:build integration
+
+package ratelimiterv1_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gorediscli "github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client/redistest"
+	ratelimiterv1 "github.com/Krokozabra213/schools_backend/internal/pkg/rate-limiter/v1"
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func setupRedis(t *testing.T, ctx context.Context) *redis.Client {
+	t.Helper()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "redis:7-alpine",
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForLog("Ready to accept connections").WithStartupTimeout(30 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { container.Terminate(ctx) })
+
+	host, _ := container.Host(ctx)
+	port, _ := container.MappedPort(ctx, "6379")
+
+	client := redis.NewClient(&redis.Options{Addr: host + ":" + port.Port()})
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestLimitersIntegration(t *testing.T) {
+	ctx := context.Background()
+	testLimiters(t, ctx, setupRedis(t, ctx))
+}
+
+// Скрипты обращаются только к KEYS[1], а ключи алгоритмов одного
+// счётчика делят hash tag, поэтому в кластере всё работает так же.
+func TestLimitersClusterIntegration(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := gorediscli.New(ctx,
+		gorediscli.WithCluster(redistest.StartCluster(t)),
+		gorediscli.WithPassword(redistest.Password),
+	)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	testLimiters(t, ctx, client)
+}
+
+func testLimiters(t *testing.T, ctx context.Context, client redis.Cmdable) {
+	t.Helper()
+
+	tests := []struct {
+		name    string
+		limiter ratelimiterv1.Limiter
+		limit   ratelimiterv1.Limit
+		allowed int
+	}{
+		{
+			name:    "sliding window",
+			limiter: ratelimiterv1.NewRedisLimiter(client),
+			limit:   ratelimiterv1.NewLimit(3, time.Second),
+			allowed: 3,
+		},
+		{
+			name:    "token bucket",
+			limiter: ratelimiterv1.NewTokenBucketLimiter(client),
+			limit:   ratelimiterv1.NewLimitWithBurst(10, time.Second, 3),
+			allowed: 3,
+		},
+		{
+			name:    "gcra",
+			limiter: ratelimiterv1.NewGCRALimiter(client),
+			limit:   ratelimiterv1.NewLimitWithBurst(10, time.Second, 3),
+			allowed: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := "test:" + gorediscli.HashTag(tt.name)
+
+			for i := 0; i < tt.allowed; i++ {
+				res, err := tt.limiter.Allow(ctx, key, tt.limit)
+				if err != nil {
+					t.Fatalf("Allow() error: %v", err)
+				}
+				if !res.Allowed {
+					t.Fatalf("request %d denied, want allowed", i)
+				}
+				if want := tt.allowed - i - 1; res.Remaining != want {
+					t.Errorf("request %d: got remaining %d, want %d", i, res.Remaining, want)
+				}
+			}
+
+			res, err := tt.limiter.Allow(ctx, key, tt.limit)
+			if err != nil {
+				t.Fatalf("Allow() error: %v", err)
+			}
+			if res.Allowed {
+				t.Fatal("request over burst allowed")
+			}
+			if res.RetryAfter <= 0 || res.RetryAfter > time.Second {
+				t.Errorf("got retry after %v, want (0, 1s]", res.RetryAfter)
+			}
+
+			time.Sleep(res.RetryAfter + 10*time.Millisecond)
+
+			res, err = tt.limiter.Allow(ctx, key, tt.limit)
+			if err != nil {
+				t.Fatalf("Allow() error: %v", err)
+			}
+			if !res.Allowed {
+				t.Error("request after retry-after denied")
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package ratelimiterv1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func newKeyRequest(peerAddr string, headers map[string][]string) *Request {
+	h := http.Header{}
+	for name, values := range headers {
+		for _, v := range values {
+			h.Add(name, v)
+		}
+	}
+
+	return &Request{
+		Ctx:      context.Background(),
+		Method:   "/svc/Method",
+		PeerAddr: peerAddr,
+		Header:   h.Values,
+	}
+}
+
+func TestIPKey(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		trusted bool
+		peer    string
+		headers map[string][]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "peer address",
+			peer: "203.0.113.5:5000",
+			want: "ip:203.0.113.5",
+		},
+		{
+			name:    "spoofed xff from untrusted peer ignored",
+			peer:    "203.0.113.5:5000",
+			headers: map[string][]string{"X-Forwarded-For": {"1.1.1.1"}},
+			want:    "ip:203.0.113.5",
+		},
+		{
+			name:    "xff without trusted proxies ignored",
+			trusted: false,
+			peer:    "10.0.0.2:5000",
+			headers: map[string][]string{"X-Forwarded-For": {"1.1.1.1"}},
+			want:    "ip:10.0.0.2",
+		},
+		{
+			name:    "rightmost untrusted xff hop",
+			trusted: true,
+			peer:    "10.0.0.2:5000",
+			headers: map[string][]string{"X-Forwarded-For": {"1.1.1.1, 198.51.100.7, 10.0.0.3"}},
+			want:    "ip:198.51.100.7",
+		},
+		{
+			name:    "multiple xff headers",
+			trusted: true,
+			peer:    "192.168.1.1:5000",
+			headers: map[string][]string{"X-Forwarded-For": {"1.1.1.1", "198.51.100.7"}},
+			want:    "ip:198.51.100.7",
+		},
+		{
+			name:    "x-real-ip from trusted peer",
+			trusted: true,
+			peer:    "10.0.0.2:5000",
+			headers: map[string][]string{"X-Real-IP": {"198.51.100.8"}},
+			want:    "ip:198.51.100.8",
+		},
+		{
+			name: "ipv6 peer",
+			peer: "[2001:db8::1]:5000",
+			want: "ip:2001:db8::1",
+		},
+		{
+			name: "unix socket peer",
+			peer: "/run/sso.sock",
+			want: "peer:/run/sso.sock",
+		},
+		{
+			name:    "no peer",
+			peer:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := IPKey()
+			if tt.trusted {
+				key = IPKey(trusted...)
+			}
+
+			got, err := key(newKeyRequest(tt.peer, tt.headers))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IPKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesInvalid(t *testing.T) {
+	if _, err := ParseTrustedProxies([]string{"not-a-cidr"}); err == nil {
+		t.Error("expected error for invalid cidr")
+	}
+}
+
+func TestUserKey(t *testing.T) {
+	key := UserKey(userFromContext)
+
+	req := newKeyRequest("203.0.113.5:5000", nil)
+	if _, err := key(req); !errors.Is(err, ErrNoKey) {
+		t.Errorf("got %v, want ErrNoKey for anonymous request", err)
+	}
+
+	req.Ctx = context.WithValue(context.Background(), userCtxKey{}, int64(42))
+	got, err := key(req)
+	if err != nil || got != "user:42" {
+		t.Errorf("got (%q, %v), want (%q, nil)", got, err, "user:42")
+	}
+}
+
+func TestComposeKey(t *testing.T) {
+	key := Compose(TenantKey(), ClientIDKey())
+
+	req := newKeyRequest("203.0.113.5:5000", map[string][]string{
+		DefaultTenantHeader:   {"7"},
+		DefaultClientIDHeader: {"mobile"},
+	})
+	got, err := key(req)
+	if err != nil || got != "school:7|client:mobile" {
+		t.Errorf("got (%q, %v), want (%q, nil)", got, err, "school:7|client:mobile")
+	}
+
+	req = newKeyRequest("203.0.113.5:5000", map[string][]string{DefaultTenantHeader: {"7"}})
+	if _, err := key(req); !errors.Is(err, ErrNoKey) {
+		t.Errorf("got %v, want ErrNoKey when a part is missing", err)
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	tests := []struct {
+		name string
+		part string
+		want string
+	}{
+		{"plain", "ip:203.0.113.5", "ratelimit:{ip:ip:203.0.113.5#/svc/Method}"},
+		{"braces in header", "client:a}b{c", "ratelimit:{ip:client:a%7Db%7Bc#/svc/Method}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rateLimitKey("ip", tt.part, "/svc/Method"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package ratelimiterv1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/testclock"
+)
+
+func TestMemorySlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	clock := testclock.New()
+	l := NewMemoryLimiter(WithClock(clock.Now), WithJanitorInterval(0))
+	defer l.Close()
+
+	limit := NewLimit(3, time.Second)
+
+	for i := 0; i < 3; i++ {
+		res, _ := l.Allow(ctx, "k", limit)
+		if !res.Allowed {
+			t.Fatalf("request %d denied, want allowed", i)
+		}
+		if res.Remaining != 2-i {
+			t.Errorf("request %d: got remaining %d, want %d", i, res.Remaining, 2-i)
+		}
+		clock.Advance(100 * time.Millisecond)
+	}
+
+	res, _ := l.Allow(ctx, "k", limit)
+	if res.Allowed {
+		t.Fatal("request over limit allowed")
+	}
+	// первый запрос был 300ms назад, окно освободится через 700ms
+	if res.RetryAfter != 700*time.Millisecond {
+		t.Errorf("got retry after %v, want 700ms", res.RetryAfter)
+	}
+
+	clock.Advance(res.RetryAfter)
+	if res, _ := l.Allow(ctx, "k", limit); !res.Allowed {
+		t.Error("request after retry-after denied")
+	}
+
+	if res, _ := l.Allow(ctx, "other", limit); !res.Allowed {
+		t.Error("keys must be limited independently")
+	}
+}
+
+func TestMemoryTokenBucket(t *testing.T) {
+	ctx := context.Background()
+	clock := testclock.New()
+	l := NewMemoryLimiter(WithAlgorithm(TokenBucket), WithClock(clock.Now), WithJanitorInterval(0))
+	defer l.Close()
+
+	// 10 rps, всплеск до 3
+	limit := NewLimitWithBurst(10, time.Second, 3)
+
+	for i := 0; i < 3; i++ {
+		if res, _ := l.Allow(ctx, "k", limit); !res.Allowed {
+			t.Fatalf("request %d denied, want allowed", i)
+		}
+	}
+
+	res, _ := l.Allow(ctx, "k", limit)
+	if res.Allowed {
+		t.Fatal("request over burst allowed")
+	}
+	if res.RetryAfter != 100*time.Millisecond {
+		t.Errorf("got retry after %v, want 100ms", res.RetryAfter)
+	}
+	if res.ResetAfter != 300*time.Millisecond {
+		t.Errorf("got reset after %v, want 300ms", res.ResetAfter)
+	}
+
+	clock.Advance(250 * time.Millisecond)
+	res, _ = l.Allow(ctx, "k", limit)
+	if !res.Allowed {
+		t.Fatal("request after refill denied")
+	}
+	if res.Remaining != 1 {
+		t.Errorf("got remaining %d, want 1", res.Remaining)
+	}
+}
+
+func TestMemoryEvictIdle(t *testing.T) {
+	ctx := context.Background()
+	clock := testclock.New()
+	l := NewMemoryLimiter(WithClock(clock.Now), WithJanitorInterval(0), WithShards(4))
+	defer l.Close()
+
+	l.Allow(ctx, "short", NewLimit(1, time.Second))
+	l.Allow(ctx, "long", NewLimit(1, time.Hour))
+
+	clock.Advance(2 * time.Second)
+	l.evictIdle()
+
+	if got := l.len(); got != 1 {
+		t.Errorf("got %d keys after eviction, want 1", got)
+	}
+}
+
+func TestMemoryCanceledContext(t *testing.T) {
+	l := NewMemoryLimiter(WithJanitorInterval(0))
+	defer l.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := l.Allow(ctx, "k", NewLimit(1, time.Second)); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	return Result{}, errors.New("redis unavailable")
+}
+
+func TestFallbackLimiter(t *testing.T) {
+	ctx := context.Background()
+	clock := testclock.New()
+	memory := NewMemoryLimiter(WithClock(clock.Now), WithJanitorInterval(0))
+	defer memory.Close()
+
+	l := NewFallbackLimiter(failingLimiter{}, memory, nil)
+	limit := NewLimit(1, time.Second)
+
+	res, err := l.Allow(ctx, "k", limit)
+	if err != nil || !res.Allowed {
+		t.Fatalf("got (%+v, %v), want allowed by fallback", res, err)
+	}
+
+	// fallback продолжает ограничивать, а не пропускает всё
+	res, err = l.Allow(ctx, "k", limit)
+	if err != nil || res.Allowed {
+		t.Fatalf("got (%+v, %v), want denied by fallback", res, err)
+	}
+}
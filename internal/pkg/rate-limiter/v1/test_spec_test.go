@@ -0,0 +1,108 @@
+package ratelimiterv1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpecValidate(t *testing.T) {
+	valid := LimitSpec{Count: 10, Window: time.Minute}
+
+	tests := []struct {
+		name    string
+		spec    Spec
+		wantErr bool
+	}{
+		{"valid", Spec{Default: valid}, false},
+		{"valid method policy only", Spec{
+			Default: valid,
+			Methods: map[string]MethodSpec{"/svc/Login": {FailPolicy: FailClosed}},
+		}, false},
+		{"empty default", Spec{}, true},
+		{"negative burst", Spec{Default: LimitSpec{Count: 10, Window: time.Minute, Burst: -1}}, true},
+		{"unknown policy", Spec{Default: valid, FailPolicy: "maybe"}, true},
+		{"bad trusted proxy", Spec{Default: valid, TrustedProxies: []string{"not-an-ip"}}, true},
+		{"method without window", Spec{
+			Default: valid,
+			Methods: map[string]MethodSpec{"/svc/Login": {LimitSpec: LimitSpec{Count: 5}}},
+		}, true},
+		{"unknown method policy", Spec{
+			Default: valid,
+			Methods: map[string]MethodSpec{"/svc/Login": {FailPolicy: "never"}},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigApply(t *testing.T) {
+	cfg, err := NewConfigFromSpec(Spec{
+		Default: LimitSpec{Count: 100, Window: time.Minute},
+		Methods: map[string]MethodSpec{
+			"/svc/Login": {LimitSpec: LimitSpec{Count: 5, Window: time.Minute, Burst: 2}, FailPolicy: FailClosed},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, policy := cfg.rulesFor("/svc/Login")
+	if got := rules[0].Limit; got != NewLimitWithBurst(5, time.Minute, 2) {
+		t.Errorf("got limit %+v, want method limit", got)
+	}
+	if policy != FailClosed {
+		t.Errorf("got policy %q, want %q", policy, FailClosed)
+	}
+
+	rules, policy = cfg.rulesFor("/svc/Other")
+	if got := rules[0].Limit; got != NewLimit(100, time.Minute) {
+		t.Errorf("got limit %+v, want default limit", got)
+	}
+	if policy != FailOpen {
+		t.Errorf("got policy %q, want %q", policy, FailOpen)
+	}
+
+	// невалидный spec не должен затирать текущую конфигурацию
+	if err := cfg.Apply(Spec{}); err == nil {
+		t.Fatal("got nil error for invalid spec")
+	}
+	if _, policy := cfg.rulesFor("/svc/Login"); policy != FailClosed {
+		t.Errorf("got policy %q after failed apply, want %q", policy, FailClosed)
+	}
+}
+
+func TestConfigApplyKeepsCustomKey(t *testing.T) {
+	cfg, err := NewConfig(100, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom := func(*Request) (string, error) { return "tenant:1", nil }
+	if err := cfg.SetKeyFunc(custom); err != nil {
+		t.Fatal(err)
+	}
+
+	// hot reload с доверенными прокси не должен вернуть ключ по IP
+	err = cfg.Apply(Spec{
+		Default:        LimitSpec{Count: 10, Window: time.Minute},
+		TrustedProxies: []string{"10.0.0.0/8"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, _ := cfg.rulesFor("/svc/Method")
+	key, err := rules[0].Key(newKeyRequest("10.0.0.1:1234", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "tenant:1" {
+		t.Errorf("got key %q, want %q", key, "tenant:1")
+	}
+}
@@ -0,0 +1,404 @@
+package ratelimiterv1
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/testclock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// transport прогоняет один запрос через конкретную обёртку (unary, stream, http)
+// и возвращает, дошёл ли он до хендлера, и выставленные заголовки.
+type transport interface {
+	call(t *testing.T, ip, method string) (passed bool, headers map[string]string)
+}
+
+type fakeTransportStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *fakeTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+type unaryTransport struct {
+	interceptor grpc.UnaryServerInterceptor
+}
+
+func (tr unaryTransport) call(t *testing.T, ip, method string) (bool, map[string]string) {
+	t.Helper()
+
+	sts := &fakeTransportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(peerContext(ip), sts)
+
+	passed := false
+	_, err := tr.interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req any) (any, error) {
+			passed = true
+			return nil, nil
+		},
+	)
+	checkGRPCError(t, passed, err)
+
+	return passed, flattenMD(sts.header)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+type streamTransport struct {
+	interceptor grpc.StreamServerInterceptor
+}
+
+func (tr streamTransport) call(t *testing.T, ip, method string) (bool, map[string]string) {
+	t.Helper()
+
+	ss := &fakeServerStream{
+		ctx: peerContext(ip),
+	}
+
+	passed := false
+	err := tr.interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: method},
+		func(srv any, stream grpc.ServerStream) error {
+			passed = true
+			return nil
+		},
+	)
+	checkGRPCError(t, passed, err)
+
+	return passed, flattenMD(ss.header)
+}
+
+type httpTransport struct {
+	middleware func(http.Handler) http.Handler
+}
+
+func (tr httpTransport) call(t *testing.T, ip, method string) (bool, map[string]string) {
+	t.Helper()
+
+	passed := false
+	h := tr.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		passed = true
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, method, nil)
+	req.RemoteAddr = ip + ":40000"
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if !passed && rec.Code != http.StatusTooManyRequests && rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("got status %d, want %d or %d", rec.Code, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+	}
+
+	headers := make(map[string]string)
+	for name := range rec.Header() {
+		headers[strings.ToLower(name)] = rec.Header().Get(name)
+	}
+	return passed, headers
+}
+
+func peerContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000},
+	})
+}
+
+func checkGRPCError(t *testing.T, passed bool, err error) {
+	t.Helper()
+
+	if passed {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		return
+	}
+	if code := status.Code(err); code != codes.ResourceExhausted && code != codes.Unavailable {
+		t.Errorf("got code %v, want %v or %v", code, codes.ResourceExhausted, codes.Unavailable)
+	}
+}
+
+func flattenMD(md metadata.MD) map[string]string {
+	headers := make(map[string]string)
+	for name, values := range md {
+		if len(values) > 0 {
+			headers[name] = values[0]
+		}
+	}
+	return headers
+}
+
+func newTransports(limiter Limiter, cfg Config) map[string]transport {
+	return map[string]transport{
+		"unary":  unaryTransport{UnaryInterceptor(limiter, cfg, nil)},
+		"stream": streamTransport{StreamInterceptor(limiter, cfg, nil)},
+		"http":   httpTransport{HTTPMiddleware(limiter, cfg, nil)},
+	}
+}
+
+func TestTransports(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, newTransport func(Limiter, Config) transport)
+	}{
+		{"denies over limit with headers", testDeniesOverLimit},
+		{"uses method limit", testMethodLimit},
+		{"limits ips independently", testIndependentIPs},
+		{"fails open on limiter error", testFailOpen},
+		{"fails closed on limiter error", testFailClosed},
+		{"applies reloaded spec", testApplySpec},
+		{"applies all method rules", testMultipleRules},
+	}
+
+	for name := range newTransports(failingLimiter{}, *newTestConfig(t, 1, time.Second)) {
+		t.Run(name, func(t *testing.T) {
+			newTransport := func(limiter Limiter, cfg Config) transport {
+				return newTransports(limiter, cfg)[name]
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					tt.run(t, newTransport)
+				})
+			}
+		})
+	}
+}
+
+func newTestConfig(t *testing.T, count int, window time.Duration) *Config {
+	t.Helper()
+
+	cfg, err := NewConfig(count, window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func newTestLimiter(t *testing.T) *MemoryLimiter {
+	t.Helper()
+
+	clock := testclock.New()
+	l := NewMemoryLimiter(WithClock(clock.Now), WithJanitorInterval(0))
+	t.Cleanup(l.Close)
+	return l
+}
+
+func testDeniesOverLimit(t *testing.T, newTransport func(Limiter, Config) transport) {
+	tr := newTransport(newTestLimiter(t), *newTestConfig(t, 2, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		passed, headers := tr.call(t, "10.0.0.1", "/svc/Method")
+		if !passed {
+			t.Fatalf("request %d denied, want allowed", i)
+		}
+		if headers["ratelimit-limit"] != "2" {
+			t.Errorf("got ratelimit-limit %q, want %q", headers["ratelimit-limit"], "2")
+		}
+	}
+
+	passed, headers := tr.call(t, "10.0.0.1", "/svc/Method")
+	if passed {
+		t.Fatal("request over limit allowed")
+	}
+	if headers["ratelimit-remaining"] != "0" {
+		t.Errorf("got ratelimit-remaining %q, want %q", headers["ratelimit-remaining"], "0")
+	}
+	if headers["retry-after"] != "60" {
+		t.Errorf("got retry-after %q, want %q", headers["retry-after"], "60")
+	}
+}
+
+func testMethodLimit(t *testing.T, newTransport func(Limiter, Config) transport) {
+	cfg := newTestConfig(t, 100, time.Minute)
+	if err := cfg.SetMethod("/svc/Login", 1, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	tr := newTransport(newTestLimiter(t), *cfg)
+
+	if passed, _ := tr.call(t, "10.0.0.1", "/svc/Login"); !passed {
+		t.Fatal("first login denied")
+	}
+	if passed, _ := tr.call(t, "10.0.0.1", "/svc/Login"); passed {
+		t.Error("second login allowed, want method limit applied")
+	}
+	if passed, _ := tr.call(t, "10.0.0.1", "/svc/Other"); !passed {
+		t.Error("other method denied, want default limit")
+	}
+}
+
+func testIndependentIPs(t *testing.T, newTransport func(Limiter, Config) transport) {
+	tr := newTransport(newTestLimiter(t), *newTestConfig(t, 1, time.Minute))
+
+	if passed, _ := tr.call(t, "10.0.0.1", "/svc/Method"); !passed {
+		t.Fatal("first ip denied")
+	}
+	if passed, _ := tr.call(t, "10.0.0.2", "/svc/Method"); !passed {
+		t.Error("second ip denied, want independent limit")
+	}
+}
+
+func testFailOpen(t *testing.T, newTransport func(Limiter, Config) transport) {
+	tr := newTransport(failingLimiter{}, *newTestConfig(t, 1, time.Minute))
+
+	passed, headers := tr.call(t, "10.0.0.1", "/svc/Method")
+	if !passed {
+		t.Fatal("request denied on limiter error, want fail open")
+	}
+	if len(headers) != 0 {
+		t.Errorf("got headers %v, want none", headers)
+	}
+}
+
+func testFailClosed(t *testing.T, newTransport func(Limiter, Config) transport) {
+	cfg := newTestConfig(t, 1, time.Minute)
+	if err := cfg.SetMethodFailPolicy("/svc/Login", FailClosed); err != nil {
+		t.Fatal(err)
+	}
+	tr := newTransport(failingLimiter{}, *cfg)
+
+	if passed, _ := tr.call(t, "10.0.0.1", "/svc/Login"); passed {
+		t.Error("login allowed on limiter error, want fail closed")
+	}
+	if passed, _ := tr.call(t, "10.0.0.1", "/svc/Other"); !passed {
+		t.Error("other method denied on limiter error, want fail open")
+	}
+}
+
+func testApplySpec(t *testing.T, newTransport func(Limiter, Config) transport) {
+	cfg := newTestConfig(t, 100, time.Minute)
+	tr := newTransport(newTestLimiter(t), *cfg)
+
+	// интерсептор уже создан: новый spec должен примениться к нему без пересоздания
+	err := cfg.Apply(Spec{
+		Default: LimitSpec{Count: 100, Window: time.Minute},
+		Methods: map[string]MethodSpec{
+			"/svc/Login": {LimitSpec: LimitSpec{Count: 1, Window: time.Minute}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if passed, _ := tr.call(t, "10.0.0.1", "/svc/Login"); !passed {
+		t.Fatal("first login denied")
+	}
+	if passed, _ := tr.call(t, "10.0.0.1", "/svc/Login"); passed {
+		t.Error("second login allowed, want reloaded method limit")
+	}
+}
+
+type userCtxKey struct{}
+
+func userFromContext(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(userCtxKey{}).(int64)
+	return id, ok
+}
+
+func testMultipleRules(t *testing.T, newTransport func(Limiter, Config) transport) {
+	cfg := newTestConfig(t, 100, time.Minute)
+	// анонимный запрос: правило по пользователю пропускается, остальные действуют
+	for _, rule := range []Rule{
+		{Name: "per-user", Key: UserKey(userFromContext), Limit: NewLimit(1, time.Minute)},
+		{Name: "per-ip-low", Key: IPKey(), Limit: NewLimit(2, time.Minute)},
+	} {
+		if err := cfg.AddMethodRule("/svc/Method", rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tr := newTransport(newTestLimiter(t), *cfg)
+
+	for i := 0; i < 2; i++ {
+		passed, headers := tr.call(t, "10.0.0.1", "/svc/Method")
+		if !passed {
+			t.Fatalf("request %d denied, want allowed", i)
+		}
+		// в заголовках самое строгое правило
+		if headers["ratelimit-limit"] != "2" {
+			t.Errorf("got ratelimit-limit %q, want %q", headers["ratelimit-limit"], "2")
+		}
+	}
+
+	if passed, _ := tr.call(t, "10.0.0.1", "/svc/Method"); passed {
+		t.Error("request allowed, want denied by additional rule")
+	}
+}
+
+// Слушатель на unix-сокете: адрес пира не IP, но запросы должны
+// проходить и ограничиваться общим лимитом, а не падать с ошибкой.
+func TestUnaryInterceptorUnixPeer(t *testing.T) {
+	interceptor := UnaryInterceptor(newTestLimiter(t), *newTestConfig(t, 1, time.Minute), nil)
+
+	call := func() error {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.UnixAddr{Name: "/run/sso.sock", Net: "unix"},
+		})
+		ctx = grpc.NewContextWithServerTransportStream(ctx, &fakeTransportStream{})
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Method"},
+			func(context.Context, any) (any, error) { return nil, nil },
+		)
+		return err
+	}
+
+	if err := call(); err != nil {
+		t.Fatalf("got error %v, want request allowed", err)
+	}
+	if got := status.Code(call()); got != codes.ResourceExhausted {
+		t.Errorf("got code %v, want %v", got, codes.ResourceExhausted)
+	}
+}
+
+type recordObserver struct{ methods []string }
+
+func (o *recordObserver) ObserveRateLimit(method, _ string, _ Outcome) {
+	o.methods = append(o.methods, method)
+}
+
+// Пути без настроек делят один лимит и одну метку: перебор путей
+// не обходит лимит и не плодит ключи и серии метрик.
+func TestHTTPMiddlewareUnknownPaths(t *testing.T) {
+	cfg := newTestConfig(t, 1, time.Minute)
+	if err := cfg.SetMethod("/v1/login", 1, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	observer := &recordObserver{}
+	cfg.SetObserver(observer)
+	tr := httpTransport{HTTPMiddleware(newTestLimiter(t), *cfg, nil)}
+
+	if passed, _ := tr.call(t, "10.0.0.1", "/v1/missing"); !passed {
+		t.Fatal("first unknown path denied")
+	}
+	if passed, _ := tr.call(t, "10.0.0.1", "/v1/missing/../x"); passed {
+		t.Error("path variant allowed, want shared limit")
+	}
+	if passed, _ := tr.call(t, "10.0.0.1", "/v1/login"); !passed {
+		t.Error("configured path denied, want own limit")
+	}
+
+	want := []string{OtherHTTPPath, OtherHTTPPath, "/v1/login"}
+	if strings.Join(observer.methods, " ") != strings.Join(want, " ") {
+		t.Errorf("got observed methods %q, want %q", observer.methods, want)
+	}
+}
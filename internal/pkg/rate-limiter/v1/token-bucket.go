@@ -0,0 +1,71 @@
+package ratelimiterv1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenBucketLimiter implements token bucket rate limiting with Redis.
+// Ведро ёмкостью burst пополняется со скоростью count/window,
+// состояние — один HASH на ключ, независимо от числа запросов.
+type TokenBucketLimiter struct {
+	client redis.Scripter
+}
+
+func NewTokenBucketLimiter(client redis.Cmdable) *TokenBucketLimiter {
+	return &TokenBucketLimiter{client: client}
+}
+
+var tokenBucketScript = redis.NewScript(`
+	local key = KEYS[1]
+	local rate = tonumber(ARGV[1])
+	local capacity = tonumber(ARGV[2])
+	local now = tonumber(ARGV[3])
+
+	local state = redis.call('HMGET', key, 'tokens', 'ts')
+	local tokens = tonumber(state[1]) or capacity
+	local ts = tonumber(state[2]) or now
+
+	-- Пополняем ведро за прошедшее время
+	local elapsed = math.max(0, now - ts)
+	tokens = math.min(capacity, tokens + elapsed * rate)
+
+	local allowed = 0
+	local retry_after = 0
+	if tokens >= 1 then
+		tokens = tokens - 1
+		allowed = 1
+	else
+		retry_after = math.ceil((1 - tokens) / rate)
+	end
+
+	-- Через reset ведро снова полное, состояние можно забыть
+	local reset = math.ceil((capacity - tokens) / rate)
+
+	redis.call('HSET', key, 'tokens', tostring(tokens), 'ts', now)
+	redis.call('PEXPIRE', key, math.max(reset, 1))
+
+	return {allowed, math.floor(tokens), retry_after, reset}
+`)
+
+func (l *TokenBucketLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now().UnixMilli()
+	// токенов в миллисекунду
+	rate := float64(limit.count) / float64(limit.window.Milliseconds())
+
+	values, err := tokenBucketScript.Run(ctx, l.client,
+		[]string{key + ":tb"},
+		rate,
+		limit.burst,
+		now,
+	).Int64Slice()
+
+	if err != nil {
+		return Result{}, fmt.Errorf("token bucket script: %w", err)
+	}
+
+	return parseScriptResult(values)
+}
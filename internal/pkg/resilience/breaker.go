@@ -0,0 +1,166 @@
+package resilience
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen — автомат разомкнут: операция не выполнялась.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// State — состояние автомата.
+type State int
+
+const (
+	// StateClosed — операции проходят, отказы подряд считаются.
+	StateClosed State = iota
+	// StateOpen — операции отклоняются с ErrCircuitOpen до конца cooldown.
+	StateOpen
+	// StateHalfOpen — после cooldown проходит одна пробная операция:
+	// успех замыкает автомат, отказ снова размыкает.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// outcome — результат операции, пропущенной автоматом.
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored — операция ничего не сказала о зависимости (например,
+	// вызывающий отменил ctx): слот пробы освобождается, состояние не меняется
+	outcomeIgnored
+)
+
+// Breaker размыкается после threshold отказов подряд, чтобы не ждать
+// таймаутов лежащей зависимости на каждом запросе и дать ей подняться.
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	onChange  func(name string, from, to State)
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newBreaker(cfg config) *Breaker {
+	return &Breaker{
+		name:      cfg.name,
+		threshold: cfg.breakerThreshold,
+		cooldown:  cfg.breakerCooldown,
+		now:       cfg.now,
+		onChange:  cfg.onStateChange,
+	}
+}
+
+// State возвращает текущее состояние; разомкнутый автомат с истёкшим
+// cooldown показывается как half-open.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// allow пропускает операцию или возвращает ErrCircuitOpen. Пропущенная
+// операция обязана сообщить результат через done.
+func (b *Breaker) allow() (done func(outcome), err error) {
+	b.mu.Lock()
+
+	from := b.state
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			b.mu.Unlock()
+			return nil, ErrCircuitOpen
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+
+	case StateHalfOpen:
+		// пробная операция уже идёт, остальные ждут её результата
+		if b.probing {
+			b.mu.Unlock()
+			return nil, ErrCircuitOpen
+		}
+		b.probing = true
+	}
+
+	probe := b.state == StateHalfOpen
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return func(o outcome) { b.record(probe, o) }, nil
+}
+
+func (b *Breaker) record(probe bool, o outcome) {
+	b.mu.Lock()
+
+	from := b.state
+	switch {
+	case probe && o == outcomeIgnored:
+		// зависимость не проверена: следующая операция станет новой пробой
+		b.probing = false
+
+	case o == outcomeIgnored:
+
+	case probe && o == outcomeFailure:
+		b.state = StateOpen
+		b.openedAt = b.now()
+		b.probing = false
+
+	case probe:
+		b.state = StateClosed
+		b.failures = 0
+		b.probing = false
+
+	case b.state != StateClosed:
+		// результат операции, начатой до размыкания: на состояние не влияет
+
+	case o == outcomeFailure:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.state = StateOpen
+			b.openedAt = b.now()
+			b.failures = 0
+		}
+
+	default:
+		b.failures = 0
+	}
+
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+func (b *Breaker) notify(from, to State) {
+	if from != to && b.onChange != nil {
+		b.onChange(b.name, from, to)
+	}
+}
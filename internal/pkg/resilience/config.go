@@ -0,0 +1,64 @@
+package resilience
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultMaxAttempts      = 3
+	defaultInitialBackoff   = 50 * time.Millisecond
+	defaultMaxBackoff       = time.Second
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 10 * time.Second
+)
+
+type config struct {
+	name           string
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	retryable      Classifier
+	failure        func(error) bool
+
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	onStateChange    func(name string, from, to State)
+
+	now func() time.Time
+}
+
+func defaultConfig() config {
+	return config{
+		maxAttempts:      defaultMaxAttempts,
+		initialBackoff:   defaultInitialBackoff,
+		maxBackoff:       defaultMaxBackoff,
+		retryable:        func(err error, idempotent bool) bool { return idempotent && IsTransient(err) },
+		failure:          IsTransient,
+		breakerThreshold: defaultBreakerThreshold,
+		breakerCooldown:  defaultBreakerCooldown,
+		now:              time.Now,
+	}
+}
+
+func (c config) valid() error {
+	if c.maxAttempts < 1 {
+		return errors.New("maxAttempts must be >= 1")
+	}
+	if c.initialBackoff < 0 {
+		return errors.New("initialBackoff must not be negative")
+	}
+	if c.maxBackoff < c.initialBackoff {
+		return errors.New("maxBackoff must be >= initialBackoff")
+	}
+	if c.retryable == nil || c.failure == nil {
+		return errors.New("classifier must not be nil")
+	}
+	if c.breakerThreshold < 0 {
+		return errors.New("breakerThreshold must not be negative")
+	}
+	if c.breakerThreshold > 0 && c.breakerCooldown <= 0 {
+		return errors.New("breakerCooldown must be positive")
+	}
+	return nil
+}
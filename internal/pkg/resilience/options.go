@@ -0,0 +1,62 @@
+package resilience
+
+import "time"
+
+type Option func(*config)
+
+// WithName задаёт имя политики для OnStateChange, например "postgres primary".
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithMaxAttempts задаёт число попыток вместе с первой; 1 отключает повторы.
+func WithMaxAttempts(attempts int) Option {
+	return func(c *config) {
+		c.maxAttempts = attempts
+	}
+}
+
+// WithBackoff задаёт паузу перед первым повтором и её предел: пауза
+// удваивается с каждой попыткой, половина её случайна.
+func WithBackoff(initial, max time.Duration) Option {
+	return func(c *config) {
+		c.initialBackoff = initial
+		c.maxBackoff = max
+	}
+}
+
+// WithClassifier задаёт, какие ошибки можно повторить. По умолчанию —
+// сетевые сбои (IsTransient) и только для идемпотентных операций.
+func WithClassifier(retryable Classifier) Option {
+	return func(c *config) {
+		c.retryable = retryable
+	}
+}
+
+// WithFailureClassifier задаёт, какие ошибки считаются отказом для
+// автомата. По умолчанию IsTransient: "не найдено" и нарушения
+// ограничений — ответ исправного сервера, а не отказ.
+func WithFailureClassifier(failure func(error) bool) Option {
+	return func(c *config) {
+		c.failure = failure
+	}
+}
+
+// WithBreaker задаёт автомат: после threshold отказов подряд он
+// размыкается на cooldown. Ноль threshold отключает автомат.
+func WithBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *config) {
+		c.breakerThreshold = threshold
+		c.breakerCooldown = cooldown
+	}
+}
+
+// WithOnStateChange вызывается при каждой смене состояния автомата.
+// Вызов синхронный, в горутине операции, сменившей состояние.
+func WithOnStateChange(fn func(name string, from, to State)) Option {
+	return func(c *config) {
+		c.onStateChange = fn
+	}
+}
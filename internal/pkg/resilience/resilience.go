@@ -0,0 +1,183 @@
+// Package resilience повторяет операции с хранилищами при временных сбоях
+// (экспоненциальная пауза с джиттером) и размыкает автомат, когда
+// зависимость лежит. Что считать временным сбоем, решает классификатор
+// клиента: pgxclient.IsRetryable, gorediscli.IsRetryable.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+)
+
+// Classifier решает, можно ли повторить операцию после err. idempotent —
+// повторное выполнение не меняет результат (чтение); для остальных
+// повтор допустим, только если операция точно не выполнилась.
+type Classifier func(err error, idempotent bool) bool
+
+type idempotentCtxKey struct{}
+
+// ContextWithIdempotent помечает операции в ctx как идемпотентные, например
+// UPDATE с фиксированными значениями: их можно повторять и после обрыва
+// соединения, когда неизвестно, дошла ли команда.
+func ContextWithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentCtxKey{}, true)
+}
+
+// IsIdempotent сообщает, помечен ли ctx через ContextWithIdempotent.
+func IsIdempotent(ctx context.Context) bool {
+	idempotent, _ := ctx.Value(idempotentCtxKey{}).(bool)
+	return idempotent
+}
+
+// Policy — повторы и автомат для одной зависимости (пула соединений).
+// Безопасна для конкурентного использования.
+type Policy struct {
+	cfg     config
+	breaker *Breaker
+}
+
+func New(opts ...Option) (*Policy, error) {
+	cfg := defaultConfig()
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := cfg.valid(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	p := &Policy{cfg: cfg}
+	if cfg.breakerThreshold > 0 {
+		p.breaker = newBreaker(cfg)
+	}
+	return p, nil
+}
+
+// Name возвращает имя, заданное WithName.
+func (p *Policy) Name() string {
+	return p.cfg.name
+}
+
+// Breaker возвращает автомат или nil, если он отключён.
+func (p *Policy) Breaker() *Breaker {
+	return p.breaker
+}
+
+// Do выполняет fn, повторяя её, пока классификатор считает ошибку
+// временной и не исчерпаны попытки. Каждая попытка проходит через
+// автомат; при разомкнутом автомате возвращается ErrCircuitOpen.
+func (p *Policy) Do(ctx context.Context, idempotent bool, fn func(ctx context.Context) error) error {
+	var last error
+	for attempt := 1; ; attempt++ {
+		err := p.try(ctx, fn)
+		if errors.Is(err, ErrCircuitOpen) && last != nil {
+			// автомат разомкнулся на наших же повторах: причина важнее
+			return fmt.Errorf("%w: %w", ErrCircuitOpen, last)
+		}
+		if err == nil || errors.Is(err, ErrCircuitOpen) {
+			return err
+		}
+		last = err
+		if attempt >= p.cfg.maxAttempts || ctx.Err() != nil || !p.cfg.retryable(err, idempotent) {
+			return err
+		}
+
+		if waitErr := sleepCtx(ctx, p.backoff(attempt)); waitErr != nil {
+			return errors.Join(err, waitErr)
+		}
+	}
+}
+
+func (p *Policy) try(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p.breaker == nil {
+		return fn(ctx)
+	}
+
+	done, err := p.breaker.allow()
+	if err != nil {
+		return err
+	}
+
+	// паника в fn — отказ; иначе слот пробы остался бы занят навсегда
+	result := outcomeFailure
+	defer func() { done(result) }()
+
+	err = fn(ctx)
+	switch {
+	case err == nil:
+		result = outcomeSuccess
+	case ctx.Err() != nil:
+		// отмена вызывающим — не отказ зависимости, но и не успех
+		result = outcomeIgnored
+	case p.cfg.failure(err):
+		result = outcomeFailure
+	default:
+		result = outcomeSuccess
+	}
+	return err
+}
+
+// backoff — пауза перед повтором после attempt-й попытки: удваивается
+// до maxBackoff, половина случайна, чтобы клиенты не повторяли синхронно.
+func (p *Policy) backoff(attempt int) time.Duration {
+	base := p.cfg.initialBackoff
+	for i := 1; i < attempt && base < p.cfg.maxBackoff; i++ {
+		base *= 2
+	}
+	base = min(base, p.cfg.maxBackoff)
+	if base <= 0 {
+		return 0
+	}
+	return base/2 + rand.N(base/2+1)
+}
+
+// IsTransient сообщает, похожа ли err на временный сетевой сбой: обрыв
+// или отказ в соединении, таймаут сокета. Отмена и дедлайн ctx
+// временным сбоем не считаются — их причина у вызывающего.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	switch {
+	case errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNABORTED),
+		errors.Is(err, syscall.EPIPE),
+		errors.Is(err, syscall.ETIMEDOUT),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, io.EOF):
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
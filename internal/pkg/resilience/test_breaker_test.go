@@ -0,0 +1,225 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/testclock"
+)
+
+type transition struct {
+	from, to State
+}
+
+func newTestBreaker(t *testing.T, threshold int, cooldown time.Duration) (*Policy, *testclock.Clock, *[]transition) {
+	t.Helper()
+
+	var (
+		mu          sync.Mutex
+		transitions []transition
+	)
+	p := newTestPolicy(t,
+		WithName("test"),
+		WithMaxAttempts(1),
+		WithBreaker(threshold, cooldown),
+		WithOnStateChange(func(name string, from, to State) {
+			if name != "test" {
+				t.Errorf("got name %q, want %q", name, "test")
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			transitions = append(transitions, transition{from, to})
+		}),
+	)
+
+	clock := testclock.New()
+	p.breaker.now = clock.Now
+	return p, clock, &transitions
+}
+
+func call(p *Policy, err error) (called bool, got error) {
+	got = p.Do(context.Background(), true, func(context.Context) error {
+		called = true
+		return err
+	})
+	return called, got
+}
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	p, clock, transitions := newTestBreaker(t, 3, 10*time.Second)
+
+	for range 2 {
+		call(p, errTransient)
+	}
+	if p.Breaker().State() != StateClosed {
+		t.Fatalf("got state %v after 2 failures, want closed", p.Breaker().State())
+	}
+
+	// успех обнуляет счётчик отказов подряд
+	call(p, nil)
+	call(p, errTransient)
+	call(p, errTransient)
+	if p.Breaker().State() != StateClosed {
+		t.Fatalf("got state %v, want closed: failures were not consecutive", p.Breaker().State())
+	}
+
+	call(p, errTransient)
+	if p.Breaker().State() != StateOpen {
+		t.Fatalf("got state %v after 3 failures, want open", p.Breaker().State())
+	}
+
+	called, err := call(p, nil)
+	if called || !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("open breaker: called %v, error %v; want not called, ErrCircuitOpen", called, err)
+	}
+
+	clock.Advance(10 * time.Second)
+	if p.Breaker().State() != StateHalfOpen {
+		t.Errorf("got state %v after cooldown, want half-open", p.Breaker().State())
+	}
+
+	if len(*transitions) != 1 || (*transitions)[0] != (transition{StateClosed, StateOpen}) {
+		t.Errorf("got transitions %v, want [closed->open]", *transitions)
+	}
+}
+
+func TestBreakerIgnoresPermanentErrors(t *testing.T) {
+	p, _, _ := newTestBreaker(t, 2, time.Second)
+
+	for range 5 {
+		call(p, errPermanent)
+	}
+	if p.Breaker().State() != StateClosed {
+		t.Errorf("got state %v, want closed: permanent errors are not failures", p.Breaker().State())
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name      string
+		probeErr  error
+		wantState State
+	}{
+		{"probe success closes", nil, StateClosed},
+		{"probe failure reopens", errTransient, StateOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, clock, transitions := newTestBreaker(t, 1, time.Second)
+
+			call(p, errTransient)
+			clock.Advance(time.Second)
+
+			// пока идёт пробная операция, остальные отклоняются
+			var concurrentErr error
+			err := p.Do(context.Background(), true, func(context.Context) error {
+				_, concurrentErr = call(p, nil)
+				return tt.probeErr
+			})
+			if !errors.Is(err, tt.probeErr) || (err == nil) != (tt.probeErr == nil) {
+				t.Fatalf("got probe error %v, want %v", err, tt.probeErr)
+			}
+			if !errors.Is(concurrentErr, ErrCircuitOpen) {
+				t.Errorf("got concurrent error %v, want ErrCircuitOpen", concurrentErr)
+			}
+
+			if got := p.Breaker().State(); got != tt.wantState {
+				t.Errorf("got state %v, want %v", got, tt.wantState)
+			}
+
+			want := []transition{{StateClosed, StateOpen}, {StateOpen, StateHalfOpen}, {StateHalfOpen, tt.wantState}}
+			if len(*transitions) != len(want) {
+				t.Fatalf("got transitions %v, want %v", *transitions, want)
+			}
+			for i := range want {
+				if (*transitions)[i] != want[i] {
+					t.Errorf("transition %d: got %v, want %v", i, (*transitions)[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerIgnoresCallerCancel(t *testing.T) {
+	p, _, _ := newTestBreaker(t, 1, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_ = p.Do(ctx, true, func(context.Context) error { return errTransient })
+	if p.Breaker().State() != StateClosed {
+		t.Errorf("got state %v, want closed: canceled call is not a failure", p.Breaker().State())
+	}
+}
+
+func TestBreakerProbeCanceled(t *testing.T) {
+	p, clock, _ := newTestBreaker(t, 1, time.Second)
+
+	call(p, errTransient)
+	clock.Advance(time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_ = p.Do(ctx, true, func(context.Context) error {
+		cancel()
+		return context.Canceled
+	})
+	// отменённая проба не проверила зависимость: автомат не замыкается,
+	// но следующая операция может стать новой пробой
+	if got := p.Breaker().State(); got != StateHalfOpen {
+		t.Fatalf("got state %v after canceled probe, want half-open", got)
+	}
+	if called, err := call(p, nil); !called || err != nil {
+		t.Fatalf("next probe: called %v, error %v; want called, nil", called, err)
+	}
+	if got := p.Breaker().State(); got != StateClosed {
+		t.Errorf("got state %v, want closed", got)
+	}
+}
+
+func TestBreakerProbePanics(t *testing.T) {
+	p, clock, _ := newTestBreaker(t, 1, time.Second)
+
+	call(p, errTransient)
+	clock.Advance(time.Second)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic was swallowed")
+			}
+		}()
+		_ = p.Do(context.Background(), true, func(context.Context) error {
+			panic("boom")
+		})
+	}()
+
+	// паника — отказ: автомат снова разомкнут, а не заперт в half-open
+	if got := p.Breaker().State(); got != StateOpen {
+		t.Fatalf("got state %v after panicking probe, want open", got)
+	}
+	clock.Advance(time.Second)
+	if called, err := call(p, nil); !called || err != nil {
+		t.Errorf("probe after cooldown: called %v, error %v; want called, nil", called, err)
+	}
+}
+
+func TestDoRetryStopsOnOpenBreaker(t *testing.T) {
+	p := newTestPolicy(t, WithMaxAttempts(5), WithBreaker(2, time.Minute))
+
+	attempts := 0
+	err := p.Do(context.Background(), true, func(context.Context) error {
+		attempts++
+		return errTransient
+	})
+
+	if !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, errTransient) {
+		t.Errorf("got error %v, want ErrCircuitOpen wrapping the last failure", err)
+	}
+	if attempts != 2 {
+		t.Errorf("got %d attempts, want 2: retries stop once the breaker opens", attempts)
+	}
+}
@@ -0,0 +1,179 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+var (
+	errTransient = &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	errPermanent = errors.New("duplicate key")
+)
+
+func newTestPolicy(t *testing.T, opts ...Option) *Policy {
+	t.Helper()
+
+	p, err := New(append([]Option{WithBackoff(0, 0), WithBreaker(0, 0)}, opts...)...)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	return p
+}
+
+func TestDoRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		idempotent   bool
+		errs         []error
+		wantAttempts int
+		wantErr      error
+	}{
+		{"success", true, []error{nil}, 1, nil},
+		{"transient then success", true, []error{errTransient, errTransient, nil}, 3, nil},
+		{"attempts exhausted", true, []error{errTransient, errTransient, errTransient, nil}, 3, errTransient},
+		{"permanent is not retried", true, []error{errPermanent, nil}, 1, errPermanent},
+		{"non-idempotent is not retried", false, []error{errTransient, nil}, 1, errTransient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPolicy(t)
+
+			attempts := 0
+			err := p.Do(context.Background(), tt.idempotent, func(context.Context) error {
+				err := tt.errs[attempts]
+				attempts++
+				return err
+			})
+
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("got %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestDoCustomClassifier(t *testing.T) {
+	retryable := errors.New("LOADING")
+	p := newTestPolicy(t, WithClassifier(func(err error, idempotent bool) bool {
+		return errors.Is(err, retryable)
+	}))
+
+	attempts := 0
+	err := p.Do(context.Background(), false, func(context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return retryable
+		}
+		return nil
+	})
+
+	if err != nil || attempts != 2 {
+		t.Errorf("got error %v after %d attempts, want nil after 2", err, attempts)
+	}
+}
+
+func TestDoStopsOnCanceledContext(t *testing.T) {
+	p := newTestPolicy(t, WithBackoff(time.Hour, time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	attempts := 0
+	err := p.Do(ctx, true, func(context.Context) error {
+		attempts++
+		return errTransient
+	})
+
+	if !errors.Is(err, errTransient) || !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want transient joined with context.Canceled", err)
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts, want 1", attempts)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := newTestPolicy(t, WithBackoff(100*time.Millisecond, time.Second))
+
+	tests := []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{50, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.attempt), func(t *testing.T) {
+			for range 100 {
+				d := p.backoff(tt.attempt)
+				if d < tt.base/2 || d > tt.base {
+					t.Fatalf("got %v, want in [%v, %v]", d, tt.base/2, tt.base)
+				}
+			}
+		})
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"connection reset", errTransient, true},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{"broken pipe", fmt.Errorf("write: %w", syscall.EPIPE), true},
+		{"unexpected EOF", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true},
+		{"socket timeout", &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}, true},
+		{"context canceled", fmt.Errorf("query: %w", context.Canceled), false},
+		{"context deadline", context.DeadlineExceeded, false},
+		{"application error", errPermanent, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"zero attempts", []Option{WithMaxAttempts(0)}},
+		{"negative backoff", []Option{WithBackoff(-time.Second, time.Second)}},
+		{"max below initial", []Option{WithBackoff(time.Second, time.Millisecond)}},
+		{"nil classifier", []Option{WithClassifier(nil)}},
+		{"negative threshold", []Option{WithBreaker(-1, time.Second)}},
+		{"breaker without cooldown", []Option{WithBreaker(5, 0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.opts...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
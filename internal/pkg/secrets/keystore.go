@@ -0,0 +1,249 @@
+package secrets
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keystoreVersion = 1
+
+	// параметры scrypt из рекомендаций для интерактивного входа (2017)
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 16
+	nonceLen     = 24
+)
+
+// ErrWrongPassphrase — файл не расшифровывается: неверная парольная фраза
+// или файл повреждён (secretbox не различает эти случаи).
+var ErrWrongPassphrase = errors.New("wrong keystore passphrase or corrupted file")
+
+// keystoreFile — формат файла на диске. Соль и nonce меняются при каждом
+// сохранении.
+type keystoreFile struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Box     []byte `json:"box"`
+}
+
+// Keystore — локальный файл секретов, зашифрованный парольной фразой
+// (scrypt + NaCl secretbox). Реализует Provider.
+type Keystore struct {
+	path       string
+	passphrase Secret
+
+	mu      sync.RWMutex
+	secrets map[string][]byte
+}
+
+var _ Provider = (*Keystore)(nil)
+
+// NewKeystore создаёт пустое хранилище. Файл появится после Save.
+func NewKeystore(path string, passphrase Secret) (*Keystore, error) {
+	if passphrase.IsZero() {
+		return nil, errors.New("empty keystore passphrase")
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("keystore %s already exists", path)
+	}
+
+	return &Keystore{
+		path:       path,
+		passphrase: passphrase,
+		secrets:    make(map[string][]byte),
+	}, nil
+}
+
+// OpenKeystore читает и расшифровывает существующее хранилище.
+func OpenKeystore(path string, passphrase Secret) (*Keystore, error) {
+	if passphrase.IsZero() {
+		return nil, errors.New("empty keystore passphrase")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read keystore: %w", err)
+	}
+
+	var file keystoreFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode keystore: %w", err)
+	}
+	if file.Version != keystoreVersion || file.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported keystore version %d (kdf %q)", file.Version, file.KDF)
+	}
+	if len(file.Salt) != saltLen || len(file.Nonce) != nonceLen {
+		return nil, ErrWrongPassphrase
+	}
+
+	key, err := deriveKey(passphrase, file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key[:])
+
+	var nonce [nonceLen]byte
+	copy(nonce[:], file.Nonce)
+
+	plain, ok := secretbox.Open(nil, file.Box, &nonce, key)
+	if !ok {
+		return nil, ErrWrongPassphrase
+	}
+	defer clear(plain)
+
+	secrets := make(map[string][]byte)
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("decode keystore payload: %w", err)
+	}
+
+	return &Keystore{
+		path:       path,
+		passphrase: passphrase,
+		secrets:    secrets,
+	}, nil
+}
+
+func (k *Keystore) Secret(_ context.Context, name string) (Secret, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	value, ok := k.secrets[name]
+	if !ok {
+		return Secret{}, ErrNotFound
+	}
+	return New(value), nil
+}
+
+// Set добавляет или заменяет секрет в памяти; на диск попадает после Save.
+func (k *Keystore) Set(name string, value Secret) error {
+	if name == "" {
+		return errors.New("empty secret name")
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.secrets[name] = value.Bytes()
+	return nil
+}
+
+// Delete удаляет секрет в памяти; на диск попадает после Save.
+func (k *Keystore) Delete(name string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	value, ok := k.secrets[name]
+	clear(value)
+	delete(k.secrets, name)
+	return ok
+}
+
+// Names возвращает отсортированные имена секретов.
+func (k *Keystore) Names() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	names := make([]string, 0, len(k.secrets))
+	for name := range k.secrets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Save шифрует хранилище и атомарно заменяет файл (права 0600).
+func (k *Keystore) Save() error {
+	k.mu.RLock()
+	plain, err := json.Marshal(k.secrets)
+	k.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("encode keystore payload: %w", err)
+	}
+	defer clear(plain)
+
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("generate salt: %w", err)
+	}
+	var nonce [nonceLen]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return fmt.Errorf("generate nonce: %w", err)
+	}
+
+	key, err := deriveKey(k.passphrase, salt)
+	if err != nil {
+		return err
+	}
+	defer clear(key[:])
+
+	data, err := json.MarshalIndent(keystoreFile{
+		Version: keystoreVersion,
+		KDF:     "scrypt",
+		Salt:    salt,
+		Nonce:   nonce[:],
+		Box:     secretbox.Seal(nil, plain, &nonce, key),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode keystore: %w", err)
+	}
+
+	return writeFileAtomic(k.path, data, 0o600)
+}
+
+func deriveKey(passphrase Secret, salt []byte) (*[scryptKeyLen]byte, error) {
+	derived, err := scrypt.Key(passphrase.value, salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("derive keystore key: %w", err)
+	}
+	defer clear(derived)
+
+	var key [scryptKeyLen]byte
+	copy(key[:], derived)
+	return &key, nil
+}
+
+// writeFileAtomic пишет во временный файл рядом и переименовывает: при
+// падении посередине старое хранилище остаётся целым.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp keystore: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("chmod temp keystore: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write temp keystore: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("sync temp keystore: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp keystore: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace keystore: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotFound — у провайдера нет секрета с таким именем.
+var ErrNotFound = errors.New("secret not found")
+
+// Provider возвращает секрет по имени. Отсутствие секрета — ErrNotFound,
+// любая другая ошибка означает, что источник недоступен.
+type Provider interface {
+	Secret(ctx context.Context, name string) (Secret, error)
+}
+
+// EnvProvider читает секрет из переменной окружения prefix+name, а если она
+// не задана — из файла, путь к которому лежит в prefix+name+"_FILE"
+// (соглашение Docker secrets). Заданы обе — ошибка.
+type EnvProvider struct {
+	prefix string
+}
+
+func NewEnvProvider(prefix string) *EnvProvider {
+	return &EnvProvider{prefix: prefix}
+}
+
+func (p *EnvProvider) Secret(_ context.Context, name string) (Secret, error) {
+	key := p.prefix + name
+
+	value, hasValue := os.LookupEnv(key)
+	path, hasFile := os.LookupEnv(key + "_FILE")
+
+	switch {
+	case hasValue && hasFile:
+		return Secret{}, fmt.Errorf("both %s and %s_FILE are set", key, key)
+	case hasValue:
+		return NewString(value), nil
+	case hasFile:
+		// путь задан явно: отсутствие файла — ошибка, а не повод искать дальше
+		s, err := readFile(path)
+		if errors.Is(err, ErrNotFound) {
+			return Secret{}, fmt.Errorf("%s_FILE: file %s does not exist", key, path)
+		}
+		return s, err
+	default:
+		return Secret{}, ErrNotFound
+	}
+}
+
+// FileProvider читает секрет из файла dir/name — например, каталог
+// /run/secrets в Docker или смонтированный Secret в Kubernetes.
+type FileProvider struct {
+	dir string
+}
+
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+func (p *FileProvider) Secret(_ context.Context, name string) (Secret, error) {
+	// имя — это имя файла, а не путь: не даём выйти за пределы dir
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return Secret{}, fmt.Errorf("invalid secret name %q", name)
+	}
+
+	return readFile(filepath.Join(p.dir, name))
+}
+
+// readFile читает секрет из файла, отбрасывая завершающий перевод строки,
+// который добавляют echo и редакторы.
+func readFile(path string) (Secret, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Secret{}, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return Secret{}, fmt.Errorf("read secret file: %w", err)
+	}
+	defer clear(data)
+
+	return New(bytes.TrimRight(data, "\r\n")), nil
+}
+
+// Chain опрашивает провайдеров по порядку и возвращает первый найденный
+// секрет. Ошибка, отличная от ErrNotFound, прерывает поиск: недоступный
+// источник не должен молча подменяться следующим.
+type Chain []Provider
+
+func (c Chain) Secret(ctx context.Context, name string) (Secret, error) {
+	for _, p := range c {
+		s, err := p.Secret(ctx, name)
+		if err == nil {
+			return s, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return Secret{}, err
+		}
+	}
+	return Secret{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+}
@@ -0,0 +1,80 @@
+// Package secrets loads credentials and keys from the environment, files or
+// an encrypted keystore, and keeps them out of logs.
+package secrets
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+const redacted = "[REDACTED]"
+
+// Secret хранит чувствительное значение. Любой вывод — fmt, slog, JSON,
+// YAML — показывает [REDACTED]; само значение доступно только явно
+// через Reveal или Bytes.
+type Secret struct {
+	value []byte
+}
+
+// New копирует b, чтобы вызывающий мог затереть свой буфер.
+func New(b []byte) Secret {
+	if len(b) == 0 {
+		return Secret{}
+	}
+	return Secret{value: append([]byte(nil), b...)}
+}
+
+func NewString(s string) Secret {
+	return New([]byte(s))
+}
+
+// Reveal возвращает значение как строку.
+func (s Secret) Reveal() string {
+	return string(s.value)
+}
+
+// Bytes возвращает копию значения.
+func (s Secret) Bytes() []byte {
+	return append([]byte(nil), s.value...)
+}
+
+func (s Secret) IsZero() bool {
+	return len(s.value) == 0
+}
+
+func (s Secret) Len() int {
+	return len(s.value)
+}
+
+// Equal сравнивает значения за постоянное время.
+func (s Secret) Equal(other Secret) bool {
+	return subtle.ConstantTimeCompare(s.value, other.value) == 1
+}
+
+func (s Secret) String() string {
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return "secrets.Secret(" + redacted + ")"
+}
+
+// Format перекрывает все глаголы fmt, включая %x и %+v у вложенных структур.
+func (s Secret) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		_, _ = io.WriteString(f, s.GoString())
+		return
+	}
+	_, _ = io.WriteString(f, redacted)
+}
+
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// MarshalText используется encoding/json, yaml и т.п.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
@@ -0,0 +1,102 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "sso.keystore")
+	passphrase := NewString("correct horse battery staple")
+
+	ks, err := NewKeystore(path, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Set("SSO_POSTGRES_PASSWORD", NewString("pg-secret")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Set("SSO_APP_SECRET", NewString("app-secret")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("got perm %o, want 600", perm)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("pg-secret")) {
+		t.Error("keystore file contains plaintext secret")
+	}
+
+	opened, err := OpenKeystore(path, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := opened.Secret(ctx, "SSO_POSTGRES_PASSWORD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Reveal() != "pg-secret" {
+		t.Errorf("got %q, want %q", got.Reveal(), "pg-secret")
+	}
+	if names := opened.Names(); len(names) != 2 || names[0] != "SSO_APP_SECRET" {
+		t.Errorf("got names %v, want sorted two names", names)
+	}
+	if _, err := opened.Secret(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want %v", err, ErrNotFound)
+	}
+
+	// удаление переживает повторное сохранение
+	if !opened.Delete("SSO_APP_SECRET") {
+		t.Error("got false deleting existing secret")
+	}
+	if err := opened.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := OpenKeystore(path, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := reopened.Names(); len(names) != 1 {
+		t.Errorf("got names %v, want one name", names)
+	}
+}
+
+func TestKeystoreWrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sso.keystore")
+
+	ks, err := NewKeystore(path, NewString("right"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenKeystore(path, NewString("wrong")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("got %v, want %v", err, ErrWrongPassphrase)
+	}
+	if _, err := NewKeystore(path, NewString("right")); err == nil {
+		t.Error("got nil error creating keystore over existing file")
+	}
+	if _, err := OpenKeystore(path, Secret{}); err == nil {
+		t.Error("got nil error for empty passphrase")
+	}
+}
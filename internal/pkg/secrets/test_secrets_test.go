@@ -0,0 +1,181 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecretRedacted(t *testing.T) {
+	s := NewString("hunter2")
+
+	type wrapper struct {
+		Password Secret
+	}
+
+	var logs bytes.Buffer
+	slog.New(slog.NewJSONHandler(&logs, nil)).Info("msg", slog.Any("password", s))
+
+	jsonOut, err := json.Marshal(wrapper{Password: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := map[string]string{
+		"%s":   fmt.Sprintf("%s", s),
+		"%v":   fmt.Sprintf("%v", s),
+		"%x":   fmt.Sprintf("%x", s),
+		"%q":   fmt.Sprintf("%q", s),
+		"%#v":  fmt.Sprintf("%#v", s),
+		"%+v":  fmt.Sprintf("%+v", wrapper{Password: s}),
+		"json": string(jsonOut),
+		"slog": logs.String(),
+	}
+	for name, out := range outputs {
+		if strings.Contains(out, "hunter2") || !strings.Contains(out, redacted) {
+			t.Errorf("%s: got %q, want redacted", name, out)
+		}
+	}
+
+	if s.Reveal() != "hunter2" {
+		t.Errorf("got %q, want %q", s.Reveal(), "hunter2")
+	}
+}
+
+func TestSecretCopies(t *testing.T) {
+	buf := []byte("value")
+	s := New(buf)
+	clear(buf)
+
+	b := s.Bytes()
+	clear(b)
+
+	if s.Reveal() != "value" {
+		t.Errorf("got %q, want %q", s.Reveal(), "value")
+	}
+}
+
+func TestEnvProvider(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "password")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    string
+		wantErr error
+	}{
+		{name: "value", env: map[string]string{"APP_PASSWORD": "from-env"}, want: "from-env"},
+		{name: "file", env: map[string]string{"APP_PASSWORD_FILE": file}, want: "from-file"},
+		{name: "missing", wantErr: ErrNotFound},
+		{name: "both", env: map[string]string{"APP_PASSWORD": "x", "APP_PASSWORD_FILE": file}, wantErr: errAny},
+		{name: "missing file", env: map[string]string{"APP_PASSWORD_FILE": filepath.Join(dir, "nope")}, wantErr: errAny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			got, err := NewEnvProvider("APP_").Secret(ctx, "PASSWORD")
+			checkErr(t, err, tt.wantErr)
+			if err == nil && got.Reveal() != tt.want {
+				t.Errorf("got %q, want %q", got.Reveal(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "db_password"), []byte("secret\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p := NewFileProvider(dir)
+
+	got, err := p.Secret(ctx, "db_password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Reveal() != "secret" {
+		t.Errorf("got %q, want %q", got.Reveal(), "secret")
+	}
+
+	if _, err := p.Secret(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want %v", err, ErrNotFound)
+	}
+	if _, err := p.Secret(ctx, "../db_password"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want invalid name error", err)
+	}
+}
+
+type staticProvider map[string]string
+
+func (p staticProvider) Secret(_ context.Context, name string) (Secret, error) {
+	if name == "broken" {
+		return Secret{}, errors.New("source unavailable")
+	}
+	v, ok := p[name]
+	if !ok {
+		return Secret{}, ErrNotFound
+	}
+	return NewString(v), nil
+}
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	chain := Chain{
+		staticProvider{"a": "first"},
+		staticProvider{"a": "second", "b": "second"},
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+	}{
+		{name: "a", want: "first"},
+		{name: "b", want: "second"},
+		{name: "c", wantErr: ErrNotFound},
+		{name: "broken", wantErr: errAny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := chain.Secret(ctx, tt.name)
+			checkErr(t, err, tt.wantErr)
+			if err == nil && got.Reveal() != tt.want {
+				t.Errorf("got %q, want %q", got.Reveal(), tt.want)
+			}
+		})
+	}
+}
+
+// errAny — ожидается любая ошибка, кроме ErrNotFound.
+var errAny = errors.New("any error")
+
+func checkErr(t *testing.T, err, want error) {
+	t.Helper()
+
+	switch {
+	case want == nil && err != nil:
+		t.Fatalf("got error %v, want nil", err)
+	case want == errAny && (err == nil || errors.Is(err, ErrNotFound)):
+		t.Fatalf("got error %v, want non-NotFound error", err)
+	case want != nil && want != errAny && !errors.Is(err, want):
+		t.Fatalf("got error %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,36 @@
+// Package testclock — управляемые часы для тестов пакетов с опцией WithClock.
+package testclock
+
+import (
+	"sync"
+	"time"
+)
+
+// Start — время, с которого идут часы из New.
+var Start = time.Unix(1_700_000_000, 0)
+
+// Clock — часы, которые идут только по Advance. Безопасны для
+// конкурентного использования.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// New возвращает часы, выставленные на Start.
+func New() *Clock {
+	return &Clock{now: Start}
+}
+
+// Now подходит для WithClock(clock.Now).
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance сдвигает часы вперёд на d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,148 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/tls-config/tlstest"
+)
+
+// handshake подключает клиента к TLS серверу на loopback и возвращает ошибку клиента.
+func handshake(t *testing.T, client, server *tls.Config) error {
+	t.Helper()
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", server)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.(*tls.Conn).Handshake()
+	}()
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), client)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// в TLS 1.3 отказ сервера в клиентском сертификате приходит алертом
+	// уже после завершения рукопожатия на стороне клиента
+	_, err = conn.Read(make([]byte, 1))
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+func TestBuildHandshake(t *testing.T) {
+	ca := tlstest.NewCA(t, "test CA")
+	other := tlstest.NewCA(t, "other CA")
+
+	caFile := ca.CAFile(t)
+	otherFile := other.CAFile(t)
+	certFile, keyFile := ca.IssueFiles(t, "client")
+
+	server := ca.ServerConfig(t, nil, "db.internal", "127.0.0.1")
+	mtlsServer := ca.ServerConfig(t, ca.Pool, "db.internal")
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		server  *tls.Config
+		wantErr bool
+	}{
+		{"full", Config{Verify: VerifyFull, CAFile: caFile, ServerName: "db.internal"}, server, false},
+		{"full by ip", Config{Verify: VerifyFull, CAFile: caFile, ServerName: "127.0.0.1"}, server, false},
+		{"full wrong name", Config{Verify: VerifyFull, CAFile: caFile, ServerName: "evil.internal"}, server, true},
+		{"full unknown CA", Config{Verify: VerifyFull, CAFile: otherFile, ServerName: "db.internal"}, server, true},
+		{"ca ignores name", Config{Verify: VerifyCA, CAFile: caFile, ServerName: "evil.internal"}, server, false},
+		{"ca unknown CA", Config{Verify: VerifyCA, CAFile: otherFile, ServerName: "db.internal"}, server, true},
+		{"none accepts anything", Config{Verify: VerifyNone, CAFile: otherFile}, server, false},
+		{"mtls", Config{Verify: VerifyFull, CAFile: caFile, CertFile: certFile, KeyFile: keyFile, ServerName: "db.internal"}, mtlsServer, false},
+		{"mtls without client cert", Config{Verify: VerifyFull, CAFile: caFile, ServerName: "db.internal"}, mtlsServer, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := tt.cfg.Build()
+			if err != nil {
+				t.Fatalf("Build() error: %v", err)
+			}
+
+			err = handshake(t, client, tt.server)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("handshake error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	ca := tlstest.NewCA(t, "test CA")
+	certFile, keyFile := ca.IssueFiles(t, "client")
+	missing := filepath.Join(t.TempDir(), "missing.crt")
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"full without server name", Config{Verify: VerifyFull}},
+		{"cert without key", Config{CertFile: certFile}},
+		{"key without cert", Config{KeyFile: keyFile}},
+		{"missing CA file", Config{CAFile: missing}},
+		{"CA file is not PEM certificate", Config{CAFile: keyFile}},
+		{"key does not match", Config{CertFile: certFile, KeyFile: certFile}},
+		{"unknown verify", Config{Verify: Verify(42)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.cfg.Build(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestBuildVerifyModes(t *testing.T) {
+	tests := []struct {
+		verify       Verify
+		wantInsecure bool
+		wantCustom   bool
+	}{
+		{VerifyNone, true, false},
+		{VerifyCA, true, true},
+		{VerifyFull, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.verify.String(), func(t *testing.T) {
+			cfg, err := Config{Verify: tt.verify, ServerName: "db.internal"}.Build()
+			if err != nil {
+				t.Fatalf("Build() error: %v", err)
+			}
+			if cfg.InsecureSkipVerify != tt.wantInsecure {
+				t.Errorf("got InsecureSkipVerify %v, want %v", cfg.InsecureSkipVerify, tt.wantInsecure)
+			}
+			if (cfg.VerifyConnection != nil) != tt.wantCustom {
+				t.Errorf("got custom verification %v, want %v", cfg.VerifyConnection != nil, tt.wantCustom)
+			}
+			if cfg.ServerName != "db.internal" {
+				t.Errorf("got ServerName %q, want %q", cfg.ServerName, "db.internal")
+			}
+			if cfg.MinVersion != tls.VersionTLS12 {
+				t.Errorf("got MinVersion %x, want TLS 1.2", cfg.MinVersion)
+			}
+		})
+	}
+}
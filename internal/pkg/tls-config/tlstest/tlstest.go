@@ -0,0 +1,153 @@
+// Package tlstest выпускает сертификаты для тестов TLS: свой CA,
+// серверные и клиентские сертификаты, записанные в PEM файлы.
+package tlstest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// CA — самоподписанный центр сертификации, живущий один тест.
+type CA struct {
+	Cert *x509.Certificate
+	Pool *x509.CertPool
+
+	key *ecdsa.PrivateKey
+	dir string
+}
+
+func NewCA(t testing.TB, name string) *CA {
+	t.Helper()
+
+	key := newKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial(t),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create CA certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse CA certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return &CA{Cert: cert, Pool: pool, key: key, dir: t.TempDir()}
+}
+
+// CAFile записывает сертификат CA и возвращает путь.
+func (ca *CA) CAFile(t testing.TB) string {
+	t.Helper()
+	return writePEM(t, filepath.Join(ca.dir, "ca.crt"), "CERTIFICATE", ca.Cert.Raw)
+}
+
+// Issue выпускает сертификат для hosts (DNS имена или IP) с назначением
+// и сервера, и клиента.
+func (ca *CA) Issue(t testing.TB, hosts ...string) tls.Certificate {
+	t.Helper()
+
+	key := newKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial(t),
+		Subject:      pkix.Name{CommonName: "tlstest"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.Cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// IssueFiles выпускает сертификат как Issue и записывает его и ключ в PEM.
+func (ca *CA) IssueFiles(t testing.TB, name string, hosts ...string) (certFile, keyFile string) {
+	t.Helper()
+
+	cert := ca.Issue(t, hosts...)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	certFile = writePEM(t, filepath.Join(ca.dir, name+".crt"), "CERTIFICATE", cert.Certificate[0])
+	keyFile = writePEM(t, filepath.Join(ca.dir, name+".key"), "PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+// ServerConfig — конфиг сервера с сертификатом для hosts. Если clientCAs
+// не nil, сервер требует клиентский сертификат, подписанный им.
+func (ca *CA) ServerConfig(t testing.TB, clientCAs *x509.CertPool, hosts ...string) *tls.Config {
+	t.Helper()
+
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{ca.Issue(t, hosts...)},
+	}
+	if clientCAs != nil {
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		cfg.ClientCAs = clientCAs
+	}
+	return cfg
+}
+
+func newKey(t testing.TB) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return key
+}
+
+func serial(t testing.TB) *big.Int {
+	t.Helper()
+
+	n, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		t.Fatalf("generate serial: %v", err)
+	}
+	return n
+}
+
+func writePEM(t testing.TB, path, typ string, der []byte) string {
+	t.Helper()
+
+	data := pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	return path
+}
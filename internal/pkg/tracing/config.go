@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"errors"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	defaultServiceName     = "unknown"
+	defaultSampleRatio     = 1.0
+	defaultShutdownTimeout = 5 * time.Second
+)
+
+type config struct {
+	enabled        bool
+	serviceName    string
+	serviceVersion string
+	environment    string
+	endpoint       string
+	insecure       bool
+	sampleRatio    float64
+	// exporter подменяет OTLP экспортер (тесты, stdout)
+	exporter sdktrace.SpanExporter
+}
+
+func defaultConfig() config {
+	return config{
+		enabled:     true,
+		serviceName: defaultServiceName,
+		sampleRatio: defaultSampleRatio,
+	}
+}
+
+func (c config) valid() error {
+	if !c.enabled {
+		return nil
+	}
+	if c.serviceName == "" {
+		return errors.New("service name is required")
+	}
+	if c.endpoint == "" && c.exporter == nil {
+		return errors.New("endpoint is required")
+	}
+	if c.sampleRatio < 0 || c.sampleRatio > 1 {
+		return errors.New("sample ratio must be in [0, 1]")
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+type Option func(*config)
+
+// WithEnabled выключает трассировку: провайдер становится no-op.
+func WithEnabled(enabled bool) Option {
+	return func(c *config) {
+		c.enabled = enabled
+	}
+}
+
+func WithService(name, version string) Option {
+	return func(c *config) {
+		c.serviceName = name
+		c.serviceVersion = version
+	}
+}
+
+func WithEnvironment(env string) Option {
+	return func(c *config) {
+		c.environment = env
+	}
+}
+
+// WithEndpoint задаёт адрес OTLP/gRPC коллектора, например "otel-collector:4317".
+func WithEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.endpoint = endpoint
+	}
+}
+
+// WithInsecure отключает TLS до коллектора (для sidecar на localhost).
+func WithInsecure(insecure bool) Option {
+	return func(c *config) {
+		c.insecure = insecure
+	}
+}
+
+// WithSampleRatio задаёт долю сэмплируемых корневых трейсов в [0, 1].
+// Решение родителя (traceparent от клиента) соблюдается всегда.
+func WithSampleRatio(ratio float64) Option {
+	return func(c *config) {
+		c.sampleRatio = ratio
+	}
+}
+
+func WithExporter(exporter sdktrace.SpanExporter) Option {
+	return func(c *config) {
+		c.exporter = exporter
+	}
+}
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{"disabled without endpoint", []Option{WithEnabled(false)}, false},
+		{"enabled without endpoint", nil, true},
+		{"ratio above one", []Option{WithEndpoint("localhost:4317"), WithSampleRatio(1.5)}, true},
+		{"empty service name", []Option{WithEndpoint("localhost:4317"), WithService("", "")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(context.Background(), tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if p != nil {
+				_ = p.Shutdown(context.Background())
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareExportsSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	p, err := New(context.Background(),
+		WithService("sso", "test"),
+		WithExporter(exporter),
+		WithSampleRatio(1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var handlerSpan trace.SpanContext
+	h := HTTPMiddleware("http.server")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/profile", nil))
+
+	// Shutdown очищает InMemoryExporter, поэтому только сбрасываем буфер
+	if err := p.sdk.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = p.Shutdown(context.Background()) })
+
+	if !handlerSpan.IsValid() {
+		t.Fatal("handler context has no span")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if got := spans[0].SpanContext.TraceID(); got != handlerSpan.TraceID() {
+		t.Errorf("got trace id %s, want %s", got, handlerSpan.TraceID())
+	}
+}
@@ -0,0 +1,106 @@
+// Package tracing configures OpenTelemetry tracing and transport instrumentation.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Provider владеет TracerProvider и экспортером; Shutdown дописывает буфер спанов.
+type Provider struct {
+	trace.TracerProvider
+	sdk *sdktrace.TracerProvider
+}
+
+// New создаёт провайдер и делает его глобальным (otel.SetTracerProvider),
+// вместе с W3C TraceContext и Baggage пропагаторами.
+func New(ctx context.Context, opts ...Option) (*Provider, error) {
+	cfg := defaultConfig()
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := cfg.valid(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.enabled {
+		p := &Provider{TracerProvider: noop.NewTracerProvider()}
+		otel.SetTracerProvider(p.TracerProvider)
+		return p, nil
+	}
+
+	exporter := cfg.exporter
+	if exporter == nil {
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.endpoint)}
+		if cfg.insecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+
+		// соединение ленивое: недоступный коллектор не мешает старту сервиса
+		var err error
+		exporter, err = otlptracegrpc.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp exporter: %w", err)
+		}
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(cfg.serviceName),
+	}
+	if cfg.serviceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(cfg.serviceVersion))
+	}
+	if cfg.environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironmentName(cfg.environment))
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, attrs...))
+	if err != nil {
+		return nil, fmt.Errorf("create resource: %w", err)
+	}
+
+	sdk := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.sampleRatio))),
+	)
+	otel.SetTracerProvider(sdk)
+
+	return &Provider{TracerProvider: sdk, sdk: sdk}, nil
+}
+
+// Shutdown отправляет оставшиеся спаны и останавливает экспортер.
+// Если ctx без дедлайна, ждём не дольше defaultShutdownTimeout.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p.sdk == nil {
+		return nil
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultShutdownTimeout)
+		defer cancel()
+	}
+
+	if err := p.sdk.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown tracer provider: %w", err)
+	}
+	return nil
+}
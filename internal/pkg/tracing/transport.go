@@ -0,0 +1,23 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
+)
+
+// GRPCServerOption создаёт серверный спан на каждый RPC и извлекает
+// traceparent из входящих метаданных. Использует глобальный провайдер.
+func GRPCServerOption() grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler())
+}
+
+// HTTPMiddleware создаёт серверный спан на каждый HTTP запрос.
+// Служебные пути (метрики, пробы) лучше монтировать мимо него.
+func HTTPMiddleware(operation string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return otelhttp.NewHandler(next, operation)
+	}
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	jwtv1 "github.com/Krokozabra213/schools_backend/internal/pkg/jwt-manager/v1"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	"github.com/Krokozabra213/schools_backend/services/sso/business"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errUnauthenticated = errors.New("missing or invalid access token")
+	errForbidden       = errors.New("admin role required")
+)
+
+// authorizeAdmin проверяет access токен из заголовка Authorization
+// ("Bearer <token>", с учётом отзыва) и роль администратора в базе.
+// Возвращает ctx с claims и user_id для логов.
+func (a *App) authorizeAdmin(ctx context.Context, authorization string) (context.Context, error) {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || token == "" {
+		return ctx, errUnauthenticated
+	}
+
+	claims, err := a.jwt.ParseAccessContext(ctx, token)
+	if err != nil {
+		return ctx, errUnauthenticated
+	}
+
+	ctx = jwtv1.ContextWithAccessClaims(ctx, claims)
+	ctx = logger.WithUserID(ctx, claims.UserID)
+
+	if err := a.business.AuthorizeAdmin(ctx, claims.UserID); err != nil {
+		if errors.Is(err, business.ErrPermissionDenied) {
+			return ctx, errForbidden
+		}
+		return ctx, err
+	}
+	return ctx, nil
+}
+
+// adminHTTP пропускает к next только администраторов.
+func (a *App) adminHTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := a.authorizeAdmin(r.Context(), r.Header.Get("Authorization"))
+		switch {
+		case errors.Is(err, errUnauthenticated):
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		case errors.Is(err, errForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case err != nil:
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		logger.FromContext(ctx, a.log).Info("admin request", "path", r.URL.Path)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// adminUnaryInterceptor требует администратора для методов служебных
+// сервисов (управление уровнями логов), остальные пропускает как есть.
+func (a *App) adminUnaryInterceptor() grpc.UnaryServerInterceptor {
+	prefix := "/" + logger.LevelServiceName + "/"
+
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+
+		var authorization string
+		if v := metadata.ValueFromIncomingContext(ctx, "authorization"); len(v) > 0 {
+			authorization = v[0]
+		}
+
+		ctx, err := a.authorizeAdmin(ctx, authorization)
+		switch {
+		case errors.Is(err, errUnauthenticated):
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case errors.Is(err, errForbidden):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case err != nil:
+			return nil, status.Error(codes.Internal, "failed authorize admin")
+		}
+
+		logger.FromContext(ctx, a.log).Info("admin request")
+		return handler(ctx, req)
+	}
+}
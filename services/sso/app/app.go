@@ -0,0 +1,584 @@
+// Package app is the SSO composition root: it builds every dependency from
+// ssoconfig.Config, runs the servers and shuts them down gracefully.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	gorediscli "github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/health"
+	jwtv1 "github.com/Krokozabra213/schools_backend/internal/pkg/jwt-manager/v1"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/metrics"
+	pgxclient "github.com/Krokozabra213/schools_backend/internal/pkg/pgx-client"
+	ratelimiterv1 "github.com/Krokozabra213/schools_backend/internal/pkg/rate-limiter/v1"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/resilience"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/tracing"
+	"github.com/Krokozabra213/schools_backend/services/sso/business"
+	ssoconfig "github.com/Krokozabra213/schools_backend/services/sso/config"
+	"github.com/Krokozabra213/schools_backend/services/sso/migrator"
+	"github.com/Krokozabra213/schools_backend/services/sso/repository/postgres"
+	redisrepo "github.com/Krokozabra213/schools_backend/services/sso/repository/redis"
+	"google.golang.org/grpc"
+)
+
+const (
+	Name    = "sso"
+	Version = "v0.1.0"
+)
+
+// Паузы между перезапусками слушателя инвалидаций.
+const (
+	listenInitialBackoff = time.Second
+	listenMaxBackoff     = 30 * time.Second
+)
+
+// closer освобождает ресурс при остановке или неудачном старте.
+type closer struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+type App struct {
+	cfg      *ssoconfig.Config
+	reloader *ssoconfig.Reloader
+	logger   *logger.Logger
+	log      *slog.Logger
+
+	tracing  *tracing.Provider
+	metrics  *metrics.Metrics
+	db       *pgxclient.Client
+	redis    *gorediscli.Client
+	cache    *redisrepo.CachedRepository
+	jwt      *jwtv1.Manager
+	business *business.Business
+	health   *health.Health
+
+	rateLimit *ratelimiterv1.Config
+	limiter   ratelimiterv1.Limiter
+
+	grpcServer *grpc.Server
+	httpServer *http.Server
+
+	// closers выполняются в обратном порядке: последним создан — первым закрыт
+	closers []closer
+}
+
+// New строит все зависимости. Если что-то не удалось, уже созданные
+// ресурсы закрываются, и вызывающему не нужно ничего освобождать.
+func New(ctx context.Context, cfg *ssoconfig.Config, configPath string, log *logger.Logger) (*App, error) {
+	a := &App{
+		cfg:      cfg,
+		reloader: ssoconfig.NewReloader(configPath, cfg, log.Logger),
+		logger:   log,
+		log:      log.Logger,
+	}
+
+	err := a.init(ctx,
+		a.initObservability,
+		a.initStorage,
+		a.initBusiness,
+		func(context.Context) error { return a.initServers() },
+	)
+	if err != nil {
+		return nil, err
+	}
+	a.initReload()
+
+	return a, nil
+}
+
+// init выполняет шаги по порядку. Если шаг не удался, closers уже
+// выполненных шагов (и самого упавшего) закрываются.
+func (a *App) init(ctx context.Context, steps ...func(ctx context.Context) error) error {
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			a.close(context.Background())
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *App) addCloser(name string, fn func(ctx context.Context) error) {
+	a.closers = append(a.closers, closer{name: name, fn: fn})
+}
+
+func (a *App) initObservability(ctx context.Context) error {
+	tp, err := tracing.New(ctx,
+		tracing.WithEnabled(a.cfg.Tracing.Enabled),
+		tracing.WithService(Name, Version),
+		tracing.WithEnvironment(a.cfg.App.Environment),
+		tracing.WithEndpoint(a.cfg.Tracing.Endpoint),
+		tracing.WithInsecure(a.cfg.Tracing.Insecure),
+		tracing.WithSampleRatio(a.cfg.Tracing.SampleRatio),
+	)
+	if err != nil {
+		return fmt.Errorf("init tracing: %w", err)
+	}
+	a.tracing = tp
+	a.addCloser("tracing", tp.Shutdown)
+
+	m, err := metrics.New(metrics.WithNamespace(Name))
+	if err != nil {
+		return fmt.Errorf("init metrics: %w", err)
+	}
+	a.metrics = m
+
+	if err := m.RegisterLogStats(a.logger); err != nil {
+		return fmt.Errorf("register log metrics: %w", err)
+	}
+
+	return nil
+}
+
+// NewPostgres подключается к Postgres по настройкам из конфигурации.
+// Используется и приложением, и служебными командами (migrate, ssoctl).
+func NewPostgres(ctx context.Context, cfg *ssoconfig.Config, extra ...pgxclient.Option) (*pgxclient.Client, error) {
+	port, err := strconv.Atoi(cfg.PG.Port)
+	if err != nil {
+		return nil, fmt.Errorf("parse postgres port %q: %w", cfg.PG.Port, err)
+	}
+
+	opts := []pgxclient.Option{
+		pgxclient.WithHost(cfg.PG.Host),
+		pgxclient.WithPort(port),
+		pgxclient.WithUser(cfg.PG.User),
+		pgxclient.WithPassword(cfg.PG.Password.Reveal()),
+		pgxclient.WithDatabase(cfg.PG.DBName),
+		pgxclient.WithSSL(cfg.PG.SSLMode),
+		pgxclient.WithSSLRootCert(cfg.PG.SSLRootCert),
+		pgxclient.WithSSLCert(cfg.PG.SSLCert, cfg.PG.SSLKey),
+		pgxclient.WithSSLServerName(cfg.PG.SSLServerName),
+		pgxclient.WithConnectionTimeout(cfg.PG.ConnectTimeout),
+		pgxclient.WithMaxConns(cfg.PG.MaxConns),
+		pgxclient.WithMinConns(cfg.PG.MinConns),
+		pgxclient.WithMaxConnLifetime(cfg.PG.MaxConnLifetime),
+		pgxclient.WithMaxConnIdletime(cfg.PG.MaxConnIdleTime),
+		pgxclient.WithReplicas(cfg.PG.Replicas...),
+		pgxclient.WithReplicaMaxLag(cfg.PG.ReplicaMaxLag),
+		pgxclient.WithReplicaCheckInterval(cfg.PG.ReplicaCheckInterval),
+		pgxclient.WithLogger(logger.Component(slog.Default(), "pgxclient")),
+	}
+	if cfg.PG.QueryStats {
+		opts = append(opts, pgxclient.WithQueryStats(cfg.PG.SlowQueryThreshold))
+	}
+	if cfg.Resilience.Enabled {
+		opts = append(opts, pgxclient.WithResilience(resilienceOptions(cfg, "postgres")...))
+	}
+
+	db, err := pgxclient.New(ctx, append(opts, extra...)...)
+	if err != nil {
+		return nil, fmt.Errorf("connect postgres: %w", err)
+	}
+	return db, nil
+}
+
+// NewRedis подключается к Redis по настройкам из конфигурации.
+func NewRedis(ctx context.Context, cfg *ssoconfig.Config, extra ...gorediscli.Option) (*gorediscli.Client, error) {
+	opts := []gorediscli.Option{
+		gorediscli.WithAddr(cfg.Redis.Addr),
+		gorediscli.WithPassword(cfg.Redis.Password.Reveal()),
+		gorediscli.WithDB(cfg.Redis.Database),
+		gorediscli.WithPoolSize(cfg.Redis.PoolSize),
+		gorediscli.WithMinIdleConns(cfg.Redis.MinIdleConns),
+		gorediscli.WithDialTimeout(cfg.Redis.DialTimeout),
+		gorediscli.WithReadTimeout(cfg.Redis.ReadTimeout),
+		gorediscli.WithWriteTimeout(cfg.Redis.WriteTimeout),
+		gorediscli.WithMaxConnLifetime(cfg.Redis.ConnMaxLifetime),
+		gorediscli.WithMaxConnIdleTime(cfg.Redis.ConnMaxIdleTime),
+		gorediscli.WithTLS(cfg.Redis.TLSMode),
+		gorediscli.WithTLSRootCert(cfg.Redis.TLSRootCert),
+		gorediscli.WithTLSCert(cfg.Redis.TLSCert, cfg.Redis.TLSKey),
+		gorediscli.WithTLSServerName(cfg.Redis.TLSServerName),
+	}
+	switch {
+	case cfg.Redis.SentinelMaster != "":
+		opts = append(opts,
+			gorediscli.WithSentinel(cfg.Redis.SentinelMaster, cfg.Redis.SentinelAddrs...),
+			gorediscli.WithSentinelPassword(cfg.Redis.SentinelPassword.Reveal()),
+		)
+	case len(cfg.Redis.ClusterAddrs) > 0:
+		opts = append(opts, gorediscli.WithCluster(cfg.Redis.ClusterAddrs...))
+	}
+	if cfg.Resilience.Enabled {
+		opts = append(opts, gorediscli.WithResilience(resilienceOptions(cfg, "redis")...))
+	}
+
+	rdb, err := gorediscli.New(ctx, append(opts, extra...)...)
+	if err != nil {
+		return nil, fmt.Errorf("connect redis: %w", err)
+	}
+	return rdb, nil
+}
+
+// redisEndpoint описывает подключение к Redis для лога в любом режиме.
+func redisEndpoint(cfg *ssoconfig.RedisConfig) slog.Attr {
+	switch {
+	case cfg.SentinelMaster != "":
+		return slog.Group("sentinel",
+			slog.String("master", cfg.SentinelMaster),
+			slog.Any("addrs", cfg.SentinelAddrs),
+		)
+	case len(cfg.ClusterAddrs) > 0:
+		return slog.Any("cluster", cfg.ClusterAddrs)
+	default:
+		return slog.String("address", cfg.Addr)
+	}
+}
+
+// resilienceOptions — повторы и автомат из конфигурации; смена состояния
+// автомата пишется в лог компонента resilience.
+func resilienceOptions(cfg *ssoconfig.Config, storage string) []resilience.Option {
+	log := logger.Component(slog.Default(), "resilience").With(slog.String("storage", storage))
+
+	return []resilience.Option{
+		resilience.WithMaxAttempts(cfg.Resilience.MaxAttempts),
+		resilience.WithBackoff(cfg.Resilience.InitialBackoff, cfg.Resilience.MaxBackoff),
+		resilience.WithBreaker(cfg.Resilience.BreakerThreshold, cfg.Resilience.BreakerCooldown),
+		resilience.WithOnStateChange(func(name string, from, to resilience.State) {
+			level := slog.LevelWarn
+			if to == resilience.StateClosed {
+				level = slog.LevelInfo
+			}
+			log.Log(context.Background(), level, "circuit breaker state changed",
+				slog.String("pool", name),
+				slog.String("from", from.String()),
+				slog.String("to", to.String()),
+			)
+		}),
+	}
+}
+
+// NewUserCache создаёт кеш профилей и отзывов поверх Redis. Изменения,
+// сделанные через него, рассылаются инвалидацией всем репликам.
+func NewUserCache(cfg *ssoconfig.Config, rdb *gorediscli.Client) (*redisrepo.CachedRepository, error) {
+	cache, err := redisrepo.NewCachedRepository(redisrepo.NewRepository(rdb), rdb, redisrepo.LocalCacheConfig{
+		Capacity:      cfg.Cache.LocalCapacity,
+		ProfileTTL:    cfg.Cache.LocalProfileTTL,
+		RevocationTTL: cfg.Cache.LocalRevocationTTL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("init local cache: %w", err)
+	}
+	return cache, nil
+}
+
+func (a *App) initStorage(ctx context.Context) error {
+	var (
+		pgOpts    []pgxclient.Option
+		redisOpts []gorediscli.Option
+	)
+	if a.cfg.Tracing.Enabled {
+		pgOpts = append(pgOpts, pgxclient.WithTracerProvider(a.tracing))
+		redisOpts = append(redisOpts, gorediscli.WithTracerProvider(a.tracing))
+	}
+
+	db, err := NewPostgres(ctx, a.cfg, pgOpts...)
+	if err != nil {
+		return err
+	}
+	a.db = db
+	a.addCloser("postgres", func(context.Context) error {
+		db.Close()
+		return nil
+	})
+	a.log.Info("connected to postgres",
+		slog.String("address", a.cfg.PG.Host+":"+a.cfg.PG.Port),
+		slog.Int("replicas", len(a.cfg.PG.Replicas)),
+	)
+
+	rdb, err := NewRedis(ctx, a.cfg, redisOpts...)
+	if err != nil {
+		return err
+	}
+	a.redis = rdb
+	a.addCloser("redis", func(context.Context) error {
+		return rdb.Close()
+	})
+	a.log.Info("connected to redis", redisEndpoint(&a.cfg.Redis))
+
+	if err := a.metrics.RegisterPgxPool("primary", db.Pool()); err != nil {
+		return fmt.Errorf("register postgres metrics: %w", err)
+	}
+	for _, r := range db.Replicas() {
+		if err := a.metrics.RegisterPgxPool("replica "+r.Name, r.Pool); err != nil {
+			return fmt.Errorf("register postgres replica metrics: %w", err)
+		}
+	}
+	if a.cfg.PG.QueryStats {
+		if err := a.metrics.RegisterPgxQueries(db); err != nil {
+			return fmt.Errorf("register postgres query metrics: %w", err)
+		}
+	}
+	if err := a.metrics.RegisterRedisPool("main", rdb); err != nil {
+		return fmt.Errorf("register redis metrics: %w", err)
+	}
+
+	return nil
+}
+
+func (a *App) initBusiness(ctx context.Context) error {
+	cache, err := NewUserCache(a.cfg, a.redis)
+	if err != nil {
+		return err
+	}
+	a.cache = cache
+
+	privateKey, err := jwtv1.ParsePrivateKey(a.cfg.JWT.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("parse jwt private key: %w", err)
+	}
+
+	jwt, err := jwtv1.New(privateKey, &privateKey.PublicKey,
+		jwtv1.WithAccessTTL(a.cfg.JWT.AccessTokenTTL),
+		jwtv1.WithRefreshTTL(a.cfg.JWT.RefreshTokenTTL),
+		jwtv1.WithRevocationChecker(cache),
+	)
+	if err != nil {
+		return fmt.Errorf("init jwt manager: %w", err)
+	}
+	a.jwt = jwt
+
+	a.business = business.New(a.cfg, logger.Component(a.log, "business"),
+		pgxclient.NewTxManager(a.db),
+		postgres.NewRepository(a.db),
+		cache,
+		cache,
+		a.metrics,
+	)
+
+	err = a.metrics.RegisterCacheStats("profile", func() metrics.CacheStats {
+		return metrics.CacheStats(a.business.ProfileCacheStats())
+	})
+	if err != nil {
+		return fmt.Errorf("register profile cache metrics: %w", err)
+	}
+
+	mig, err := migrator.New(a.db.Pool(), logger.Component(a.log, "migrator"))
+	if err != nil {
+		return fmt.Errorf("init migrator: %w", err)
+	}
+	a.addCloser("migrator", func(context.Context) error {
+		return mig.Close()
+	})
+
+	if a.cfg.PG.AutoMigrate {
+		// под advisory lock: реплики применяют миграции по очереди
+		results, err := mig.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("auto migrate: %w", err)
+		}
+		a.log.Info("migrations applied", slog.Int("count", len(results)))
+	}
+
+	h, err := NewHealth(a.db, a.redis, jwt, mig,
+		health.WithTimeout(a.cfg.Health.CheckTimeout),
+		health.WithCacheTTL(a.cfg.Health.CacheTTL),
+	)
+	if err != nil {
+		return fmt.Errorf("init health: %w", err)
+	}
+	a.health = h
+
+	rateLimit, err := ratelimiterv1.NewConfigFromSpec(a.cfg.RateLimit)
+	if err != nil {
+		return fmt.Errorf("init rate limit config: %w", err)
+	}
+	rateLimit.SetObserver(a.metrics)
+	a.rateLimit = rateLimit
+
+	// при недоступности Redis лимиты продолжают действовать локально
+	memory := ratelimiterv1.NewMemoryLimiter()
+	a.addCloser("memory rate limiter", func(context.Context) error {
+		memory.Close()
+		return nil
+	})
+	a.limiter = ratelimiterv1.NewFallbackLimiter(
+		ratelimiterv1.NewRedisLimiter(a.redis),
+		memory,
+		logger.Component(a.log, "ratelimiter"),
+	)
+
+	return nil
+}
+
+// initReload подписывает на перечитывание файла конфигурации то,
+// что можно применить без перезапуска.
+func (a *App) initReload() {
+	// сравниваем с прошлым файлом, а не с текущим уровнем: иначе любое
+	// перечитывание отменит временный уровень, выставленный через admin API
+	level := a.cfg.App.Level()
+	a.reloader.Subscribe("log_level", func(cfg *ssoconfig.Config) error {
+		if next := cfg.App.Level(); next != level {
+			level = next
+			a.logger.SetLevel(next)
+		}
+		return nil
+	})
+	a.reloader.Subscribe("rate_limit", func(cfg *ssoconfig.Config) error {
+		return a.rateLimit.Apply(cfg.RateLimit)
+	})
+}
+
+// listenWithRetry перезапускает listen до отмены ctx: без подписки
+// локальные кеши инстансов расходятся до их TTL. Пауза удваивается до
+// maxWait и сбрасывается, если подписка продержалась дольше maxWait.
+func listenWithRetry(ctx context.Context, log *slog.Logger, listen func(context.Context) error, initial, maxWait time.Duration) {
+	wait := initial
+	for {
+		started := time.Now()
+		err := listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = redisrepo.ErrSubscriptionClosed
+		}
+		if time.Since(started) > maxWait {
+			wait = initial
+		}
+
+		log.Error("cache invalidation listener stopped, restarting",
+			slog.String("error", err.Error()),
+			slog.Duration("retry_in", wait),
+		)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		wait = min(wait*2, maxWait)
+	}
+}
+
+// Run запускает серверы и фоновые задачи и блокируется до отмены ctx
+// (SIGINT/SIGTERM) или падения одного из серверов, после чего
+// останавливает всё и освобождает ресурсы.
+func (a *App) Run(ctx context.Context) error {
+	grpcLis, httpLis, err := a.listen()
+	if err != nil {
+		a.close(context.Background())
+		return err
+	}
+
+	bgCtx, cancelBackground := context.WithCancel(context.WithoutCancel(ctx))
+	var bg sync.WaitGroup
+
+	bg.Add(2)
+	go func() {
+		defer bg.Done()
+		listenWithRetry(bgCtx, a.log, a.cache.Listen, listenInitialBackoff, listenMaxBackoff)
+	}()
+	go func() {
+		defer bg.Done()
+		a.reloader.Run(bgCtx)
+	}()
+
+	serveErr := make(chan error, 2)
+	go func() {
+		a.log.Info("grpc server started", slog.String("address", grpcLis.Addr().String()))
+		serveErr <- a.grpcServer.Serve(grpcLis)
+	}()
+	go func() {
+		a.log.Info("http server started", slog.String("address", httpLis.Addr().String()))
+		serveErr <- a.httpServer.Serve(httpLis)
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		a.log.Info("shutdown signal received")
+	case err := <-serveErr:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			runErr = fmt.Errorf("server failed: %w", err)
+			a.log.Error("server failed, shutting down", slog.String("error", err.Error()))
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.cfg.App.ShutdownTimeout)
+	defer cancel()
+
+	a.shutdownServers(shutdownCtx)
+
+	cancelBackground()
+	bg.Wait()
+
+	a.close(shutdownCtx)
+	a.log.Info("application stopped")
+
+	return runErr
+}
+
+// shutdownServers переводит сервис в not ready и выжидает ShutdownDelay,
+// затем перестаёт принимать новые запросы и ждёт текущие до дедлайна ctx,
+// после чего обрывает оставшиеся.
+func (a *App) shutdownServers(ctx context.Context) {
+	// балансировщик должен увидеть not ready раньше, чем закроются порты
+	a.health.SetShuttingDown()
+
+	// пока реплику не исключили, запросы ещё приходят: обслуживаем их
+	if delay := a.cfg.App.ShutdownDelay; delay > 0 {
+		a.log.Info("draining before shutdown", slog.Duration("delay", delay))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		stopped := make(chan struct{})
+		go func() {
+			a.grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			a.log.Warn("grpc graceful stop timed out, forcing")
+			a.grpcServer.Stop()
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		if err := a.httpServer.Shutdown(ctx); err != nil {
+			a.log.Warn("http graceful shutdown failed, forcing", slog.String("error", err.Error()))
+			_ = a.httpServer.Close()
+		}
+	}()
+
+	wg.Wait()
+}
+
+// close освобождает ресурсы в порядке, обратном созданию.
+func (a *App) close(ctx context.Context) {
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		c := a.closers[i]
+
+		start := time.Now()
+		if err := c.fn(ctx); err != nil {
+			a.log.Error("failed close resource", slog.String("resource", c.name), slog.String("error", err.Error()))
+			continue
+		}
+		a.log.Debug("resource closed", slog.String("resource", c.name), slog.Duration("took", time.Since(start)))
+	}
+	a.closers = nil
+}
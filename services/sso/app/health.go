@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gorediscli "github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/health"
+	jwtv1 "github.com/Krokozabra213/schools_backend/internal/pkg/jwt-manager/v1"
+	pgxclient "github.com/Krokozabra213/schools_backend/internal/pkg/pgx-client"
+	"github.com/Krokozabra213/schools_backend/services/sso/migrator"
+)
+
+var errPendingMigrations = errors.New("database has pending migrations")
+
+// NewHealth регистрирует проверки SSO: liveness не трогает внешние системы,
+// readiness проверяет Postgres, Redis, ключ подписи и версию схемы.
+func NewHealth(db *pgxclient.Client, rdb *gorediscli.Client, jwt *jwtv1.Manager, mig *migrator.Migrator, opts ...health.Option) (*health.Health, error) {
+	h, err := health.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	h.AddLivenessCheck("process", func(ctx context.Context) error { return nil })
+
+	h.AddReadinessCheck("postgres", health.PingCheck(db))
+	h.AddReadinessCheck("redis", func(ctx context.Context) error {
+		return rdb.Ping(ctx).Err()
+	})
+	h.AddReadinessCheck("signing_key", signingKeyCheck(jwt))
+	h.AddReadinessCheck("migrations", func(ctx context.Context) error {
+		pending, err := mig.HasPending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending {
+			return errPendingMigrations
+		}
+		return nil
+	})
+
+	return h, nil
+}
+
+// signingKeyCheck выпускает и сразу разбирает служебный токен: так видно,
+// что приватный и публичный ключи загружены и составляют пару.
+func signingKeyCheck(jwt *jwtv1.Manager) health.CheckFunc {
+	return func(ctx context.Context) error {
+		// отрицательный ID не совпадёт ни с одним пользователем
+		token, err := jwt.GenerateAccess(jwtv1.TokenData{
+			UserID:   -1,
+			Username: "healthcheck",
+			Email:    "healthcheck@localhost",
+		})
+		if err != nil {
+			return fmt.Errorf("sign probe token: %w", err)
+		}
+		if _, err := jwt.ParseAccess(token); err != nil {
+			return fmt.Errorf("verify probe token: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	ratelimiterv1 "github.com/Krokozabra213/schools_backend/internal/pkg/rate-limiter/v1"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/tracing"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// unlimitedGRPC — префиксы методов мимо rate limiter. Пробы kubelet приходят
+// с одного адреса и делили бы лимит с трафиком: под нагрузкой readiness
+// получала бы ResourceExhausted и под перезапускался бы.
+var unlimitedGRPC = []string{"/" + healthpb.Health_ServiceDesc.ServiceName + "/"}
+
+func (a *App) initServers() error {
+	a.grpcServer = grpc.NewServer(
+		tracing.GRPCServerOption(),
+		grpc.ConnectionTimeout(a.cfg.GRPC.ReadTimeout),
+		grpc.MaxHeaderListSize(uint32(a.cfg.GRPC.MaxHeaderMegabytes)<<20),
+		// request id первым, чтобы он был в логах всех интерсепторов;
+		// метрики до rate limiter, чтобы учитывать и его отказы
+		grpc.ChainUnaryInterceptor(
+			logger.UnaryServerInterceptor(),
+			a.metrics.UnaryServerInterceptor(),
+			skipUnary(unlimitedGRPC, ratelimiterv1.UnaryInterceptor(a.limiter, *a.rateLimit, a.log)),
+			a.adminUnaryInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			logger.StreamServerInterceptor(),
+			a.metrics.StreamServerInterceptor(),
+			skipStream(unlimitedGRPC, ratelimiterv1.StreamInterceptor(a.limiter, *a.rateLimit, a.log)),
+		),
+	)
+	healthpb.RegisterHealthServer(a.grpcServer, a.health.GRPCServer())
+	a.logger.RegisterLevelService(a.grpcServer)
+
+	// служебный HTTP: пробы и метрики не лимитируются и не трассируются
+	mux := http.NewServeMux()
+	mux.Handle("GET /healthz", a.health.LivenessHandler())
+	mux.Handle("GET /readyz", a.health.ReadinessHandler())
+	mux.Handle("GET /metrics", a.metrics.Handler())
+	// уровни логов меняются на лету, только администратором
+	mux.Handle("/admin/log-level", a.adminHTTP(a.logger.LevelHandler()))
+
+	a.httpServer = &http.Server{
+		Addr:           net.JoinHostPort(a.cfg.HTTP.Host, a.cfg.HTTP.Port),
+		Handler:        logger.HTTPMiddleware(mux),
+		ReadTimeout:    a.cfg.HTTP.ReadTimeout,
+		WriteTimeout:   a.cfg.HTTP.WriteTimeout,
+		MaxHeaderBytes: a.cfg.HTTP.MaxHeaderMegabytes << 20,
+	}
+
+	return nil
+}
+
+// listen занимает оба порта до запуска серверов, чтобы занятый порт
+// был ошибкой старта, а не падением уже работающего процесса.
+func (a *App) listen() (grpcLis, httpLis net.Listener, err error) {
+	grpcLis, err = net.Listen("tcp", net.JoinHostPort(a.cfg.GRPC.Host, a.cfg.GRPC.Port))
+	if err != nil {
+		return nil, nil, fmt.Errorf("listen grpc: %w", err)
+	}
+
+	httpLis, err = net.Listen("tcp", a.httpServer.Addr)
+	if err != nil {
+		_ = grpcLis.Close()
+		return nil, nil, fmt.Errorf("listen http: %w", err)
+	}
+
+	return grpcLis, httpLis, nil
+}
+
+func skipped(method string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipUnary пропускает interceptor для методов с префиксами из prefixes.
+func skipUnary(prefixes []string, interceptor grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if skipped(info.FullMethod, prefixes) {
+			return handler(ctx, req)
+		}
+		return interceptor(ctx, req, info, handler)
+	}
+}
+
+// skipStream — то же для потоковых методов (Health/Watch).
+func skipStream(prefixes []string, interceptor grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if skipped(info.FullMethod, prefixes) {
+			return handler(srv, ss)
+		}
+		return interceptor(srv, ss, info, handler)
+	}
+}
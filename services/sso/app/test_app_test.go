@@ -0,0 +1,213 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/health"
+	ssoconfig "github.com/Krokozabra213/schools_backend/services/sso/config"
+	"google.golang.org/grpc"
+)
+
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+
+	return &App{
+		cfg: &ssoconfig.Config{},
+		log: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+}
+
+// recordCloser добавляет closer, который записывает своё имя в closed.
+func recordCloser(a *App, closed *[]string, name string, err error) {
+	a.addCloser(name, func(context.Context) error {
+		*closed = append(*closed, name)
+		return err
+	})
+}
+
+func TestCloseOrder(t *testing.T) {
+	a := newTestApp(t)
+
+	var closed []string
+	recordCloser(a, &closed, "tracing", nil)
+	recordCloser(a, &closed, "postgres", errors.New("already closed"))
+	recordCloser(a, &closed, "redis", nil)
+
+	a.close(context.Background())
+
+	// ошибка одного closer не мешает закрыть остальные
+	if want := []string{"redis", "postgres", "tracing"}; !slices.Equal(closed, want) {
+		t.Errorf("got close order %v, want %v", closed, want)
+	}
+
+	// повторный close ничего не закрывает дважды
+	a.close(context.Background())
+	if len(closed) != 3 {
+		t.Errorf("got %d closes after second close, want 3", len(closed))
+	}
+}
+
+func TestInitCleansUpOnFailure(t *testing.T) {
+	a := newTestApp(t)
+	errStep := errors.New("connect redis")
+
+	var closed, ran []string
+	step := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			ran = append(ran, name)
+			recordCloser(a, &closed, name, nil)
+			return err
+		}
+	}
+
+	err := a.init(context.Background(),
+		step("observability", nil),
+		step("storage", errStep),
+		step("business", nil),
+	)
+	if !errors.Is(err, errStep) {
+		t.Fatalf("got error %v, want %v", err, errStep)
+	}
+
+	if want := []string{"observability", "storage"}; !slices.Equal(ran, want) {
+		t.Errorf("got steps %v, want %v", ran, want)
+	}
+	// ресурсы упавшего шага тоже освобождаются
+	if want := []string{"storage", "observability"}; !slices.Equal(closed, want) {
+		t.Errorf("got close order %v, want %v", closed, want)
+	}
+	if len(a.closers) != 0 {
+		t.Errorf("got %d closers left, want 0", len(a.closers))
+	}
+}
+
+// На время ShutdownDelay сервис отвечает not ready, но запросы ещё
+// принимает; после задержки порт закрывается.
+func TestShutdownDelay(t *testing.T) {
+	const delay = 300 * time.Millisecond
+
+	a := newTestApp(t)
+	a.cfg.App.ShutdownDelay = delay
+
+	h, err := health.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.health = h
+	a.grpcServer = grpc.NewServer()
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /readyz", h.ReadinessHandler())
+	a.httpServer = &http.Server{Handler: mux}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go a.httpServer.Serve(lis)
+	url := "http://" + lis.Addr().String() + "/readyz"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan struct{})
+	go func() {
+		a.shutdownServers(ctx)
+		close(done)
+	}()
+
+	// транспорт без keep-alive: каждое обращение — новое соединение
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	var status int
+	for time.Since(start) < delay/2 {
+		resp, err := client.Get(url)
+		if err != nil {
+			t.Fatalf("request during drain delay failed: %v", err)
+		}
+		resp.Body.Close()
+		status = resp.StatusCode
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("got readiness status %d during drain, want %d", status, http.StatusServiceUnavailable)
+	}
+
+	<-done
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("servers stopped after %v, want at least %v", elapsed, delay)
+	}
+	if _, err := client.Get(url); err == nil {
+		t.Error("request after shutdown succeeded, want connection error")
+	}
+}
+
+func TestListenWithRetry(t *testing.T) {
+	a := newTestApp(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// подписка обрывается дважды, третья держится до отмены
+	var calls int
+	listen := func(ctx context.Context) error {
+		calls++
+		switch calls {
+		case 1:
+			return errors.New("redis down")
+		case 2:
+			return nil
+		}
+		cancel()
+		<-ctx.Done()
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		listenWithRetry(ctx, a.log, listen, time.Millisecond, 5*time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("listener loop did not stop after cancel")
+	}
+	if calls != 3 {
+		t.Errorf("got %d listen calls, want 3", calls)
+	}
+}
+
+func TestHealthSkipsRateLimit(t *testing.T) {
+	errLimited := errors.New("rate limited")
+	deny := func(context.Context, any, *grpc.UnaryServerInfo, grpc.UnaryHandler) (any, error) {
+		return nil, errLimited
+	}
+	interceptor := skipUnary(unlimitedGRPC, deny)
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	tests := []struct {
+		method  string
+		wantErr error
+	}{
+		{"/grpc.health.v1.Health/Check", nil},
+		{"/grpc.health.v1.HealthCheck/Other", errLimited},
+		{"/sso.AuthService/Login", errLimited},
+	}
+
+	for _, tt := range tests {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got error %v, want %v", tt.method, err, tt.wantErr)
+		}
+	}
+}
@@ -3,9 +3,12 @@ package business
 import (
 	"context"
 	"log/slog"
+	"time"
 
+	pgxclient "github.com/Krokozabra213/schools_backend/internal/pkg/pgx-client"
 	ssoconfig "github.com/Krokozabra213/schools_backend/services/sso/config"
 	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+	"golang.org/x/sync/singleflight"
 )
 
 //go:generate mockgen  -source=constructor.go -destination=mocks/mocks.go
@@ -19,20 +22,64 @@ type UserProvider interface {
 	SoftDeleteUser(ctx context.Context, id int64) error
 	HardDeleteUser(ctx context.Context, id int64) error
 	CountUsers(ctx context.Context) (int64, error)
+	ListUsers(ctx context.Context, limit, offset int32) ([]domain.User, error)
+	LockUser(ctx context.Context, id int64) error
+	UnlockUser(ctx context.Context, id int64) error
+	SetUserRole(ctx context.Context, id int64, role domain.Role) error
 	ExistsUserByUsername(ctx context.Context, username string) (bool, error)
 	ExistsUserByEmail(ctx context.Context, email string) (bool, error)
 }
 
+// ProfileCache кеширует публичные профили пользователей.
+type ProfileCache interface {
+	CacheUserProfile(ctx context.Context, profile *domain.UserCacheProfile, ttl time.Duration) error
+	GetUserProfile(ctx context.Context, userID int64) (*domain.UserCacheProfile, error)
+	DeleteUserProfile(ctx context.Context, userID int64) error
+}
+
+// TokenWatermark хранит момент, до которого все токены пользователя считаются отозванными.
+type TokenWatermark interface {
+	BumpTokensValidAfter(ctx context.Context, userID int64, at time.Time, ttl time.Duration) error
+}
+
+// TxManager выполняет fn в транзакции, передавая её через контекст.
+// Вызовы UserProvider с этим контекстом попадают в ту же транзакцию.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	WithinTxLevel(ctx context.Context, level pgxclient.IsolationLevel, fn func(ctx context.Context) error) error
+}
+
 type Business struct {
-	cfg  *ssoconfig.Config
-    log *slog.Logger
-	user UserProvider
+	cfg     *ssoconfig.Config
+	log     *slog.Logger
+	tx      TxManager
+	user    UserProvider
+	cache   ProfileCache
+	tokens  TokenWatermark
+	metrics Metrics
+
+	profileGroup singleflight.Group
+	profileStats cacheStats
 }
 
-func New(cfg *ssoconfig.Config, user UserProvider) *Business {
+// New создаёт бизнес-слой. metrics может быть nil — тогда счётчики не ведутся,
+// log может быть nil — тогда используется slog.Default().
+func New(cfg *ssoconfig.Config, log *slog.Logger, tx TxManager, user UserProvider, cache ProfileCache, tokens TokenWatermark, metrics Metrics) *Business {
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+	if log == nil {
+		log = slog.Default()
+	}
+
 	return &Business{
-		cfg:  cfg,
-		user: user,
+		cfg:     cfg,
+		log:     log,
+		tx:      tx,
+		user:    user,
+		cache:   cache,
+		tokens:  tokens,
+		metrics: metrics,
 	}
 }
 
@@ -3,9 +3,17 @@ package business
 import "errors"
 
 var (
-	ErrUserNotFound     = errors.New("user not found")
-	ErrInternal         = errors.New("internal service error")
-	ErrPermissionDenied = errors.New("permission denied")
-	ErrUserExists       = errors.New("user already exists")
-	ErrEmailExists      = errors.New("user email already exists")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInternal           = errors.New("internal service error")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrUserExists         = errors.New("user already exists")
+	ErrEmailExists        = errors.New("user email already exists")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrInvalidPagination  = errors.New("invalid pagination")
+
+	// ErrTokensNotRevoked — изменение пользователя сохранено, но выданные
+	// токены не отозваны: повторять операцию не нужно, достаточно повторить
+	// RevokeUserTokens.
+	ErrTokensNotRevoked = errors.New("changes saved, but user tokens were not revoked")
 )
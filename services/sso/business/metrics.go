@@ -0,0 +1,29 @@
+package business
+
+// Metrics — бизнес-счётчики (реализация: internal/pkg/metrics).
+// LoginSucceeded и LoginFailed вызовет Login, когда он появится в бизнес-слое;
+// серии уже выставлены нулями, чтобы по ним можно было строить дашборды.
+type Metrics interface {
+	UserRegistered()
+	RegistrationFailed(reason string)
+	LoginSucceeded()
+	LoginFailed(reason string)
+}
+
+// Причины отказа для метрик: фиксированный набор, чтобы не раздувать кардинальность.
+const (
+	reasonEmailExists = "email_exists"
+	reasonUserExists  = "user_exists"
+	reasonInternal    = "internal"
+
+	// причины отказа во входе; тот же набор инициализирует internal/pkg/metrics
+	reasonInvalidCredentials = "invalid_credentials"
+	reasonUserLocked         = "user_locked"
+)
+
+type noopMetrics struct{}
+
+func (noopMetrics) UserRegistered()           {}
+func (noopMetrics) RegistrationFailed(string) {}
+func (noopMetrics) LoginSucceeded()           {}
+func (noopMetrics) LoginFailed(string)        {}
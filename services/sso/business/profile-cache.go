@@ -0,0 +1,53 @@
+package business
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// CacheStats счётчики обращений к кешу профилей.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Errors uint64
+}
+
+type cacheStats struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	errors atomic.Uint64
+}
+
+func (s *cacheStats) snapshot() CacheStats {
+	return CacheStats{
+		Hits:   s.hits.Load(),
+		Misses: s.misses.Load(),
+		Errors: s.errors.Load(),
+	}
+}
+
+// ProfileCacheStats returns hit/miss counters of the profile cache.
+func (b *Business) ProfileCacheStats() CacheStats {
+	return b.profileStats.snapshot()
+}
+
+// profileTTL добавляет к TTL случайный джиттер, чтобы ключи не истекали одновременно.
+func (b *Business) profileTTL() time.Duration {
+	ttl := b.cfg.Cache.ProfileTTL
+	if jitter := b.cfg.Cache.ProfileTTLJitter; jitter > 0 {
+		ttl += rand.N(jitter)
+	}
+	return ttl
+}
+
+// invalidateProfile удаляет профиль из кеша. Ошибка не прерывает операцию:
+// устаревшая запись всё равно истечёт по TTL.
+func (b *Business) invalidateProfile(ctx context.Context, log *slog.Logger, userID int64) {
+	if err := b.cache.DeleteUserProfile(ctx, userID); err != nil {
+		b.profileStats.errors.Add(1)
+		log.Error("failed invalidate profile cache", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,404 @@
+package business
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	pgxclient "github.com/Krokozabra213/schools_backend/internal/pkg/pgx-client"
+	ssoconfig "github.com/Krokozabra213/schools_backend/services/sso/config"
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+	"github.com/Krokozabra213/schools_backend/services/sso/repository/postgres"
+	redisrepo "github.com/Krokozabra213/schools_backend/services/sso/repository/redis"
+)
+
+// fakeUsers — UserProvider в памяти. err, если задана, возвращают все методы.
+type fakeUsers struct {
+	mu     sync.Mutex
+	users  map[int64]*domain.User
+	nextID int64
+	err    error
+
+	// getCalls — сколько раз вызывался GetUserByID
+	getCalls int
+	// primaryReads — сколько из них помечены ContextWithPrimary
+	primaryReads int
+	// listArgs — limit и offset последнего ListUsers
+	listArgs [2]int32
+	// afterGet, если задан, вызывается после чтения в GetUserByID
+	afterGet func()
+}
+
+func newFakeUsers(users ...domain.User) *fakeUsers {
+	f := &fakeUsers{users: make(map[int64]*domain.User)}
+	for _, u := range users {
+		f.users[u.ID] = &u
+		f.nextID = max(f.nextID, u.ID)
+	}
+	return f
+}
+
+func (f *fakeUsers) user(id int64) (*domain.User, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	u, ok := f.users[id]
+	if !ok {
+		return nil, postgres.ErrNotFound
+	}
+	return u, nil
+}
+
+func (f *fakeUsers) UpdateUser(_ context.Context, params domain.UpdateUser) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.user(params.ID)
+	if err != nil {
+		return err
+	}
+	if params.Username != nil {
+		u.Username = *params.Username
+	}
+	if params.Email != nil {
+		u.Email = *params.Email
+	}
+	if params.Name != nil {
+		u.Name = *params.Name
+	}
+	if params.Surname != nil {
+		u.Surname = *params.Surname
+	}
+	if params.IsMale != nil {
+		u.IsMale = *params.IsMale
+	}
+	return nil
+}
+
+func (f *fakeUsers) CreateUser(_ context.Context, user *domain.CreateUser) (*domain.CreateUserRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	for _, u := range f.users {
+		if u.Username == user.Username {
+			return nil, postgres.ErrAlreadyExists
+		}
+	}
+
+	f.nextID++
+	f.users[f.nextID] = &domain.User{
+		ID:       f.nextID,
+		Username: user.Username,
+		Email:    user.Email,
+		Password: user.Password,
+		Name:     user.Name,
+		Surname:  user.Surname,
+		IsMale:   user.IsMale,
+		Role:     domain.RoleStudent,
+	}
+	return &domain.CreateUserRow{ID: f.nextID}, nil
+}
+
+func (f *fakeUsers) GetUserByUsername(_ context.Context, username string) (*domain.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	for _, u := range f.users {
+		if u.Username == username {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, postgres.ErrNotFound
+}
+
+func (f *fakeUsers) GetUserByID(ctx context.Context, id int64) (*domain.User, error) {
+	u, err := f.getUserByID(ctx, id)
+	if f.afterGet != nil {
+		f.afterGet()
+	}
+	return u, err
+}
+
+func (f *fakeUsers) getUserByID(ctx context.Context, id int64) (*domain.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.getCalls++
+	if pgxclient.PrimaryFromContext(ctx) {
+		f.primaryReads++
+	}
+	// как и настоящий запрос, отменённый контекст не доходит до базы
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	u, err := f.user(id)
+	if err != nil {
+		return nil, err
+	}
+	copied := *u
+	return &copied, nil
+}
+
+// UpdatePassword, как и запрос sqlc, не сообщает об отсутствии строки.
+func (f *fakeUsers) UpdatePassword(_ context.Context, id int64, password string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+	if u, ok := f.users[id]; ok {
+		u.Password = password
+	}
+	return nil
+}
+
+func (f *fakeUsers) SoftDeleteUser(_ context.Context, id int64) error {
+	return f.HardDeleteUser(context.Background(), id)
+}
+
+func (f *fakeUsers) HardDeleteUser(_ context.Context, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.user(id); err != nil {
+		return err
+	}
+	delete(f.users, id)
+	return nil
+}
+
+func (f *fakeUsers) CountUsers(context.Context) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return 0, f.err
+	}
+	return int64(len(f.users)), nil
+}
+
+func (f *fakeUsers) ListUsers(_ context.Context, limit, offset int32) ([]domain.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.listArgs = [2]int32{limit, offset}
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	ids := slices.Sorted(maps.Keys(f.users))
+	start := min(int(offset), len(ids))
+	end := min(start+int(limit), len(ids))
+
+	users := make([]domain.User, 0, end-start)
+	for _, id := range ids[start:end] {
+		users = append(users, *f.users[id])
+	}
+	return users, nil
+}
+
+func (f *fakeUsers) LockUser(_ context.Context, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.user(id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	u.LockedAt = &now
+	return nil
+}
+
+func (f *fakeUsers) UnlockUser(_ context.Context, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.user(id)
+	if err != nil {
+		return err
+	}
+	u.LockedAt = nil
+	return nil
+}
+
+func (f *fakeUsers) SetUserRole(_ context.Context, id int64, role domain.Role) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.user(id)
+	if err != nil {
+		return err
+	}
+	u.Role = role
+	return nil
+}
+
+func (f *fakeUsers) ExistsUserByUsername(ctx context.Context, username string) (bool, error) {
+	_, err := f.GetUserByUsername(ctx, username)
+	if err == postgres.ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (f *fakeUsers) ExistsUserByEmail(_ context.Context, email string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return false, f.err
+	}
+	for _, u := range f.users {
+		if u.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeCache — ProfileCache в памяти с ошибками на каждую операцию.
+// Как и Redis, после DeleteUserProfile не принимает профиль (надгробие).
+type fakeCache struct {
+	mu         sync.Mutex
+	profiles   map[int64]domain.UserCacheProfile
+	tombstones map[int64]bool
+
+	getErr, setErr, deleteErr error
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{
+		profiles:   make(map[int64]domain.UserCacheProfile),
+		tombstones: make(map[int64]bool),
+	}
+}
+
+func (f *fakeCache) CacheUserProfile(_ context.Context, profile *domain.UserCacheProfile, _ time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.setErr != nil {
+		return f.setErr
+	}
+	if f.tombstones[profile.ID] {
+		return redisrepo.ErrProfileInvalidated
+	}
+	f.profiles[profile.ID] = *profile
+	return nil
+}
+
+func (f *fakeCache) GetUserProfile(_ context.Context, userID int64) (*domain.UserCacheProfile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+	p, ok := f.profiles[userID]
+	if !ok {
+		return nil, redisrepo.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (f *fakeCache) DeleteUserProfile(_ context.Context, userID int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	delete(f.profiles, userID)
+	f.tombstones[userID] = true
+	return nil
+}
+
+func (f *fakeCache) cached(userID int64) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.profiles[userID]
+	return ok
+}
+
+// fakeTokens запоминает водяные знаки пользователей.
+type fakeTokens struct {
+	mu    sync.Mutex
+	bumps map[int64]time.Time
+	err   error
+}
+
+func (f *fakeTokens) BumpTokensValidAfter(_ context.Context, userID int64, at time.Time, _ time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+	if f.bumps == nil {
+		f.bumps = make(map[int64]time.Time)
+	}
+	f.bumps[userID] = at
+	return nil
+}
+
+func (f *fakeTokens) bumped(userID int64) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.bumps[userID]
+	return ok
+}
+
+// fakeTx выполняет fn без транзакции.
+type fakeTx struct{}
+
+func (fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) WithinTxLevel(ctx context.Context, _ pgxclient.IsolationLevel, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type testBusiness struct {
+	*Business
+	users  *fakeUsers
+	cache  *fakeCache
+	tokens *fakeTokens
+}
+
+func newTestBusiness(t *testing.T, users ...domain.User) *testBusiness {
+	t.Helper()
+
+	cfg := &ssoconfig.Config{
+		JWT: ssoconfig.JWTConfig{
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: time.Hour,
+		},
+		Cache: ssoconfig.CacheConfig{ProfileTTL: time.Minute},
+	}
+
+	tb := &testBusiness{
+		users:  newFakeUsers(users...),
+		cache:  newFakeCache(),
+		tokens: &fakeTokens{},
+	}
+	tb.Business = New(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)),
+		fakeTx{}, tb.users, tb.cache, tb.tokens, nil)
+	return tb
+}
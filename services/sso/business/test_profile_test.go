@@ -0,0 +1,202 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+)
+
+var testUser = domain.User{
+	ID:       1,
+	Username: "ivan",
+	Email:    "ivan@example.com",
+	Name:     "Ivan",
+	Surname:  "Petrov",
+	IsMale:   true,
+	Role:     domain.RoleStudent,
+}
+
+func TestGetProfile(t *testing.T) {
+	t.Run("cache hit", func(t *testing.T) {
+		b := newTestBusiness(t, testUser)
+		b.cache.profiles[testUser.ID] = domain.UserCacheProfile{ID: testUser.ID, Username: "cached"}
+
+		profile, err := b.GetProfile(context.Background(), testUser.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if profile.Username != "cached" {
+			t.Errorf("got username %q, want %q", profile.Username, "cached")
+		}
+		if b.users.getCalls != 0 {
+			t.Errorf("got %d database reads, want 0", b.users.getCalls)
+		}
+		if got := b.ProfileCacheStats(); got != (CacheStats{Hits: 1}) {
+			t.Errorf("got stats %+v, want one hit", got)
+		}
+	})
+
+	t.Run("miss populates cache", func(t *testing.T) {
+		b := newTestBusiness(t, testUser)
+
+		for range 2 {
+			profile, err := b.GetProfile(context.Background(), testUser.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if profile.Username != testUser.Username {
+				t.Errorf("got username %q, want %q", profile.Username, testUser.Username)
+			}
+		}
+		if b.users.getCalls != 1 {
+			t.Errorf("got %d database reads, want 1", b.users.getCalls)
+		}
+		if got := b.ProfileCacheStats(); got != (CacheStats{Hits: 1, Misses: 1}) {
+			t.Errorf("got stats %+v, want one hit and one miss", got)
+		}
+	})
+
+	t.Run("cache error falls back to database", func(t *testing.T) {
+		b := newTestBusiness(t, testUser)
+		b.cache.getErr = errors.New("redis down")
+		b.cache.setErr = errors.New("redis down")
+
+		profile, err := b.GetProfile(context.Background(), testUser.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if profile.ID != testUser.ID {
+			t.Errorf("got profile %d, want %d", profile.ID, testUser.ID)
+		}
+		if got := b.ProfileCacheStats(); got != (CacheStats{Misses: 1, Errors: 2}) {
+			t.Errorf("got stats %+v, want one miss and two errors", got)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		b := newTestBusiness(t)
+
+		if _, err := b.GetProfile(context.Background(), 404); !errors.Is(err, ErrUserNotFound) {
+			t.Errorf("got error %v, want %v", err, ErrUserNotFound)
+		}
+		if b.cache.cached(404) {
+			t.Error("missing user must not be cached")
+		}
+	})
+
+	t.Run("load ignores caller cancel", func(t *testing.T) {
+		b := newTestBusiness(t, testUser)
+
+		// результат singleflight делят все ожидающие: отмена одного из них
+		// не должна обрывать загрузку
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := b.GetProfile(ctx, testUser.ID); err != nil {
+			t.Fatalf("got error %v, want profile", err)
+		}
+		if !b.cache.cached(testUser.ID) {
+			t.Error("profile was not cached")
+		}
+	})
+}
+
+func TestProfileInvalidation(t *testing.T) {
+	t.Run("update user", func(t *testing.T) {
+		b := newTestBusiness(t, testUser)
+		ctx := context.Background()
+
+		if _, err := b.GetProfile(ctx, testUser.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		name := "Pyotr"
+		if err := b.UpdateUser(ctx, testUser.ID, domain.UpdateUser{ID: testUser.ID, Name: &name}); err != nil {
+			t.Fatal(err)
+		}
+		if b.cache.cached(testUser.ID) {
+			t.Fatal("profile stays cached after update")
+		}
+
+		profile, err := b.GetProfile(ctx, testUser.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if profile.Name != name {
+			t.Errorf("got name %q, want %q", profile.Name, name)
+		}
+	})
+
+	t.Run("delete user", func(t *testing.T) {
+		b := newTestBusiness(t, testUser)
+		ctx := context.Background()
+
+		if _, err := b.GetProfile(ctx, testUser.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := b.DeleteUser(ctx, testUser.ID, testUser.ID); err != nil {
+			t.Fatal(err)
+		}
+		if b.cache.cached(testUser.ID) {
+			t.Error("profile stays cached after delete")
+		}
+		if !b.tokens.bumped(testUser.ID) {
+			t.Error("tokens of deleted user were not revoked")
+		}
+		if _, err := b.GetProfile(ctx, testUser.ID); !errors.Is(err, ErrUserNotFound) {
+			t.Errorf("got error %v, want %v", err, ErrUserNotFound)
+		}
+	})
+
+	t.Run("load racing with update is not cached", func(t *testing.T) {
+		b := newTestBusiness(t, testUser)
+		ctx := context.Background()
+
+		// загрузка прочитала строку, и до записи в кеш пользователя изменили
+		name := "Pyotr"
+		b.users.afterGet = func() {
+			b.users.afterGet = nil
+			if err := b.UpdateUser(ctx, testUser.ID, domain.UpdateUser{ID: testUser.ID, Name: &name}); err != nil {
+				t.Error(err)
+			}
+		}
+
+		profile, err := b.GetProfile(ctx, testUser.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if profile.Name != testUser.Name {
+			t.Fatalf("got name %q, want the one read before update %q", profile.Name, testUser.Name)
+		}
+		if b.cache.cached(testUser.ID) {
+			t.Fatal("profile read before update was cached")
+		}
+
+		profile, err = b.GetProfile(ctx, testUser.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if profile.Name != name {
+			t.Errorf("got name %q, want %q", profile.Name, name)
+		}
+		if got := b.ProfileCacheStats().Errors; got != 0 {
+			t.Errorf("got %d cache errors, want 0", got)
+		}
+	})
+
+	t.Run("invalidation error does not fail update", func(t *testing.T) {
+		b := newTestBusiness(t, testUser)
+		b.cache.deleteErr = errors.New("redis down")
+
+		name := "Pyotr"
+		if err := b.UpdateUser(context.Background(), testUser.ID, domain.UpdateUser{ID: testUser.ID, Name: &name}); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got := b.ProfileCacheStats().Errors; got != 1 {
+			t.Errorf("got %d cache errors, want 1", got)
+		}
+	})
+}
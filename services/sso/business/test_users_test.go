@@ -0,0 +1,202 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLockUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lock and unlock", func(t *testing.T) {
+		b := newTestBusiness(t, testUser)
+
+		if err := b.LockUser(ctx, testUser.ID); err != nil {
+			t.Fatal(err)
+		}
+		if b.users.users[testUser.ID].LockedAt == nil {
+			t.Fatal("user was not locked")
+		}
+		if !b.tokens.bumped(testUser.ID) {
+			t.Error("tokens of locked user were not revoked")
+		}
+
+		if err := b.UnlockUser(ctx, testUser.ID); err != nil {
+			t.Fatal(err)
+		}
+		if b.users.users[testUser.ID].LockedAt != nil {
+			t.Error("user stays locked after unlock")
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		b := newTestBusiness(t)
+
+		if err := b.LockUser(ctx, 404); !errors.Is(err, ErrUserNotFound) {
+			t.Errorf("lock: got error %v, want %v", err, ErrUserNotFound)
+		}
+		if err := b.UnlockUser(ctx, 404); !errors.Is(err, ErrUserNotFound) {
+			t.Errorf("unlock: got error %v, want %v", err, ErrUserNotFound)
+		}
+	})
+
+	t.Run("tokens not revoked", func(t *testing.T) {
+		b := newTestBusiness(t, testUser)
+		b.tokens.err = errors.New("redis down")
+
+		if err := b.LockUser(ctx, testUser.ID); !errors.Is(err, ErrTokensNotRevoked) {
+			t.Errorf("got error %v, want %v", err, ErrTokensNotRevoked)
+		}
+		// блокировка уже сохранена, повторять нужно только отзыв
+		if b.users.users[testUser.ID].LockedAt == nil {
+			t.Error("lock was not committed")
+		}
+	})
+}
+
+func TestAssignRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int64
+		role     domain.Role
+		wantErr  error
+		wantRole domain.Role
+	}{
+		{name: "success", userID: testUser.ID, role: domain.RoleTeacher, wantRole: domain.RoleTeacher},
+		{name: "invalid role", userID: testUser.ID, role: "janitor", wantErr: ErrInvalidRole, wantRole: testUser.Role},
+		{name: "not found", userID: 404, role: domain.RoleTeacher, wantErr: ErrUserNotFound, wantRole: testUser.Role},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBusiness(t, testUser)
+
+			err := b.AssignRole(context.Background(), tt.userID, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+			if got := b.users.users[testUser.ID].Role; got != tt.wantRole {
+				t.Errorf("got role %q, want %q", got, tt.wantRole)
+			}
+			if got := b.tokens.bumped(testUser.ID); got != (tt.wantErr == nil) {
+				t.Errorf("got tokens revoked %v, want %v", got, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		b := newTestBusiness(t, testUser)
+
+		if err := b.ResetPassword(ctx, testUser.ID, "s3cret"); err != nil {
+			t.Fatal(err)
+		}
+		hash := b.users.users[testUser.ID].Password
+		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte("s3cret")); err != nil {
+			t.Errorf("stored password does not match: %v", err)
+		}
+		if !b.tokens.bumped(testUser.ID) {
+			t.Error("tokens were not revoked")
+		}
+		// только что созданного пользователя реплика может ещё не видеть
+		if b.users.primaryReads != 1 {
+			t.Errorf("got %d primary reads, want 1", b.users.primaryReads)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		b := newTestBusiness(t)
+
+		if err := b.ResetPassword(ctx, 404, "s3cret"); !errors.Is(err, ErrUserNotFound) {
+			t.Errorf("got error %v, want %v", err, ErrUserNotFound)
+		}
+		if b.tokens.bumped(404) {
+			t.Error("tokens of missing user were revoked")
+		}
+	})
+}
+
+func TestCreateUserRole(t *testing.T) {
+	newUser := func(role domain.Role) *domain.CreateUser {
+		return &domain.CreateUser{
+			Username: "petr",
+			Email:    "petr@example.com",
+			Password: "s3cret",
+			Role:     role,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		role     domain.Role
+		wantErr  error
+		wantRole domain.Role
+	}{
+		{name: "default role", wantRole: domain.RoleStudent},
+		{name: "explicit role", role: domain.RoleAdmin, wantRole: domain.RoleAdmin},
+		{name: "invalid role", role: "janitor", wantErr: ErrInvalidRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBusiness(t)
+
+			row, err := b.CreateUser(context.Background(), newUser(tt.role))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(b.users.users) != 0 {
+					t.Error("user was created with invalid role")
+				}
+				return
+			}
+			if got := b.users.users[row.ID].Role; got != tt.wantRole {
+				t.Errorf("got role %q, want %q", got, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestListUsersPagination(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit, offset int
+		wantErr       error
+	}{
+		{name: "first page", limit: 1, offset: 0},
+		{name: "max limit", limit: MaxListLimit, offset: 0},
+		{name: "far offset", limit: 10, offset: 100},
+		{name: "zero limit", limit: 0, wantErr: ErrInvalidPagination},
+		{name: "negative limit", limit: -1, wantErr: ErrInvalidPagination},
+		{name: "limit over max", limit: MaxListLimit + 1, wantErr: ErrInvalidPagination},
+		{name: "negative offset", limit: 10, offset: -1, wantErr: ErrInvalidPagination},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBusiness(t, testUser)
+
+			_, err := b.ListUsers(context.Background(), tt.limit, tt.offset)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			// неверные границы не должны доходить до базы
+			want := [2]int32{}
+			if tt.wantErr == nil {
+				want = [2]int32{int32(tt.limit), int32(tt.offset)}
+			}
+			if b.users.listArgs != want {
+				t.Errorf("got repository args %v, want %v", b.users.listArgs, want)
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package business
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	pgxclient "github.com/Krokozabra213/schools_backend/internal/pkg/pgx-client"
 	"github.com/Krokozabra213/schools_backend/services/sso/domain"
 	"github.com/Krokozabra213/schools_backend/services/sso/repository/postgres"
 	"golang.org/x/crypto/bcrypt"
@@ -13,25 +16,22 @@ import (
 func (b *Business) CreateUser(ctx context.Context, user *domain.CreateUser) (*domain.CreateUserRow, error) {
 	const op = "business.CreateUser"
 
-	log := b.log.With(
+	log := logger.FromContext(ctx, b.log).With(
 		slog.String("op", op),
 		slog.String("username", user.Username),
 	)
 	log.Info("starting user registration process...")
 
-	exists, err := b.user.ExistsUserByEmail(ctx, user.Email)
-	if err != nil {
-		log.Error("failed to check email existence", slog.String("error", err.Error()))
-		return nil, ErrInternal
-	}
-	if exists {
-		log.Warn("email already exists")
-		return nil, ErrEmailExists
+	if user.Role != "" && !user.Role.Valid() {
+		log.Warn("invalid role", slog.String("role", string(user.Role)))
+		return nil, ErrInvalidRole
 	}
 
+	// хешируем до транзакции: bcrypt медленный, а fn может повторяться
 	hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		log.Error("failed to hash password", slog.String("error", err.Error()))
+		b.metrics.RegistrationFailed(reasonInternal)
 		return nil, err
 	}
 
@@ -39,15 +39,48 @@ func (b *Business) CreateUser(ctx context.Context, user *domain.CreateUser) (*do
 	_ = user.Password
 	user.Password = string(hash)
 
-	result, err := b.user.CreateUser(ctx, user)
+	var result *domain.CreateUserRow
+
+	// Serializable: проверка email и вставка не должны гоняться с параллельной регистрацией
+	err = b.tx.WithinTxLevel(ctx, pgxclient.Serializable, func(ctx context.Context) error {
+		exists, err := b.user.ExistsUserByEmail(ctx, user.Email)
+		if err != nil {
+			return fmt.Errorf("check email existence: %w", err)
+		}
+		if exists {
+			return ErrEmailExists
+		}
+
+		result, err = b.user.CreateUser(ctx, user)
+		if err != nil {
+			return fmt.Errorf("create user: %w", err)
+		}
+
+		// в той же транзакции: пользователь не должен остаться с ролью по умолчанию
+		if user.Role != "" {
+			if err := b.user.SetUserRole(ctx, result.ID, user.Role); err != nil {
+				return fmt.Errorf("set user role: %w", err)
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		log.Error("failed create new user", slog.String("error", err.Error()))
-		if errors.Is(err, postgres.ErrAlreadyExists) {
+		switch {
+		case errors.Is(err, ErrEmailExists):
+			log.Warn("email already exists")
+			b.metrics.RegistrationFailed(reasonEmailExists)
+			return nil, ErrEmailExists
+		case errors.Is(err, postgres.ErrAlreadyExists):
+			log.Warn("user already exists", slog.String("error", err.Error()))
+			b.metrics.RegistrationFailed(reasonUserExists)
 			return nil, ErrUserExists
 		}
+		log.Error("failed create new user", slog.String("error", err.Error()))
+		b.metrics.RegistrationFailed(reasonInternal)
 		return nil, ErrInternal
 	}
 
+	b.metrics.UserRegistered()
 	log.Info("user successfully registered",
 		slog.Int64("user_id", result.ID))
 
@@ -0,0 +1,44 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	"github.com/Krokozabra213/schools_backend/services/sso/repository/postgres"
+)
+
+func (b *Business) DeleteUser(ctx context.Context, actorID, targetID int64) error {
+	const op = "business.DeleteUser"
+
+	log := logger.FromContext(ctx, b.log).With(
+		slog.String("op", op),
+		slog.Int64("target_user_id", targetID),
+		slog.Int64("actor_id", actorID),
+	)
+	log.Info("starting delete user process...")
+
+	if err := b.checkUpdatePermission(ctx, actorID, targetID); err != nil {
+		log.Warn("permission denied", slog.String("error", err.Error()))
+		return ErrPermissionDenied
+	}
+
+	if err := b.user.SoftDeleteUser(ctx, targetID); err != nil {
+		log.Error("failed delete user", slog.String("error", err.Error()))
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return ErrInternal
+	}
+
+	b.invalidateProfile(ctx, log, targetID)
+
+	// удалённый пользователь не должен продолжать работать с выданными токенами
+	if err := b.revokeUserTokens(ctx, log, targetID); err != nil {
+		return ErrTokensNotRevoked
+	}
+
+	log.Info("user successfully deleted")
+	return nil
+}
@@ -0,0 +1,51 @@
+package business
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+)
+
+// MaxListLimit ограничивает размер одной страницы ListUsers.
+const MaxListLimit = 1000
+
+// ListUsers возвращает страницу активных пользователей без паролей.
+func (b *Business) ListUsers(ctx context.Context, limit, offset int) ([]domain.User, error) {
+	const op = "business.ListUsers"
+
+	log := logger.FromContext(ctx, b.log).With(
+		slog.String("op", op),
+		slog.Int("limit", limit),
+		slog.Int("offset", offset),
+	)
+
+	if limit <= 0 || limit > MaxListLimit || offset < 0 {
+		log.Warn("invalid pagination")
+		return nil, ErrInvalidPagination
+	}
+
+	users, err := b.user.ListUsers(ctx, int32(limit), int32(offset))
+	if err != nil {
+		log.Error("failed list users", slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+
+	return users, nil
+}
+
+// CountUsers возвращает число активных (не удалённых) пользователей.
+func (b *Business) CountUsers(ctx context.Context) (int64, error) {
+	const op = "business.CountUsers"
+
+	count, err := b.user.CountUsers(ctx)
+	if err != nil {
+		logger.FromContext(ctx, b.log).Error("failed count users",
+			slog.String("op", op),
+			slog.String("error", err.Error()))
+		return 0, ErrInternal
+	}
+
+	return count, nil
+}
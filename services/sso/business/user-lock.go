@@ -0,0 +1,60 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	"github.com/Krokozabra213/schools_backend/services/sso/repository/postgres"
+)
+
+// LockUser блокирует пользователя и отзывает его токены.
+// Административная операция: права проверяет вызывающий (ssoctl).
+func (b *Business) LockUser(ctx context.Context, userID int64) error {
+	const op = "business.LockUser"
+
+	log := logger.FromContext(ctx, b.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+	log.Info("starting lock user process...")
+
+	if err := b.user.LockUser(ctx, userID); err != nil {
+		log.Error("failed lock user", slog.String("error", err.Error()))
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return ErrInternal
+	}
+
+	// заблокированный пользователь не должен работать с уже выданными токенами
+	if err := b.revokeUserTokens(ctx, log, userID); err != nil {
+		return ErrTokensNotRevoked
+	}
+
+	log.Info("user successfully locked")
+	return nil
+}
+
+// UnlockUser снимает блокировку. Отозванные токены не восстанавливаются.
+func (b *Business) UnlockUser(ctx context.Context, userID int64) error {
+	const op = "business.UnlockUser"
+
+	log := logger.FromContext(ctx, b.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+	log.Info("starting unlock user process...")
+
+	if err := b.user.UnlockUser(ctx, userID); err != nil {
+		log.Error("failed unlock user", slog.String("error", err.Error()))
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return ErrInternal
+	}
+
+	log.Info("user successfully unlocked")
+	return nil
+}
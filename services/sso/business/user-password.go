@@ -0,0 +1,105 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	pgxclient "github.com/Krokozabra213/schools_backend/internal/pkg/pgx-client"
+	"github.com/Krokozabra213/schools_backend/services/sso/repository/postgres"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (b *Business) ChangePassword(ctx context.Context, actorID, userID int64, oldPassword, newPassword string) error {
+	const op = "business.ChangePassword"
+
+	log := logger.FromContext(ctx, b.log).With(
+		slog.String("op", op),
+		slog.Int64("target_user_id", userID),
+		slog.Int64("actor_id", actorID),
+	)
+	log.Info("starting change password process...")
+
+	if err := b.checkUpdatePermission(ctx, actorID, userID); err != nil {
+		log.Warn("permission denied", slog.String("error", err.Error()))
+		return ErrPermissionDenied
+	}
+
+	// старый пароль сверяем с primary: на реплике хеш может быть до прошлой смены
+	user, err := b.user.GetUserByID(pgxclient.ContextWithPrimary(ctx), userID)
+	if err != nil {
+		log.Error("failed get user", slog.String("error", err.Error()))
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return ErrInternal
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
+		log.Warn("invalid old password")
+		return ErrInvalidCredentials
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error("failed to hash password", slog.String("error", err.Error()))
+		return ErrInternal
+	}
+
+	if err := b.user.UpdatePassword(ctx, userID, string(hash)); err != nil {
+		log.Error("failed update password", slog.String("error", err.Error()))
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return ErrInternal
+	}
+
+	// старые access токены не должны пережить смену пароля
+	if err := b.revokeUserTokens(ctx, log, userID); err != nil {
+		return ErrTokensNotRevoked
+	}
+
+	log.Info("password successfully changed")
+	return nil
+}
+
+// ResetPassword задаёт новый пароль без проверки старого и отзывает токены.
+// Административная операция: права проверяет вызывающий (ssoctl).
+func (b *Business) ResetPassword(ctx context.Context, userID int64, newPassword string) error {
+	const op = "business.ResetPassword"
+
+	log := logger.FromContext(ctx, b.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+	log.Info("starting reset password process...")
+
+	// UpdatePassword не сообщает об отсутствии строки, поэтому проверяем
+	// заранее — на primary: реплика может ещё не знать о новом пользователе
+	if _, err := b.user.GetUserByID(pgxclient.ContextWithPrimary(ctx), userID); err != nil {
+		log.Error("failed get user", slog.String("error", err.Error()))
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return ErrInternal
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error("failed to hash password", slog.String("error", err.Error()))
+		return ErrInternal
+	}
+
+	if err := b.user.UpdatePassword(ctx, userID, string(hash)); err != nil {
+		log.Error("failed update password", slog.String("error", err.Error()))
+		return ErrInternal
+	}
+
+	if err := b.revokeUserTokens(ctx, log, userID); err != nil {
+		return ErrTokensNotRevoked
+	}
+
+	log.Info("password successfully reset")
+	return nil
+}
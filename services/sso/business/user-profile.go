@@ -0,0 +1,89 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	pgxclient "github.com/Krokozabra213/schools_backend/internal/pkg/pgx-client"
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+	"github.com/Krokozabra213/schools_backend/services/sso/repository/postgres"
+	redisrepo "github.com/Krokozabra213/schools_backend/services/sso/repository/redis"
+)
+
+// profileLoadTimeout ограничивает загрузку профиля в singleflight: она
+// отвязана от контекста первого вызывающего.
+const profileLoadTimeout = 5 * time.Second
+
+// GetProfile returns the user profile, reading through the Redis cache.
+func (b *Business) GetProfile(ctx context.Context, userID int64) (*domain.UserCacheProfile, error) {
+	const op = "business.GetProfile"
+
+	log := logger.FromContext(ctx, b.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	profile, err := b.cache.GetUserProfile(ctx, userID)
+	if err == nil {
+		b.profileStats.hits.Add(1)
+		return profile, nil
+	}
+	if !errors.Is(err, redisrepo.ErrNotFound) {
+		// кеш недоступен — идём в базу
+		b.profileStats.errors.Add(1)
+		log.Warn("failed get profile from cache", slog.String("error", err.Error()))
+	}
+	b.profileStats.misses.Add(1)
+
+	// singleflight: на один userID в базу идёт только один запрос. Результат
+	// получат все ожидающие, поэтому отмена запроса первого из них не должна
+	// обрывать загрузку для остальных.
+	v, err, _ := b.profileGroup.Do(strconv.FormatInt(userID, 10), func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), profileLoadTimeout)
+		defer cancel()
+		return b.loadProfile(ctx, log, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*domain.UserCacheProfile), nil
+}
+
+func (b *Business) loadProfile(ctx context.Context, log *slog.Logger, userID int64) (*domain.UserCacheProfile, error) {
+	// профиль читаем с primary: после UpdateUser кеш сброшен, и устаревшая
+	// реплика вернула бы в кеш старые данные на весь TTL
+	user, err := b.user.GetUserByID(pgxclient.ContextWithPrimary(ctx), userID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		log.Error("failed get user", slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+
+	profile := &domain.UserCacheProfile{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Name:     user.Name,
+		Surname:  user.Surname,
+		IsMale:   user.IsMale,
+	}
+
+	err = b.cache.CacheUserProfile(ctx, profile, b.profileTTL())
+	switch {
+	case errors.Is(err, redisrepo.ErrProfileInvalidated):
+		// пока шла загрузка, профиль изменили: прочитанное не кешируем
+		log.Debug("profile changed during load, not cached")
+	case err != nil:
+		b.profileStats.errors.Add(1)
+		log.Warn("failed cache profile", slog.String("error", err.Error()))
+	}
+
+	return profile, nil
+}
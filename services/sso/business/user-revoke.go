@@ -0,0 +1,38 @@
+package business
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+)
+
+// RevokeUserTokens отзывает все выданные пользователю токены, включая access.
+// Вызывается при смене пароля, блокировке и смене роли.
+func (b *Business) RevokeUserTokens(ctx context.Context, userID int64) error {
+	const op = "business.RevokeUserTokens"
+
+	log := logger.FromContext(ctx, b.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	if err := b.revokeUserTokens(ctx, log, userID); err != nil {
+		return ErrInternal
+	}
+
+	log.Info("user tokens revoked")
+	return nil
+}
+
+func (b *Business) revokeUserTokens(ctx context.Context, log *slog.Logger, userID int64) error {
+	// водяной знак должен жить не меньше самого долгого токена
+	ttl := max(b.cfg.JWT.AccessTokenTTL, b.cfg.JWT.RefreshTokenTTL)
+
+	if err := b.tokens.BumpTokensValidAfter(ctx, userID, time.Now(), ttl); err != nil {
+		log.Error("failed revoke user tokens", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
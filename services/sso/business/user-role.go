@@ -0,0 +1,74 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	pgxclient "github.com/Krokozabra213/schools_backend/internal/pkg/pgx-client"
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+	"github.com/Krokozabra213/schools_backend/services/sso/repository/postgres"
+)
+
+// AssignRole меняет роль пользователя и отзывает его токены, чтобы
+// новые права вступили в силу сразу, а не после истечения access токена.
+// Административная операция: права проверяет вызывающий (ssoctl).
+func (b *Business) AssignRole(ctx context.Context, userID int64, role domain.Role) error {
+	const op = "business.AssignRole"
+
+	log := logger.FromContext(ctx, b.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.String("role", string(role)),
+	)
+	log.Info("starting assign role process...")
+
+	if !role.Valid() {
+		log.Warn("invalid role")
+		return ErrInvalidRole
+	}
+
+	if err := b.user.SetUserRole(ctx, userID, role); err != nil {
+		log.Error("failed set user role", slog.String("error", err.Error()))
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return ErrInternal
+	}
+
+	if err := b.revokeUserTokens(ctx, log, userID); err != nil {
+		return ErrTokensNotRevoked
+	}
+
+	log.Info("role successfully assigned")
+	return nil
+}
+
+// AuthorizeAdmin проверяет, что пользователь — незаблокированный администратор.
+// Роль читается из базы, а не из токена: снятие роли действует сразу.
+func (b *Business) AuthorizeAdmin(ctx context.Context, userID int64) error {
+	const op = "business.AuthorizeAdmin"
+
+	log := logger.FromContext(ctx, b.log).With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	// права — только с primary: снятая роль не должна жить до догона реплики
+	user, err := b.user.GetUserByID(pgxclient.ContextWithPrimary(ctx), userID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			log.Warn("admin access by unknown user")
+			return ErrPermissionDenied
+		}
+		log.Error("failed get user", slog.String("error", err.Error()))
+		return ErrInternal
+	}
+
+	if user.Role != domain.RoleAdmin || user.Locked() {
+		log.Warn("admin access denied", slog.String("role", string(user.Role)))
+		return ErrPermissionDenied
+	}
+	return nil
+}
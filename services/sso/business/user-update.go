@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
 	"github.com/Krokozabra213/schools_backend/services/sso/domain"
 	"github.com/Krokozabra213/schools_backend/services/sso/repository/postgres"
 )
@@ -12,7 +13,7 @@ import (
 func (b *Business) UpdateUser(ctx context.Context, actorID int64, params domain.UpdateUser) error {
 	const op = "business.UpdateUser"
 
-	log := b.log.With(
+	log := logger.FromContext(ctx, b.log).With(
 		slog.String("op", op),
 		slog.Int64("target_user_id", params.ID),
 		slog.Int64("actor_id", actorID),
@@ -34,6 +35,8 @@ func (b *Business) UpdateUser(ctx context.Context, actorID int64, params domain.
 		return ErrInternal
 	}
 
+	b.invalidateProfile(ctx, log, params.ID)
+
 	log.Info("user successfully updated")
 	return nil
 }
@@ -1,138 +1,370 @@
-package ssoconfig
-
-import (
-	"fmt"
-	"log/slog"
-	"os"
-	"time"
-
-	"github.com/ilyakaznacheev/cleanenv"
-	"github.com/joho/godotenv"
-)
-
-type Config struct {
-	App   AppConfig      `yaml:"app"`
-	HTTP  HTTPConfig     `yaml:"http"`
-	GRPC  GRPCConfig     `yaml:"grpc"`
-	PG    PostgresConfig `yaml:"postgres"`
-	Redis RedisConfig    `yaml:"redis"`
-	JWT   JWTConfig      `yaml:"jwt"`
-}
-
-type AppConfig struct {
-	Environment  string `env:"SSO_ENV" env-default:"development"`
-	AppSecretKey string `env:"SSO_APP_SECRET" env-required:"true"`
-}
-
-type PostgresConfig struct {
-	Host     string `env:"SSO_POSTGRES_HOST" env-required:"true"`
-	Port     string `env:"SSO_POSTGRES_PORT" env-default:"5432"`
-	User     string `env:"SSO_POSTGRES_USER" env-required:"true"`
-	Password string `env:"SSO_POSTGRES_PASSWORD" env-required:"true"`
-	DBName   string `env:"SSO_POSTGRES_DB" env-required:"true"`
-
-	MaxConns int `yaml:"maxConns" env:"SSO_PG_MAX_CONNS" env-default:"10"`
-	MinConns int `yaml:"minConns" env:"SSO_PG_MIN_CONNS" env-default:"2"`
-}
-
-type RedisConfig struct {
-	Addr     string `env:"SSO_REDIS_ADDR" env-required:"true"`
-	Password string `env:"SSO_REDIS_PASSWORD" env-required:"true"`
-	Database int    `env:"SSO_REDIS_DATABASE" env-default:"0"`
-
-	PoolSize     int `yaml:"poolSize" env:"SSO_REDIS_POOL_SIZE" env-default:"10"`
-	MinIdleConns int `yaml:"minIdleConns" env:"SSO_REDIS_MIN_IDLE_CONNS" env-default:"2"`
-}
-
-type HTTPConfig struct {
-	Host               string        `yaml:"host" env:"SSO_HTTP_HOST" env-default:"0.0.0.0"`
-	Port               string        `yaml:"port" env:"SSO_HTTP_PORT" env-default:"8080"`
-	ReadTimeout        time.Duration `yaml:"readTimeout" env:"SSO_HTTP_READ_TIMEOUT" env-default:"10s"`
-	WriteTimeout       time.Duration `yaml:"writeTimeout" env:"SSO_HTTP_WRITE_TIMEOUT" env-default:"10s"`
-	MaxHeaderMegabytes int           `yaml:"maxHeaderBytes" env:"SSO_HTTP_MAX_HEADER_BYTES" env-default:"1"`
-}
-
-type GRPCConfig struct {
-	Host               string        `yaml:"host" env:"SSO_GRPC_HOST" env-default:"0.0.0.0"`
-	Port               string        `yaml:"port" env:"SSO_GRPC_PORT" env-default:"44050"`
-	ReadTimeout        time.Duration `yaml:"readTimeout" env:"SSO_GRPC_READ_TIMEOUT" env-default:"10s"`
-	WriteTimeout       time.Duration `yaml:"writeTimeout" env:"SSO_GRPC_WRITE_TIMEOUT" env-default:"10s"`
-	MaxHeaderMegabytes int           `yaml:"maxHeaderBytes" env:"SSO_GRPC_MAX_HEADER_BYTES" env-default:"1"`
-}
-
-type JWTConfig struct {
-	AccessTokenTTL  time.Duration `yaml:"accessTokenTTL" env:"SSO_JWT_ACCESS_TTL" env-default:"15m"`
-	RefreshTokenTTL time.Duration `yaml:"refreshTokenTTL" env:"SSO_JWT_REFRESH_TTL" env-default:"720h"`
-	PrivateKeyPath  string        `yaml:"privateKeyPath" env:"SSO_JWT_PRIVATE_KEY_PATH" env-default:"private.pem"`
-	PrivateKey      []byte        `yaml:"-" env:"-"`
-}
-
-func MustInit(configFile string) *Config {
-	cfg, err := Init(configFile)
-	if err != nil {
-		panic("config: " + err.Error())
-	}
-	return cfg
-}
-
-func Init(configFile string) (*Config, error) {
-
-	if err := godotenv.Load(); err != nil {
-		return nil, fmt.Errorf("load env file: %w", err)
-	}
-
-	var cfg Config
-
-	if err := cleanenv.ReadConfig(configFile, &cfg); err != nil {
-		return nil, fmt.Errorf("read config: %w", err)
-	}
-
-	keyData, err := os.ReadFile(cfg.JWT.PrivateKeyPath)
-	if err != nil {
-		return nil, fmt.Errorf("read private key %q: %w", cfg.JWT.PrivateKeyPath, err)
-	}
-	cfg.JWT.PrivateKey = keyData
-
-	return &cfg, nil
-}
-
-func (c *Config) LogValue() slog.Value {
-	return slog.GroupValue(
-		slog.String("env", c.App.Environment),
-
-		slog.Group("http",
-			slog.String("address", c.HTTP.Host+":"+c.HTTP.Port),
-			slog.Duration("read_timeout", c.HTTP.ReadTimeout),
-			slog.Duration("write_timeout", c.HTTP.WriteTimeout),
-			slog.Int("max_header_megabytes", c.HTTP.MaxHeaderMegabytes),
-		),
-
-		slog.Group("grpc",
-			slog.String("address", c.GRPC.Host+":"+c.GRPC.Port),
-			slog.Duration("read_timeout", c.GRPC.ReadTimeout),
-			slog.Duration("write_timeout", c.GRPC.WriteTimeout),
-			slog.Int("max_header_megabytes", c.GRPC.MaxHeaderMegabytes),
-		),
-
-		slog.Group("jwt",
-			slog.Duration("access_token_ttl", c.JWT.AccessTokenTTL),
-			slog.Duration("refresh_token_ttl", c.JWT.RefreshTokenTTL),
-			slog.String("private_key_path", c.JWT.PrivateKeyPath),
-		),
-
-		slog.Group("postgres",
-			slog.String("address", c.PG.Host+":"+c.PG.Port),
-			slog.String("database", c.PG.DBName),
-			slog.String("user", c.PG.User),
-			slog.Int("max_conns", c.PG.MaxConns),
-			slog.Int("min_conns", c.PG.MinConns),
-		),
-
-		slog.Group("redis",
-			slog.String("address", c.Redis.Addr),
-			slog.Int("database", c.Redis.Database),
-			slog.Int("pool_size", c.Redis.PoolSize),
-			slog.Int("min_idle_conns", c.Redis.MinIdleConns),
-		),
-	)
-}
+package ssoconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+	ratelimiterv1 "github.com/Krokozabra213/schools_backend/internal/pkg/rate-limiter/v1"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/secrets"
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	App   AppConfig      `yaml:"app"`
+	HTTP  HTTPConfig     `yaml:"http"`
+	GRPC  GRPCConfig     `yaml:"grpc"`
+	PG    PostgresConfig `yaml:"postgres"`
+	Redis RedisConfig    `yaml:"redis"`
+	JWT   JWTConfig      `yaml:"jwt"`
+	Cache CacheConfig    `yaml:"cache"`
+
+	Tracing    TracingConfig    `yaml:"tracing"`
+	Health     HealthConfig     `yaml:"health"`
+	Secrets    SecretsConfig    `yaml:"secrets"`
+	Resilience ResilienceConfig `yaml:"resilience"`
+
+	RateLimit ratelimiterv1.Spec `yaml:"rateLimit" env-prefix:"SSO_RATE_LIMIT_"`
+}
+
+type AppConfig struct {
+	Environment string `env:"SSO_ENV" env-default:"development"`
+	// AppSecretKey — секрет SSO_APP_SECRET, см. SecretsConfig
+	AppSecretKey secrets.Secret `yaml:"-"`
+
+	// LogLevel переопределяет уровень окружения (debug, info, warn, error).
+	// Применяется на лету при изменении файла.
+	LogLevel string `yaml:"logLevel" env:"SSO_LOG_LEVEL"`
+	// LogRedactKeys — атрибуты, скрываемые в логе сверх встроенных (password, token, ...).
+	LogRedactKeys []string `yaml:"logRedactKeys" env:"SSO_LOG_REDACT_KEYS" env-separator:","`
+	// LogSampleInterval и LogSampleBurst: за интервал пишется не больше burst
+	// одинаковых записей, об остальных — сводка. Ноль отключает семплирование.
+	LogSampleInterval time.Duration `yaml:"logSampleInterval" env:"SSO_LOG_SAMPLE_INTERVAL" env-default:"1s"`
+	LogSampleBurst    int           `yaml:"logSampleBurst" env:"SSO_LOG_SAMPLE_BURST" env-default:"10"`
+	// LogBufferSize — буфер асинхронной записи в записях; ноль — синхронно.
+	LogBufferSize int `yaml:"logBufferSize" env:"SSO_LOG_BUFFER_SIZE" env-default:"0"`
+
+	// ReloadInterval — как часто проверять файл конфигурации на изменения
+	ReloadInterval time.Duration `yaml:"reloadInterval" env:"SSO_CONFIG_RELOAD_INTERVAL" env-default:"10s"`
+	// ShutdownTimeout — сколько ждать завершения текущих запросов при остановке
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SSO_SHUTDOWN_TIMEOUT" env-default:"15s"`
+	// ShutdownDelay — сколько после перехода в not ready ещё принимать
+	// запросы, пока балансировщик не исключит реплику. Входит в ShutdownTimeout.
+	ShutdownDelay time.Duration `yaml:"shutdownDelay" env:"SSO_SHUTDOWN_DELAY" env-default:"0s"`
+}
+
+type PostgresConfig struct {
+	Host   string `env:"SSO_POSTGRES_HOST" env-required:"true"`
+	Port   string `env:"SSO_POSTGRES_PORT" env-default:"5432"`
+	User   string `env:"SSO_POSTGRES_USER" env-required:"true"`
+	DBName string `env:"SSO_POSTGRES_DB" env-required:"true"`
+	// Password — секрет SSO_POSTGRES_PASSWORD
+	Password secrets.Secret `yaml:"-"`
+
+	SSLMode         string        `yaml:"sslMode" env:"SSO_PG_SSL_MODE" env-default:"disable"`
+	ConnectTimeout  time.Duration `yaml:"connectTimeout" env:"SSO_PG_CONNECT_TIMEOUT" env-default:"5s"`
+	MaxConns        int           `yaml:"maxConns" env:"SSO_PG_MAX_CONNS" env-default:"10"`
+	MinConns        int           `yaml:"minConns" env:"SSO_PG_MIN_CONNS" env-default:"2"`
+	MaxConnLifetime time.Duration `yaml:"maxConnLifetime" env:"SSO_PG_MAX_CONN_LIFETIME" env-default:"2h"`
+	MaxConnIdleTime time.Duration `yaml:"maxConnIdleTime" env:"SSO_PG_MAX_CONN_IDLE_TIME" env-default:"15m"`
+
+	// SSLRootCert — CA bundle для verify-ca/verify-full; пусто — системные CA.
+	SSLRootCert string `yaml:"sslRootCert" env:"SSO_PG_SSL_ROOT_CERT"`
+	// SSLCert, SSLKey — клиентский сертификат для mTLS.
+	SSLCert string `yaml:"sslCert" env:"SSO_PG_SSL_CERT"`
+	SSLKey  string `yaml:"sslKey" env:"SSO_PG_SSL_KEY"`
+	// SSLServerName — имя для SNI и verify-full, если отличается от Host.
+	SSLServerName string `yaml:"sslServerName" env:"SSO_PG_SSL_SERVER_NAME"`
+
+	// AutoMigrate применяет миграции при старте (под advisory lock)
+	AutoMigrate bool `yaml:"autoMigrate" env:"SSO_PG_AUTO_MIGRATE" env-default:"false"`
+
+	// Replicas — реплики для чтения ("host" или "host:port"); пусто — всё читается с primary.
+	Replicas []string `yaml:"replicas" env:"SSO_PG_REPLICAS" env-separator:","`
+	// ReplicaMaxLag — отставание, после которого реплика исключается из чтения.
+	ReplicaMaxLag        time.Duration `yaml:"replicaMaxLag" env:"SSO_PG_REPLICA_MAX_LAG" env-default:"10s"`
+	ReplicaCheckInterval time.Duration `yaml:"replicaCheckInterval" env:"SSO_PG_REPLICA_CHECK_INTERVAL" env-default:"5s"`
+
+	// QueryStats включает статистику по запросам sqlc (метрики pgx_query_*)
+	// и лог запросов не быстрее SlowQueryThreshold; порог 0 — без лога.
+	QueryStats         bool          `yaml:"queryStats" env:"SSO_PG_QUERY_STATS" env-default:"true"`
+	SlowQueryThreshold time.Duration `yaml:"slowQueryThreshold" env:"SSO_PG_SLOW_QUERY_THRESHOLD" env-default:"200ms"`
+}
+
+type RedisConfig struct {
+	// Addr — адрес единственного узла; обязателен без Sentinel и Cluster.
+	Addr     string `env:"SSO_REDIS_ADDR"`
+	Database int    `env:"SSO_REDIS_DATABASE" env-default:"0"`
+	// Password — секрет SSO_REDIS_PASSWORD
+	Password secrets.Secret `yaml:"-"`
+
+	// SentinelMaster — имя мастера в Redis Sentinel: адрес мастера
+	// спрашивается у SentinelAddrs и меняется при failover.
+	SentinelMaster string   `yaml:"sentinelMaster" env:"SSO_REDIS_SENTINEL_MASTER"`
+	SentinelAddrs  []string `yaml:"sentinelAddrs" env:"SSO_REDIS_SENTINEL_ADDRS" env-separator:","`
+	// SentinelPassword — необязательный секрет SSO_REDIS_SENTINEL_PASSWORD
+	SentinelPassword secrets.Secret `yaml:"-"`
+	// ClusterAddrs — seed-узлы Redis Cluster; database при этом только 0.
+	ClusterAddrs []string `yaml:"clusterAddrs" env:"SSO_REDIS_CLUSTER_ADDRS" env-separator:","`
+
+	PoolSize        int           `yaml:"poolSize" env:"SSO_REDIS_POOL_SIZE" env-default:"10"`
+	MinIdleConns    int           `yaml:"minIdleConns" env:"SSO_REDIS_MIN_IDLE_CONNS" env-default:"2"`
+	DialTimeout     time.Duration `yaml:"dialTimeout" env:"SSO_REDIS_DIAL_TIMEOUT" env-default:"5s"`
+	ReadTimeout     time.Duration `yaml:"readTimeout" env:"SSO_REDIS_READ_TIMEOUT" env-default:"3s"`
+	WriteTimeout    time.Duration `yaml:"writeTimeout" env:"SSO_REDIS_WRITE_TIMEOUT" env-default:"3s"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime" env:"SSO_REDIS_CONN_MAX_LIFETIME" env-default:"2h"`
+	ConnMaxIdleTime time.Duration `yaml:"connMaxIdleTime" env:"SSO_REDIS_CONN_MAX_IDLE_TIME" env-default:"15m"`
+
+	// TLSMode — disable, require, verify-ca или verify-full (как sslMode Postgres).
+	TLSMode       string `yaml:"tlsMode" env:"SSO_REDIS_TLS_MODE" env-default:"disable"`
+	TLSRootCert   string `yaml:"tlsRootCert" env:"SSO_REDIS_TLS_ROOT_CERT"`
+	TLSCert       string `yaml:"tlsCert" env:"SSO_REDIS_TLS_CERT"`
+	TLSKey        string `yaml:"tlsKey" env:"SSO_REDIS_TLS_KEY"`
+	TLSServerName string `yaml:"tlsServerName" env:"SSO_REDIS_TLS_SERVER_NAME"`
+}
+
+type HTTPConfig struct {
+	Host               string        `yaml:"host" env:"SSO_HTTP_HOST" env-default:"0.0.0.0"`
+	Port               string        `yaml:"port" env:"SSO_HTTP_PORT" env-default:"8080"`
+	ReadTimeout        time.Duration `yaml:"readTimeout" env:"SSO_HTTP_READ_TIMEOUT" env-default:"10s"`
+	WriteTimeout       time.Duration `yaml:"writeTimeout" env:"SSO_HTTP_WRITE_TIMEOUT" env-default:"10s"`
+	MaxHeaderMegabytes int           `yaml:"maxHeaderBytes" env:"SSO_HTTP_MAX_HEADER_BYTES" env-default:"1"`
+}
+
+type GRPCConfig struct {
+	Host               string        `yaml:"host" env:"SSO_GRPC_HOST" env-default:"0.0.0.0"`
+	Port               string        `yaml:"port" env:"SSO_GRPC_PORT" env-default:"44050"`
+	ReadTimeout        time.Duration `yaml:"readTimeout" env:"SSO_GRPC_READ_TIMEOUT" env-default:"10s"`
+	WriteTimeout       time.Duration `yaml:"writeTimeout" env:"SSO_GRPC_WRITE_TIMEOUT" env-default:"10s"`
+	MaxHeaderMegabytes int           `yaml:"maxHeaderBytes" env:"SSO_GRPC_MAX_HEADER_BYTES" env-default:"1"`
+}
+
+type JWTConfig struct {
+	AccessTokenTTL  time.Duration `yaml:"accessTokenTTL" env:"SSO_JWT_ACCESS_TTL" env-default:"15m"`
+	RefreshTokenTTL time.Duration `yaml:"refreshTokenTTL" env:"SSO_JWT_REFRESH_TTL" env-default:"720h"`
+	// PrivateKeyPath используется, если секрета SSO_JWT_PRIVATE_KEY нет
+	PrivateKeyPath string         `yaml:"privateKeyPath" env:"SSO_JWT_PRIVATE_KEY_PATH" env-default:"private.pem"`
+	PrivateKey     secrets.Secret `yaml:"-"`
+}
+
+type CacheConfig struct {
+	ProfileTTL       time.Duration `yaml:"profileTTL" env:"SSO_CACHE_PROFILE_TTL" env-default:"10m"`
+	ProfileTTLJitter time.Duration `yaml:"profileTTLJitter" env:"SSO_CACHE_PROFILE_TTL_JITTER" env-default:"1m"`
+
+	LocalCapacity      int           `yaml:"localCapacity" env:"SSO_CACHE_LOCAL_CAPACITY" env-default:"10000"`
+	LocalProfileTTL    time.Duration `yaml:"localProfileTTL" env:"SSO_CACHE_LOCAL_PROFILE_TTL" env-default:"30s"`
+	LocalRevocationTTL time.Duration `yaml:"localRevocationTTL" env:"SSO_CACHE_LOCAL_REVOCATION_TTL" env-default:"5s"`
+}
+
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled" env:"SSO_TRACING_ENABLED" env-default:"false"`
+	// Endpoint — OTLP/gRPC коллектор, например "otel-collector:4317"
+	Endpoint    string  `yaml:"endpoint" env:"SSO_TRACING_ENDPOINT" env-default:"localhost:4317"`
+	Insecure    bool    `yaml:"insecure" env:"SSO_TRACING_INSECURE" env-default:"true"`
+	SampleRatio float64 `yaml:"sampleRatio" env:"SSO_TRACING_SAMPLE_RATIO" env-default:"0.1"`
+}
+
+// SecretsConfig задаёт, откуда берутся секреты. Порядок поиска:
+// окружение (NAME или NAME_FILE), каталог Dir, зашифрованное хранилище.
+type SecretsConfig struct {
+	// Dir — каталог с файлами секретов (/run/secrets), имя файла = имя секрета
+	Dir string `yaml:"dir" env:"SSO_SECRETS_DIR"`
+	// KeystorePath — хранилище ssoctl keystore; парольная фраза берётся
+	// из SSO_KEYSTORE_PASSPHRASE или SSO_KEYSTORE_PASSPHRASE_FILE
+	KeystorePath string `yaml:"keystorePath" env:"SSO_KEYSTORE_PATH"`
+}
+
+// ResilienceConfig — повторы и автомат для Postgres и Redis (у каждого
+// пула свой автомат).
+type ResilienceConfig struct {
+	Enabled bool `yaml:"enabled" env:"SSO_RESILIENCE_ENABLED" env-default:"true"`
+	// MaxAttempts — попыток вместе с первой
+	MaxAttempts    int           `yaml:"maxAttempts" env:"SSO_RETRY_MAX_ATTEMPTS" env-default:"3"`
+	InitialBackoff time.Duration `yaml:"initialBackoff" env:"SSO_RETRY_INITIAL_BACKOFF" env-default:"50ms"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff" env:"SSO_RETRY_MAX_BACKOFF" env-default:"1s"`
+	// BreakerThreshold — отказов подряд до размыкания; 0 отключает автомат
+	BreakerThreshold int           `yaml:"breakerThreshold" env:"SSO_BREAKER_THRESHOLD" env-default:"5"`
+	BreakerCooldown  time.Duration `yaml:"breakerCooldown" env:"SSO_BREAKER_COOLDOWN" env-default:"10s"`
+}
+
+type HealthConfig struct {
+	// CheckTimeout ограничивает одну проверку зависимости
+	CheckTimeout time.Duration `yaml:"checkTimeout" env:"SSO_HEALTH_CHECK_TIMEOUT" env-default:"2s"`
+	// CacheTTL — сколько переиспользовать результат, чтобы пробы не нагружали БД
+	CacheTTL time.Duration `yaml:"cacheTTL" env:"SSO_HEALTH_CACHE_TTL" env-default:"5s"`
+}
+
+// Level возвращает LogLevel, а если он не задан — уровень окружения.
+// Значение проверено в Validate.
+func (c AppConfig) Level() slog.Level {
+	if c.LogLevel == "" {
+		return logger.DefaultLevel(c.Environment)
+	}
+
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(c.LogLevel))
+	return level
+}
+
+func MustInit(configFile string) *Config {
+	cfg, err := Init(configFile)
+	if err != nil {
+		panic("config: " + err.Error())
+	}
+	return cfg
+}
+
+// Init загружает конфигурацию процесса: .env (если есть), затем Load.
+func Init(configFile string) (*Config, error) {
+	// .env — удобство для локального запуска; в контейнерах переменные
+	// приходят из окружения, и файла может не быть
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("load env file: %w", err)
+	}
+
+	return Load(configFile)
+}
+
+// Load собирает конфигурацию слоями: значения по умолчанию, YAML файл,
+// переменные окружения, затем секреты через SecretProvider. Неизвестные
+// ключи в файле и семантически неверные значения — ошибка.
+func Load(configFile string) (*Config, error) {
+	var cfg Config
+
+	if err := applyDefaults(&cfg); err != nil {
+		return nil, fmt.Errorf("apply defaults: %w", err)
+	}
+
+	if err := decodeFile(configFile, &cfg); err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return nil, fmt.Errorf("read env: %w", err)
+	}
+
+	ctx := context.Background()
+
+	provider, err := cfg.Secrets.Provider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.loadSecrets(ctx, provider); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+func (c *Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("env", c.App.Environment),
+		slog.String("log_level", c.App.Level().String()),
+		slog.Duration("reload_interval", c.App.ReloadInterval),
+		slog.Duration("shutdown_timeout", c.App.ShutdownTimeout),
+		slog.Duration("shutdown_delay", c.App.ShutdownDelay),
+
+		slog.Group("http",
+			slog.String("address", c.HTTP.Host+":"+c.HTTP.Port),
+			slog.Duration("read_timeout", c.HTTP.ReadTimeout),
+			slog.Duration("write_timeout", c.HTTP.WriteTimeout),
+			slog.Int("max_header_megabytes", c.HTTP.MaxHeaderMegabytes),
+		),
+
+		slog.Group("grpc",
+			slog.String("address", c.GRPC.Host+":"+c.GRPC.Port),
+			slog.Duration("read_timeout", c.GRPC.ReadTimeout),
+			slog.Duration("write_timeout", c.GRPC.WriteTimeout),
+			slog.Int("max_header_megabytes", c.GRPC.MaxHeaderMegabytes),
+		),
+
+		slog.Group("jwt",
+			slog.Duration("access_token_ttl", c.JWT.AccessTokenTTL),
+			slog.Duration("refresh_token_ttl", c.JWT.RefreshTokenTTL),
+			slog.String("private_key_path", c.JWT.PrivateKeyPath),
+		),
+
+		slog.Group("postgres",
+			slog.String("address", c.PG.Host+":"+c.PG.Port),
+			slog.String("database", c.PG.DBName),
+			slog.String("user", c.PG.User),
+			slog.String("ssl_mode", c.PG.SSLMode),
+			slog.String("ssl_root_cert", c.PG.SSLRootCert),
+			slog.String("ssl_cert", c.PG.SSLCert),
+			slog.Duration("connect_timeout", c.PG.ConnectTimeout),
+			slog.Int("max_conns", c.PG.MaxConns),
+			slog.Int("min_conns", c.PG.MinConns),
+			slog.Duration("max_conn_lifetime", c.PG.MaxConnLifetime),
+			slog.Duration("max_conn_idle_time", c.PG.MaxConnIdleTime),
+			slog.Bool("auto_migrate", c.PG.AutoMigrate),
+			slog.Any("replicas", c.PG.Replicas),
+			slog.Duration("replica_max_lag", c.PG.ReplicaMaxLag),
+			slog.Bool("query_stats", c.PG.QueryStats),
+			slog.Duration("slow_query_threshold", c.PG.SlowQueryThreshold),
+		),
+
+		slog.Group("redis",
+			slog.String("address", c.Redis.Addr),
+			slog.String("sentinel_master", c.Redis.SentinelMaster),
+			slog.Any("sentinel_addrs", c.Redis.SentinelAddrs),
+			slog.Any("cluster_addrs", c.Redis.ClusterAddrs),
+			slog.Int("database", c.Redis.Database),
+			slog.Int("pool_size", c.Redis.PoolSize),
+			slog.Int("min_idle_conns", c.Redis.MinIdleConns),
+			slog.Duration("dial_timeout", c.Redis.DialTimeout),
+			slog.Duration("read_timeout", c.Redis.ReadTimeout),
+			slog.Duration("write_timeout", c.Redis.WriteTimeout),
+			slog.String("tls_mode", c.Redis.TLSMode),
+			slog.String("tls_root_cert", c.Redis.TLSRootCert),
+			slog.String("tls_cert", c.Redis.TLSCert),
+		),
+
+		slog.Group("cache",
+			slog.Duration("profile_ttl", c.Cache.ProfileTTL),
+			slog.Duration("profile_ttl_jitter", c.Cache.ProfileTTLJitter),
+			slog.Int("local_capacity", c.Cache.LocalCapacity),
+			slog.Duration("local_profile_ttl", c.Cache.LocalProfileTTL),
+			slog.Duration("local_revocation_ttl", c.Cache.LocalRevocationTTL),
+		),
+
+		slog.Group("tracing",
+			slog.Bool("enabled", c.Tracing.Enabled),
+			slog.String("endpoint", c.Tracing.Endpoint),
+			slog.Float64("sample_ratio", c.Tracing.SampleRatio),
+		),
+
+		slog.Group("secrets",
+			slog.String("dir", c.Secrets.Dir),
+			slog.String("keystore_path", c.Secrets.KeystorePath),
+		),
+
+		slog.Group("health",
+			slog.Duration("check_timeout", c.Health.CheckTimeout),
+			slog.Duration("cache_ttl", c.Health.CacheTTL),
+		),
+
+		slog.Group("resilience",
+			slog.Bool("enabled", c.Resilience.Enabled),
+			slog.Int("max_attempts", c.Resilience.MaxAttempts),
+			slog.Duration("initial_backoff", c.Resilience.InitialBackoff),
+			slog.Duration("max_backoff", c.Resilience.MaxBackoff),
+			slog.Int("breaker_threshold", c.Resilience.BreakerThreshold),
+			slog.Duration("breaker_cooldown", c.Resilience.BreakerCooldown),
+		),
+
+		slog.Group("rate_limit",
+			slog.Int("default_count", c.RateLimit.Default.Count),
+			slog.Duration("default_window", c.RateLimit.Default.Window),
+			slog.String("fail_policy", string(c.RateLimit.FailPolicy)),
+			slog.Int("methods", len(c.RateLimit.Methods)),
+		),
+	)
+}
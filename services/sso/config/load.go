@@ -0,0 +1,147 @@
+package ssoconfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"gopkg.in/yaml.v3"
+)
+
+// decodeFile строго разбирает YAML: неизвестный ключ — ошибка, а не тихо
+// проигнорированная опечатка.
+func decodeFile(path string, cfg *Config) error {
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+	default:
+		return fmt.Errorf("unsupported config format %q, want .yaml or .yml", ext)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+
+	// пустой файл допустим: всё придёт из окружения и значений по умолчанию
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// Теги cleanenv, по которым собирается конфигурация.
+const (
+	tagEnv       = "env"
+	tagDefault   = "env-default"
+	tagRequired  = "env-required"
+	tagPrefix    = "env-prefix"
+	tagSeparator = "env-separator"
+	tagLayout    = "env-layout"
+)
+
+// envField — лист конфигурации: поле, которое cleanenv заполняет целиком.
+type envField struct {
+	value reflect.Value
+	field reflect.StructField
+	// envs — имена переменных с учётом env-prefix вложенных структур
+	envs []string
+}
+
+// envFields обходит cfg так же, как cleanenv: вложенные структуры
+// раскрываются с накоплением env-prefix, time.Time и url.URL — листья.
+func envFields(v reflect.Value, prefix string) []envField {
+	var out []envField
+	t := v.Type()
+	for i := range t.NumField() {
+		sf, fv := t.Field(i), v.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if fv.Kind() == reflect.Struct && fv.Type() != timeType && fv.Type() != urlType {
+			out = append(out, envFields(fv, prefix+sf.Tag.Get(tagPrefix))...)
+			continue
+		}
+
+		f := envField{value: fv, field: sf}
+		if env := sf.Tag.Get(tagEnv); env != "" {
+			for _, name := range strings.Split(env, ",") {
+				f.envs = append(f.envs, prefix+name)
+			}
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+	urlType  = reflect.TypeFor[url.URL]()
+)
+
+// applyDefaults заполняет поля значениями env-default. Вызывается до
+// разбора файла: так явный ноль или false из YAML не затирается
+// значением по умолчанию, как при cleanenv.ReadEnv после файла.
+func applyDefaults(cfg *Config) error {
+	for _, f := range envFields(reflect.ValueOf(cfg).Elem(), "") {
+		def, ok := f.field.Tag.Lookup(tagDefault)
+		if !ok {
+			continue
+		}
+		if err := f.parse(tagDefault + ":" + strconv.Quote(def)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyEnv переносит заданные переменные окружения поверх файла и
+// проверяет env-required: обязательное поле может прийти и из файла.
+func applyEnv(cfg *Config) error {
+	for _, f := range envFields(reflect.ValueOf(cfg).Elem(), "") {
+		for _, name := range f.envs {
+			if _, ok := os.LookupEnv(name); !ok {
+				continue
+			}
+			if err := f.parse(tagEnv + ":" + strconv.Quote(name)); err != nil {
+				return err
+			}
+			break
+		}
+
+		if _, required := f.field.Tag.Lookup(tagRequired); required && f.value.IsZero() {
+			return fmt.Errorf("field %q is required but the value is not provided", f.field.Name)
+		}
+	}
+	return nil
+}
+
+// parse разбирает значение поля средствами cleanenv: временная структура
+// из одного поля того же типа получает tag и разделители исходного поля.
+func (f envField) parse(tag string) error {
+	for _, key := range []string{tagSeparator, tagLayout} {
+		if v, ok := f.field.Tag.Lookup(key); ok {
+			tag += " " + key + ":" + strconv.Quote(v)
+		}
+	}
+
+	tmp := reflect.New(reflect.StructOf([]reflect.StructField{
+		{Name: "Value", Type: f.value.Type(), Tag: reflect.StructTag(tag)},
+	}))
+	if err := cleanenv.ReadEnv(tmp.Interface()); err != nil {
+		return fmt.Errorf("field %q: %w", f.field.Name, err)
+	}
+	f.value.Set(tmp.Elem().Field(0))
+	return nil
+}
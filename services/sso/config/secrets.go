@@ -0,0 +1,86 @@
+package ssoconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/secrets"
+)
+
+// Имена секретов, под которыми их ищут провайдеры.
+const (
+	SecretAppKey                = "SSO_APP_SECRET"
+	SecretPostgresPassword      = "SSO_POSTGRES_PASSWORD"
+	SecretRedisPassword         = "SSO_REDIS_PASSWORD"
+	SecretRedisSentinelPassword = "SSO_REDIS_SENTINEL_PASSWORD"
+	SecretJWTPrivateKey         = "SSO_JWT_PRIVATE_KEY"
+	SecretKeystorePassphrase    = "SSO_KEYSTORE_PASSPHRASE"
+)
+
+// Provider собирает цепочку источников секретов: окружение, каталог Dir,
+// хранилище KeystorePath.
+func (c SecretsConfig) Provider(ctx context.Context) (secrets.Provider, error) {
+	env := secrets.NewEnvProvider("")
+	chain := secrets.Chain{env}
+
+	if c.Dir != "" {
+		chain = append(chain, secrets.NewFileProvider(c.Dir))
+	}
+
+	if c.KeystorePath != "" {
+		passphrase, err := env.Secret(ctx, SecretKeystorePassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("keystore passphrase: %w", err)
+		}
+
+		ks, err := secrets.OpenKeystore(c.KeystorePath, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, ks)
+	}
+
+	return chain, nil
+}
+
+// loadSecrets заполняет секретные поля конфигурации.
+func (c *Config) loadSecrets(ctx context.Context, provider secrets.Provider) error {
+	required := []struct {
+		name string
+		dst  *secrets.Secret
+	}{
+		{SecretAppKey, &c.App.AppSecretKey},
+		{SecretPostgresPassword, &c.PG.Password},
+		{SecretRedisPassword, &c.Redis.Password},
+	}
+
+	for _, ref := range required {
+		s, err := provider.Secret(ctx, ref.name)
+		if err != nil {
+			return fmt.Errorf("secret %s: %w", ref.name, err)
+		}
+		*ref.dst = s
+	}
+
+	// пароль sentinel-узлов необязателен: часто они без него
+	sentinel, err := provider.Secret(ctx, SecretRedisSentinelPassword)
+	if err != nil && !errors.Is(err, secrets.ErrNotFound) {
+		return fmt.Errorf("secret %s: %w", SecretRedisSentinelPassword, err)
+	}
+	c.Redis.SentinelPassword = sentinel
+
+	// ключ можно положить в хранилище, иначе читаем файл privateKeyPath
+	key, err := provider.Secret(ctx, SecretJWTPrivateKey)
+	if errors.Is(err, secrets.ErrNotFound) {
+		key, err = secrets.NewFileProvider(filepath.Dir(c.JWT.PrivateKeyPath)).
+			Secret(ctx, filepath.Base(c.JWT.PrivateKeyPath))
+	}
+	if err != nil {
+		return fmt.Errorf("jwt private key: %w", err)
+	}
+	c.JWT.PrivateKey = key
+
+	return nil
+}
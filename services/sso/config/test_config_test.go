@@ -0,0 +1,436 @@
+package ssoconfig
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/secrets"
+)
+
+const testYAML = `
+app:
+  logLevel: warn
+jwt:
+  privateKeyPath: %KEY%
+rateLimit:
+  default:
+    count: 100
+    window: 1m
+`
+
+// setupEnv выставляет обязательные переменные и пишет конфигурацию во временный каталог.
+func setupEnv(t *testing.T, yaml string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	key := filepath.Join(dir, "private.pem")
+	if err := os.WriteFile(key, []byte("key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "sso.yaml")
+	if err := os.WriteFile(path, []byte(strings.ReplaceAll(yaml, "%KEY%", key)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SSO_APP_SECRET", strings.Repeat("s", minAppSecretLen))
+	t.Setenv("SSO_POSTGRES_HOST", "localhost")
+	t.Setenv("SSO_POSTGRES_USER", "postgres")
+	t.Setenv("SSO_POSTGRES_PASSWORD", "password")
+	t.Setenv("SSO_POSTGRES_DB", "sso")
+	t.Setenv("SSO_REDIS_ADDR", "localhost:6379")
+	t.Setenv("SSO_REDIS_PASSWORD", "password")
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			yaml: testYAML,
+		},
+		{
+			name:    "unknown key",
+			yaml:    testYAML + "auth:\n  accessTokenTTL: 15m\n",
+			wantErr: "field auth not found",
+		},
+		{
+			name:    "invalid value",
+			yaml:    testYAML,
+			env:     map[string]string{"SSO_PG_MIN_CONNS": "20"},
+			wantErr: "postgres.minConns",
+		},
+		{
+			name:    "invalid log level",
+			yaml:    testYAML,
+			env:     map[string]string{"SSO_LOG_LEVEL": "loud"},
+			wantErr: "app.logLevel",
+		},
+		{
+			name:    "short secret",
+			yaml:    testYAML,
+			env:     map[string]string{"SSO_APP_SECRET": "short"},
+			wantErr: "app.secret",
+		},
+		{
+			name:    "client cert without key",
+			yaml:    testYAML,
+			env:     map[string]string{"SSO_PG_SSL_MODE": "verify-full", "SSO_PG_SSL_CERT": "client.crt"},
+			wantErr: "postgres.sslCert",
+		},
+		{
+			name:    "redis prefer tls",
+			yaml:    testYAML,
+			env:     map[string]string{"SSO_REDIS_TLS_MODE": "prefer"},
+			wantErr: "redis.tlsMode",
+		},
+		{
+			name: "redis sentinel",
+			yaml: testYAML,
+			env: map[string]string{
+				"SSO_REDIS_ADDR":            "",
+				"SSO_REDIS_SENTINEL_MASTER": "mymaster",
+				"SSO_REDIS_SENTINEL_ADDRS":  "sentinel-1:26379,sentinel-2:26379",
+			},
+		},
+		{
+			name:    "redis sentinel without addrs",
+			yaml:    testYAML,
+			env:     map[string]string{"SSO_REDIS_SENTINEL_MASTER": "mymaster"},
+			wantErr: "redis.sentinelAddrs",
+		},
+		{
+			name: "redis sentinel and cluster",
+			yaml: testYAML,
+			env: map[string]string{
+				"SSO_REDIS_SENTINEL_MASTER": "mymaster",
+				"SSO_REDIS_SENTINEL_ADDRS":  "sentinel-1:26379",
+				"SSO_REDIS_CLUSTER_ADDRS":   "node-1:6379",
+			},
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:    "redis cluster with database",
+			yaml:    testYAML,
+			env:     map[string]string{"SSO_REDIS_CLUSTER_ADDRS": "node-1:6379,node-2:6379", "SSO_REDIS_DATABASE": "1"},
+			wantErr: "redis.database",
+		},
+		{
+			name:    "redis without addr",
+			yaml:    testYAML,
+			env:     map[string]string{"SSO_REDIS_ADDR": ""},
+			wantErr: "redis.addr",
+		},
+		{
+			name:    "negative slow query threshold",
+			yaml:    testYAML,
+			env:     map[string]string{"SSO_PG_SLOW_QUERY_THRESHOLD": "-1s"},
+			wantErr: "postgres.slowQueryThreshold",
+		},
+		{
+			name:    "shutdown delay exceeds timeout",
+			yaml:    testYAML,
+			env:     map[string]string{"SSO_SHUTDOWN_DELAY": "15s"},
+			wantErr: "app.shutdownDelay",
+		},
+		{
+			name:    "zero retry attempts",
+			yaml:    testYAML,
+			env:     map[string]string{"SSO_RETRY_MAX_ATTEMPTS": "0"},
+			wantErr: "resilience.maxAttempts",
+		},
+		{
+			name: "resilience disabled",
+			yaml: testYAML,
+			env:  map[string]string{"SSO_RESILIENCE_ENABLED": "false", "SSO_RETRY_MAX_ATTEMPTS": "0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := setupEnv(t, tt.yaml)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			_, err := Load(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("got error %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadLayers(t *testing.T) {
+	path := setupEnv(t, testYAML)
+	t.Setenv("SSO_PG_MAX_CONNS", "42")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// default
+	if cfg.Redis.DialTimeout.String() != "5s" {
+		t.Errorf("got dial timeout %v, want 5s", cfg.Redis.DialTimeout)
+	}
+	// file
+	if cfg.App.Level() != slog.LevelWarn {
+		t.Errorf("got level %v, want %v", cfg.App.Level(), slog.LevelWarn)
+	}
+	// env
+	if cfg.PG.MaxConns != 42 {
+		t.Errorf("got max conns %d, want 42", cfg.PG.MaxConns)
+	}
+}
+
+// Явный ноль или false в файле — значение, а не «не задано»: значение по
+// умолчанию его не перекрывает, а переменная окружения перекрывает.
+func TestLoadExplicitZero(t *testing.T) {
+	tests := []struct {
+		name  string
+		yaml  string
+		env   map[string]string
+		unset []string
+		check func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "tracing",
+			yaml: testYAML + "tracing:\n  insecure: false\n  sampleRatio: 0\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Tracing.Insecure {
+					t.Errorf("got insecure %v, want false", cfg.Tracing.Insecure)
+				}
+				if cfg.Tracing.SampleRatio != 0 {
+					t.Errorf("got sample ratio %v, want 0", cfg.Tracing.SampleRatio)
+				}
+			},
+		},
+		{
+			name: "resilience",
+			yaml: testYAML + "resilience:\n  enabled: false\n  breakerThreshold: 0\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Resilience.Enabled {
+					t.Errorf("got enabled %v, want false", cfg.Resilience.Enabled)
+				}
+				if cfg.Resilience.BreakerThreshold != 0 {
+					t.Errorf("got breaker threshold %d, want 0", cfg.Resilience.BreakerThreshold)
+				}
+			},
+		},
+		{
+			name: "query stats",
+			yaml: testYAML + "postgres:\n  queryStats: false\n  slowQueryThreshold: 0s\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.PG.QueryStats {
+					t.Errorf("got query stats %v, want false", cfg.PG.QueryStats)
+				}
+				if cfg.PG.SlowQueryThreshold != 0 {
+					t.Errorf("got slow query threshold %v, want 0", cfg.PG.SlowQueryThreshold)
+				}
+			},
+		},
+		{
+			name: "log sampling",
+			yaml: strings.Replace(testYAML, "logLevel: warn", "logLevel: warn\n  logSampleInterval: 0s", 1),
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.App.LogSampleInterval != 0 {
+					t.Errorf("got log sample interval %v, want 0", cfg.App.LogSampleInterval)
+				}
+			},
+		},
+		{
+			name: "env over file",
+			yaml: testYAML + "tracing:\n  sampleRatio: 0\n",
+			env:  map[string]string{"SSO_TRACING_SAMPLE_RATIO": "0.5"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Tracing.SampleRatio != 0.5 {
+					t.Errorf("got sample ratio %v, want 0.5", cfg.Tracing.SampleRatio)
+				}
+			},
+		},
+		{
+			name:  "required from file",
+			yaml:  testYAML + "postgres:\n  host: db.internal\n",
+			unset: []string{"SSO_POSTGRES_HOST"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.PG.Host != "db.internal" {
+					t.Errorf("got host %q, want db.internal", cfg.PG.Host)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := setupEnv(t, tt.yaml)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			for _, k := range tt.unset {
+				unsetenv(t, k)
+			}
+
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestSecretFile(t *testing.T) {
+	path := setupEnv(t, testYAML)
+
+	secret := filepath.Join(t.TempDir(), "pg-password")
+	if err := os.WriteFile(secret, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SSO_POSTGRES_PASSWORD_FILE", secret)
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "both") {
+		t.Fatalf("got error %v, want conflict of SSO_POSTGRES_PASSWORD and _FILE", err)
+	}
+
+	unsetenv(t, "SSO_POSTGRES_PASSWORD")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PG.Password.Reveal() != "from-file" {
+		t.Errorf("got password %q, want %q", cfg.PG.Password.Reveal(), "from-file")
+	}
+
+	// ротация секрета подхватывается при перечитывании
+	if err := os.WriteFile(secret, []byte("rotated"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PG.Password.Reveal() != "rotated" {
+		t.Errorf("got password %q, want %q", cfg.PG.Password.Reveal(), "rotated")
+	}
+}
+
+func TestKeystoreSecrets(t *testing.T) {
+	path := setupEnv(t, testYAML)
+	unsetenv(t, "SSO_REDIS_PASSWORD")
+
+	ksPath := filepath.Join(t.TempDir(), "sso.keystore")
+	ks, err := secrets.NewKeystore(ksPath, secrets.NewString("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Set(SecretRedisPassword, secrets.NewString("from-keystore")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Set(SecretJWTPrivateKey, secrets.NewString("pem")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SSO_KEYSTORE_PATH", ksPath)
+	t.Setenv("SSO_KEYSTORE_PASSPHRASE", "wrong")
+	if _, err := Load(path); !errors.Is(err, secrets.ErrWrongPassphrase) {
+		t.Fatalf("got error %v, want %v", err, secrets.ErrWrongPassphrase)
+	}
+
+	t.Setenv("SSO_KEYSTORE_PASSPHRASE", "passphrase")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Redis.Password.Reveal() != "from-keystore" {
+		t.Errorf("got redis password %q, want %q", cfg.Redis.Password.Reveal(), "from-keystore")
+	}
+	// ключ из хранилища важнее файла privateKeyPath
+	if cfg.JWT.PrivateKey.Reveal() != "pem" {
+		t.Errorf("got private key %q, want %q", cfg.JWT.PrivateKey.Reveal(), "pem")
+	}
+	// окружение важнее хранилища
+	if cfg.PG.Password.Reveal() != "password" {
+		t.Errorf("got postgres password %q, want %q", cfg.PG.Password.Reveal(), "password")
+	}
+}
+
+// unsetenv удаляет переменную до конца теста и восстанавливает её после.
+func unsetenv(t *testing.T, key string) {
+	t.Helper()
+
+	t.Setenv(key, "")
+	if err := os.Unsetenv(key); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloader(t *testing.T) {
+	path := setupEnv(t, testYAML)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewReloader(path, cfg, nil)
+
+	var levels []slog.Level
+	r.Subscribe("failing", func(*Config) error {
+		return errors.New("boom")
+	})
+	r.Subscribe("level", func(cfg *Config) error {
+		levels = append(levels, cfg.App.Level())
+		return nil
+	})
+
+	updated := strings.Replace(testYAML, "logLevel: warn", "logLevel: error", 1)
+	if err := os.WriteFile(path, []byte(strings.ReplaceAll(updated, "%KEY%", cfg.JWT.PrivateKeyPath)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// ошибка одного подписчика не мешает остальным
+	if len(levels) != 1 || levels[0] != slog.LevelError {
+		t.Errorf("got levels %v, want [%v]", levels, slog.LevelError)
+	}
+	if r.Current().App.LogLevel != "error" {
+		t.Errorf("got current log level %q, want %q", r.Current().App.LogLevel, "error")
+	}
+
+	// невалидный файл не доходит до подписчиков
+	if err := os.WriteFile(path, []byte("unknown: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); err == nil {
+		t.Fatal("got nil error for invalid file")
+	}
+	if len(levels) != 1 {
+		t.Errorf("got %d notifications, want 1", len(levels))
+	}
+	if r.Current().App.LogLevel != "error" {
+		t.Errorf("got current log level %q, want previous %q", r.Current().App.LogLevel, "error")
+	}
+}
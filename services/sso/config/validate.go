@@ -0,0 +1,225 @@
+package ssoconfig
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
+)
+
+// minAppSecretLen — секрет короче 32 байт слишком слаб для HMAC.
+const minAppSecretLen = 32
+
+var sslModes = map[string]bool{
+	"disable":     true,
+	"allow":       true,
+	"prefer":      true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+// redisTLSModes — как sslModes, но без allow и prefer: Redis не
+// договаривается о TLS, порт либо с ним, либо без.
+var redisTLSModes = map[string]bool{
+	"disable":     true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+// Validate проверяет значения по смыслу (порты, таймауты, согласованность
+// лимитов) и возвращает все найденные ошибки разом.
+func (c *Config) Validate() error {
+	var v validator
+
+	switch c.App.Environment {
+	case logger.EnvLocal, logger.EnvDev, logger.EnvProd:
+	default:
+		v.addf("app.environment: unknown value %q, want %s, %s or %s",
+			c.App.Environment, logger.EnvLocal, logger.EnvDev, logger.EnvProd)
+	}
+	if c.App.AppSecretKey.Len() < minAppSecretLen {
+		v.addf("app.secret: must be at least %d characters", minAppSecretLen)
+	}
+	if c.App.LogLevel != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(c.App.LogLevel)); err != nil {
+			v.addf("app.logLevel: %v", err)
+		}
+	}
+	if c.App.LogSampleInterval < 0 || c.App.LogSampleBurst < 0 {
+		v.addf("app.logSampleInterval, app.logSampleBurst: must not be negative")
+	}
+	if c.App.LogBufferSize < 0 {
+		v.addf("app.logBufferSize: must not be negative, got %d", c.App.LogBufferSize)
+	}
+	v.positive("app.reloadInterval", c.App.ReloadInterval)
+	v.positive("app.shutdownTimeout", c.App.ShutdownTimeout)
+	if c.App.ShutdownDelay < 0 || c.App.ShutdownDelay >= c.App.ShutdownTimeout {
+		v.addf("app.shutdownDelay: must be non-negative and less than app.shutdownTimeout, got %v", c.App.ShutdownDelay)
+	}
+
+	v.port("http.port", c.HTTP.Port)
+	v.positive("http.readTimeout", c.HTTP.ReadTimeout)
+	v.positive("http.writeTimeout", c.HTTP.WriteTimeout)
+	v.positiveInt("http.maxHeaderBytes", c.HTTP.MaxHeaderMegabytes)
+
+	v.port("grpc.port", c.GRPC.Port)
+	v.positive("grpc.readTimeout", c.GRPC.ReadTimeout)
+	v.positive("grpc.writeTimeout", c.GRPC.WriteTimeout)
+	v.positiveInt("grpc.maxHeaderBytes", c.GRPC.MaxHeaderMegabytes)
+
+	if c.HTTP.Port == c.GRPC.Port && c.HTTP.Host == c.GRPC.Host {
+		v.addf("http.port and grpc.port: both listen on %s:%s", c.HTTP.Host, c.HTTP.Port)
+	}
+
+	v.port("postgres.port", c.PG.Port)
+	if !sslModes[c.PG.SSLMode] {
+		v.addf("postgres.sslMode: unknown value %q", c.PG.SSLMode)
+	}
+	v.certPair("postgres.sslCert", c.PG.SSLCert, c.PG.SSLKey)
+	v.positive("postgres.connectTimeout", c.PG.ConnectTimeout)
+	v.positiveInt("postgres.maxConns", c.PG.MaxConns)
+	if c.PG.MinConns < 0 || c.PG.MinConns > c.PG.MaxConns {
+		v.addf("postgres.minConns: must be in [0, maxConns], got %d", c.PG.MinConns)
+	}
+	v.positive("postgres.maxConnLifetime", c.PG.MaxConnLifetime)
+	v.positive("postgres.maxConnIdleTime", c.PG.MaxConnIdleTime)
+	v.nonEmpty("postgres.replicas", c.PG.Replicas)
+	v.positive("postgres.replicaMaxLag", c.PG.ReplicaMaxLag)
+	v.positive("postgres.replicaCheckInterval", c.PG.ReplicaCheckInterval)
+	if c.PG.SlowQueryThreshold < 0 {
+		v.addf("postgres.slowQueryThreshold: must not be negative, got %s", c.PG.SlowQueryThreshold)
+	}
+
+	sentinel := c.Redis.SentinelMaster != "" || len(c.Redis.SentinelAddrs) > 0
+	cluster := len(c.Redis.ClusterAddrs) > 0
+	switch {
+	case sentinel && cluster:
+		v.addf("redis.sentinelMaster and redis.clusterAddrs: mutually exclusive")
+	case sentinel && c.Redis.SentinelMaster == "":
+		v.addf("redis.sentinelMaster: required with sentinelAddrs")
+	case sentinel && len(c.Redis.SentinelAddrs) == 0:
+		v.addf("redis.sentinelAddrs: required with sentinelMaster")
+	case cluster && c.Redis.Database != 0:
+		v.addf("redis.database: must be 0 in cluster mode, got %d", c.Redis.Database)
+	case !sentinel && !cluster && c.Redis.Addr == "":
+		v.addf("redis.addr: required without sentinel or cluster")
+	}
+	v.nonEmpty("redis.sentinelAddrs", c.Redis.SentinelAddrs)
+	v.nonEmpty("redis.clusterAddrs", c.Redis.ClusterAddrs)
+	if c.Redis.Database < 0 {
+		v.addf("redis.database: must not be negative, got %d", c.Redis.Database)
+	}
+	v.positiveInt("redis.poolSize", c.Redis.PoolSize)
+	if c.Redis.MinIdleConns < 0 || c.Redis.MinIdleConns > c.Redis.PoolSize {
+		v.addf("redis.minIdleConns: must be in [0, poolSize], got %d", c.Redis.MinIdleConns)
+	}
+	v.positive("redis.dialTimeout", c.Redis.DialTimeout)
+	v.positive("redis.readTimeout", c.Redis.ReadTimeout)
+	v.positive("redis.writeTimeout", c.Redis.WriteTimeout)
+	v.positive("redis.connMaxLifetime", c.Redis.ConnMaxLifetime)
+	v.positive("redis.connMaxIdleTime", c.Redis.ConnMaxIdleTime)
+	if !redisTLSModes[c.Redis.TLSMode] {
+		v.addf("redis.tlsMode: unknown value %q", c.Redis.TLSMode)
+	}
+	v.certPair("redis.tlsCert", c.Redis.TLSCert, c.Redis.TLSKey)
+
+	v.positive("jwt.accessTokenTTL", c.JWT.AccessTokenTTL)
+	v.positive("jwt.refreshTokenTTL", c.JWT.RefreshTokenTTL)
+	if c.JWT.AccessTokenTTL >= c.JWT.RefreshTokenTTL {
+		v.addf("jwt.accessTokenTTL: must be shorter than refreshTokenTTL")
+	}
+	if c.JWT.PrivateKeyPath == "" {
+		v.addf("jwt.privateKeyPath: must not be empty")
+	}
+
+	v.positive("cache.profileTTL", c.Cache.ProfileTTL)
+	if c.Cache.ProfileTTLJitter < 0 || c.Cache.ProfileTTLJitter >= c.Cache.ProfileTTL {
+		v.addf("cache.profileTTLJitter: must be in [0, profileTTL)")
+	}
+	v.positiveInt("cache.localCapacity", c.Cache.LocalCapacity)
+	v.positive("cache.localProfileTTL", c.Cache.LocalProfileTTL)
+	v.positive("cache.localRevocationTTL", c.Cache.LocalRevocationTTL)
+
+	if c.Tracing.Enabled && c.Tracing.Endpoint == "" {
+		v.addf("tracing.endpoint: required when tracing is enabled")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.addf("tracing.sampleRatio: must be in [0, 1], got %v", c.Tracing.SampleRatio)
+	}
+
+	if c.Resilience.Enabled {
+		v.positiveInt("resilience.maxAttempts", c.Resilience.MaxAttempts)
+		v.positive("resilience.initialBackoff", c.Resilience.InitialBackoff)
+		if c.Resilience.MaxBackoff < c.Resilience.InitialBackoff {
+			v.addf("resilience.maxBackoff: must be >= initialBackoff")
+		}
+		if c.Resilience.BreakerThreshold < 0 {
+			v.addf("resilience.breakerThreshold: must not be negative, got %d", c.Resilience.BreakerThreshold)
+		}
+		if c.Resilience.BreakerThreshold > 0 {
+			v.positive("resilience.breakerCooldown", c.Resilience.BreakerCooldown)
+		}
+	}
+
+	v.positive("health.checkTimeout", c.Health.CheckTimeout)
+	v.positive("health.cacheTTL", c.Health.CacheTTL)
+
+	if err := c.RateLimit.Validate(); err != nil {
+		v.addf("rateLimit: %w", err)
+	}
+
+	return v.err()
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) addf(format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) positive(field string, d time.Duration) {
+	if d <= 0 {
+		v.addf("%s: must be positive, got %s", field, d)
+	}
+}
+
+func (v *validator) positiveInt(field string, n int) {
+	if n <= 0 {
+		v.addf("%s: must be positive, got %d", field, n)
+	}
+}
+
+func (v *validator) port(field, port string) {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		v.addf("%s: invalid port %q", field, port)
+	}
+}
+
+// certPair требует сертификат и ключ вместе: одно без другого для mTLS бесполезно.
+func (v *validator) certPair(field, cert, key string) {
+	if (cert == "") != (key == "") {
+		v.addf("%s: certificate and key must be set together", field)
+	}
+}
+
+// nonEmpty проверяет, что в списке адресов нет пустых строк (",," в env).
+func (v *validator) nonEmpty(field string, addrs []string) {
+	for i, a := range addrs {
+		if a == "" {
+			v.addf("%s[%d]: must not be empty", field, i)
+		}
+	}
+}
+
+func (v *validator) err() error {
+	return errors.Join(v.errs...)
+}
@@ -0,0 +1,140 @@
+package ssoconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reloader перечитывает файл конфигурации при изменении и передаёт новую
+// версию подписчикам (уровень логов, лимиты и т.п.). Каждый подписчик сам
+// решает, какие поля применить на лету; остальные изменения вступают в силу
+// после перезапуска.
+type Reloader struct {
+	path string
+	log  *slog.Logger
+
+	mu          sync.Mutex
+	current     *Config
+	subscribers []subscriber
+}
+
+type subscriber struct {
+	name string
+	fn   func(cfg *Config) error
+}
+
+// NewReloader создаёт Reloader для уже загруженной конфигурации current.
+func NewReloader(path string, current *Config, log *slog.Logger) *Reloader {
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return &Reloader{
+		path:    path,
+		log:     log.With(slog.String("op", "ssoconfig.Reloader")),
+		current: current,
+	}
+}
+
+// Subscribe регистрирует fn, вызываемую после каждой успешной перезагрузки.
+// Подписчики вызываются по очереди в порядке регистрации; ошибка одного
+// логируется и не мешает остальным.
+func (r *Reloader) Subscribe(name string, fn func(cfg *Config) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscribers = append(r.subscribers, subscriber{name: name, fn: fn})
+}
+
+// Current возвращает последнюю успешно загруженную конфигурацию.
+func (r *Reloader) Current() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.current
+}
+
+// Reload загружает файл и уведомляет подписчиков. Невалидный файл — ошибка,
+// подписчики не вызываются, текущая конфигурация сохраняется.
+func (r *Reloader) Reload() error {
+	cfg, err := Load(r.path)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.current = cfg
+	for _, s := range r.subscribers {
+		if err := s.fn(cfg); err != nil {
+			r.log.Error("failed apply reloaded config",
+				slog.String("subscriber", s.name),
+				slog.String("error", err.Error()))
+		}
+	}
+	return nil
+}
+
+// Run следит за файлом с интервалом App.ReloadInterval текущей конфигурации.
+// Блокируется до отмены ctx.
+func (r *Reloader) Run(ctx context.Context) {
+	WatchFile(ctx, r.path, r.Current().App.ReloadInterval, r.Reload, r.log)
+}
+
+// WatchFile опрашивает файл раз в interval и вызывает onChange, когда меняется
+// его содержимое. Сравнивается хэш, а не mtime: так корректно отрабатывает
+// подмена symlink при обновлении ConfigMap в Kubernetes.
+// Блокируется до отмены ctx.
+func WatchFile(ctx context.Context, path string, interval time.Duration, onChange func() error, log *slog.Logger) {
+	if log == nil {
+		log = slog.Default()
+	}
+	log = log.With(slog.String("op", "ssoconfig.WatchFile"), slog.String("path", path))
+
+	last, err := fileHash(path)
+	if err != nil {
+		log.Warn("failed read config file", slog.String("error", err.Error()))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		sum, err := fileHash(path)
+		if err != nil {
+			log.Warn("failed read config file", slog.String("error", err.Error()))
+			continue
+		}
+		if bytes.Equal(sum, last) {
+			continue
+		}
+		last = sum
+
+		if err := onChange(); err != nil {
+			log.Error("failed reload config, keeping previous", slog.String("error", err.Error()))
+			continue
+		}
+		log.Info("config reloaded")
+	}
+}
+
+func fileHash(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
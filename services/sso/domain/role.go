@@ -0,0 +1,32 @@
+package domain
+
+import "fmt"
+
+// Role определяет права пользователя в системе.
+type Role string
+
+const (
+	RoleStudent Role = "student"
+	RoleTeacher Role = "teacher"
+	RoleAdmin   Role = "admin"
+)
+
+// Roles перечисляет все допустимые роли.
+var Roles = []Role{RoleStudent, RoleTeacher, RoleAdmin}
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleStudent, RoleTeacher, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// ParseRole разбирает роль из строки (CLI, конфигурация).
+func ParseRole(s string) (Role, error) {
+	r := Role(s)
+	if !r.Valid() {
+		return "", fmt.Errorf("unknown role %q, want one of %v", s, Roles)
+	}
+	return r, nil
+}
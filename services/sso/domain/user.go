@@ -9,6 +9,8 @@ type CreateUser struct {
 	Name     string
 	Surname  string
 	IsMale   bool
+	// Role — роль нового пользователя; пусто — роль по умолчанию
+	Role Role
 }
 
 type CreateUserRow struct {
@@ -25,10 +27,17 @@ type User struct {
 	Name      string
 	Surname   string
 	IsMale    bool
+	Role      Role
+	LockedAt  *time.Time
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// Locked сообщает, заблокирован ли пользователь администратором.
+func (u *User) Locked() bool {
+	return u.LockedAt != nil
+}
+
 func NewUser(id int64, username, email, password, name, surname string, isMale bool,
 	createdAt, updatedAt time.Time,
 ) User {
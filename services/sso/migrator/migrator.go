@@ -0,0 +1,184 @@
+// Package migrator applies the embedded SSO migrations with goose.
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Krokozabra213/schools_backend/sql/goose/sso/migrations"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// ErrNoMigrations — откатывать или повторять нечего.
+var ErrNoMigrations = errors.New("no migrations to apply")
+
+// ErrPoolTooSmall — пулу не хватает соединений, чтобы держать блокировку
+// и выполнять миграции одновременно (см. Redo).
+var ErrPoolTooSmall = errors.New("migration pool needs at least 2 connections")
+
+type Migrator struct {
+	pool     *pgxpool.Pool
+	db       *sql.DB
+	locker   lock.SessionLocker
+	provider *goose.Provider
+	// unlocked — те же миграции без своей блокировки: для команд из
+	// нескольких шагов, которые держат locker сами (см. Redo)
+	unlocked *goose.Provider
+}
+
+// New создаёт мигратор поверх пула pgx. Закрытие мигратора пул не закрывает.
+// Атрибут component добавляет вызывающий: logger.Component(log, "migrator").
+//
+// Все изменяющие команды выполняются под advisory lock Postgres: несколько
+// реплик, стартующих одновременно с автомиграцией, применят миграции
+// по очереди, а не наперегонки.
+func New(pool *pgxpool.Pool, log *slog.Logger) (*Migrator, error) {
+	if log == nil {
+		log = slog.Default()
+	}
+
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("create migration lock: %w", err)
+	}
+
+	db := stdlib.OpenDBFromPool(pool)
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations.Files,
+		goose.WithSessionLocker(locker),
+		goose.WithSlog(log),
+	)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create goose provider: %w", err)
+	}
+
+	unlocked, err := goose.NewProvider(goose.DialectPostgres, db, migrations.Files,
+		goose.WithSlog(log),
+	)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create goose provider: %w", err)
+	}
+
+	return &Migrator{
+		pool:     pool,
+		db:       db,
+		locker:   locker,
+		provider: provider,
+		unlocked: unlocked,
+	}, nil
+}
+
+// Up применяет все неприменённые миграции.
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		return results, fmt.Errorf("migrate up: %w", err)
+	}
+	return results, nil
+}
+
+// Down откатывает последнюю миграцию.
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	result, err := m.provider.Down(ctx)
+	if err != nil {
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			return nil, ErrNoMigrations
+		}
+		return result, fmt.Errorf("migrate down: %w", err)
+	}
+	return result, nil
+}
+
+// Redo откатывает и заново применяет последнюю миграцию. Оба шага идут
+// под одной блокировкой: другая реплика не вклинится между откатом и
+// повторным применением.
+func (m *Migrator) Redo(ctx context.Context) ([]*goose.MigrationResult, error) {
+	// с одним соединением шаги ждали бы занятое блокировкой соединение
+	// до таймаута ctx
+	if maxConns := m.pool.Config().MaxConns; maxConns < 2 {
+		return nil, fmt.Errorf("%w: postgres.maxConns is %d", ErrPoolTooSmall, maxConns)
+	}
+
+	var results []*goose.MigrationResult
+
+	err := m.withLock(ctx, func() error {
+		down, err := m.unlocked.Down(ctx)
+		if err != nil {
+			if errors.Is(err, goose.ErrNoNextVersion) {
+				return ErrNoMigrations
+			}
+			return fmt.Errorf("migrate down: %w", err)
+		}
+		results = append(results, down)
+
+		up, err := m.unlocked.UpByOne(ctx)
+		if err != nil {
+			return fmt.Errorf("migrate up by one: %w", err)
+		}
+		results = append(results, up)
+		return nil
+	})
+	return results, err
+}
+
+// withLock выполняет fn под той же advisory lock, что берёт goose. Шаги fn
+// идут через другие соединения пула, поэтому пулу нужно не меньше двух.
+func (m *Migrator) withLock(ctx context.Context, fn func() error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("get migration lock connection: %w", err)
+	}
+	defer conn.Close()
+
+	if err := m.locker.SessionLock(ctx, conn); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		// отпускаем и при отменённом ctx: иначе блокировка живёт, пока
+		// соединение в пуле
+		if unlockErr := m.locker.SessionUnlock(context.WithoutCancel(ctx), conn); unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("release migration lock: %w", unlockErr))
+		}
+	}()
+
+	return fn()
+}
+
+// Status возвращает состояние каждой миграции.
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	status, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migration status: %w", err)
+	}
+	return status, nil
+}
+
+// Version возвращает текущую версию схемы и последнюю доступную миграцию.
+func (m *Migrator) Version(ctx context.Context) (current, target int64, err error) {
+	current, target, err = m.provider.GetVersions(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("migration version: %w", err)
+	}
+	return current, target, nil
+}
+
+// HasPending сообщает, есть ли неприменённые миграции.
+func (m *Migrator) HasPending(ctx context.Context) (bool, error) {
+	pending, err := m.provider.HasPending(ctx)
+	if err != nil {
+		return false, fmt.Errorf("check pending migrations: %w", err)
+	}
+	return pending, nil
+}
+
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
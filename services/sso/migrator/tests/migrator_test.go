@@ -0,0 +1,205 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/Krokozabra213/schools_backend/services/sso/migrator"
+)
+
+var connStr string
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	container, err := postgres.Run(ctx,
+		"postgres:16-alpine",
+		postgres.WithDatabase("test_db"),
+		postgres.WithUsername("test"),
+		postgres.WithPassword("test"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second),
+		),
+	)
+	if err != nil {
+		fmt.Printf("failed to start container: %v\n", err)
+		os.Exit(1)
+	}
+
+	connStr, err = container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		fmt.Printf("failed to get connection string: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	container.Terminate(ctx)
+	os.Exit(code)
+}
+
+// newMigrator создаёт мигратор на собственном пуле, как у отдельной реплики.
+func newMigrator(t *testing.T) *migrator.Migrator {
+	t.Helper()
+
+	pool, err := pgxpool.New(context.Background(), connStr)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	mig, err := migrator.New(pool, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { mig.Close() })
+	return mig
+}
+
+// reset удаляет схему вместе с таблицей версий goose.
+func reset(t *testing.T) {
+	t.Helper()
+
+	pool, err := pgxpool.New(context.Background(), connStr)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	_, err = pool.Exec(context.Background(), "DROP SCHEMA public CASCADE; CREATE SCHEMA public")
+	require.NoError(t, err)
+}
+
+// parallel запускает fn на каждом миграторе одновременно.
+func parallel[T any](migs []*migrator.Migrator, fn func(*migrator.Migrator) (T, error)) ([]T, []error) {
+	results := make([]T, len(migs))
+	errs := make([]error, len(migs))
+
+	var wg sync.WaitGroup
+	for i, mig := range migs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = fn(mig)
+		}()
+	}
+	wg.Wait()
+	return results, errs
+}
+
+// Реплики, стартующие одновременно с автомиграцией, применяют каждую
+// миграцию ровно один раз.
+func TestConcurrentUp(t *testing.T) {
+	ctx := context.Background()
+	reset(t)
+
+	migs := []*migrator.Migrator{newMigrator(t), newMigrator(t)}
+	results, errs := parallel(migs, func(m *migrator.Migrator) ([]*goose.MigrationResult, error) {
+		return m.Up(ctx)
+	})
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	status, err := migs[0].Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(status), len(results[0])+len(results[1]),
+		"every migration must be applied exactly once")
+
+	for _, m := range migs {
+		pending, err := m.HasPending(ctx)
+		require.NoError(t, err)
+		assert.False(t, pending)
+	}
+}
+
+func TestRedo(t *testing.T) {
+	ctx := context.Background()
+	reset(t)
+
+	mig := newMigrator(t)
+	_, err := mig.Up(ctx)
+	require.NoError(t, err)
+	before, target, err := mig.Version(ctx)
+	require.NoError(t, err)
+
+	results, err := mig.Redo(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "down", results[0].Direction)
+	assert.Equal(t, "up", results[1].Direction)
+	assert.Equal(t, target, results[1].Source.Version)
+
+	after, _, err := mig.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+// Откат и повторное применение идут под одной блокировкой: параллельные
+// Redo не откатывают схему дважды подряд.
+func TestConcurrentRedo(t *testing.T) {
+	ctx := context.Background()
+	reset(t)
+
+	migs := []*migrator.Migrator{newMigrator(t), newMigrator(t)}
+	_, err := migs[0].Up(ctx)
+	require.NoError(t, err)
+	_, target, err := migs[0].Version(ctx)
+	require.NoError(t, err)
+
+	results, errs := parallel(migs, func(m *migrator.Migrator) ([]*goose.MigrationResult, error) {
+		return m.Redo(ctx)
+	})
+	for i, err := range errs {
+		require.NoError(t, err)
+		// каждый Redo откатил и применил именно последнюю миграцию
+		require.Len(t, results[i], 2)
+		assert.Equal(t, target, results[i][0].Source.Version)
+		assert.Equal(t, target, results[i][1].Source.Version)
+	}
+
+	current, _, err := migs[0].Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, target, current)
+}
+
+func TestDownWithoutMigrations(t *testing.T) {
+	ctx := context.Background()
+	reset(t)
+
+	mig := newMigrator(t)
+	_, err := mig.Down(ctx)
+	assert.ErrorIs(t, err, migrator.ErrNoMigrations)
+}
+
+// С одним соединением в пуле шаги Redo ждали бы соединение, занятое
+// блокировкой: Redo должен отказать сразу, а не висеть до таймаута.
+func TestRedoSingleConnPool(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	reset(t)
+
+	pool, err := pgxpool.New(ctx, connStr+"&pool_max_conns=1")
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	mig, err := migrator.New(pool, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { mig.Close() })
+
+	_, err = mig.Up(ctx)
+	require.NoError(t, err)
+
+	_, err = mig.Redo(ctx)
+	assert.ErrorIs(t, err, migrator.ErrPoolTooSmall)
+}
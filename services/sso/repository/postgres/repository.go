@@ -3,7 +3,9 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
 
+	pgxclient "github.com/Krokozabra213/schools_backend/internal/pkg/pgx-client"
 	"github.com/Krokozabra213/schools_backend/services/sso/domain"
 	"github.com/Krokozabra213/schools_backend/services/sso/repository/postgres/sqlc"
 	"github.com/jackc/pgx/v5"
@@ -18,21 +20,36 @@ type UserProvider interface {
 	SoftDeleteUser(ctx context.Context, id int64) error
 	HardDeleteUser(ctx context.Context, id int64) error
 	CountUsers(ctx context.Context) (int64, error)
+	ListUsers(ctx context.Context, limit, offset int32) ([]domain.User, error)
+	LockUser(ctx context.Context, id int64) error
+	UnlockUser(ctx context.Context, id int64) error
+	SetUserRole(ctx context.Context, id int64, role domain.Role) error
 	ExistsUserByUsername(ctx context.Context, username string) (bool, error)
 	ExistsUserByEmail(ctx context.Context, email string) (bool, error)
 }
 
 var _ UserProvider = (*PostgresRepository)(nil)
 
+// ReadRouter выбирает соединение для чтения (реплику); реализуется pgxclient.Client.
+type ReadRouter interface {
+	Reader(ctx context.Context) pgxclient.Reader
+}
+
 type PostgresRepository struct {
 	DB      sqlc.DBTX
 	Queries sqlc.Querier
+
+	router ReadRouter
 }
 
+// NewRepository создаёт репозиторий. Если db умеет выбирать реплики
+// (ReadRouter), чтения идут через него, запись — всегда в db.
 func NewRepository(db sqlc.DBTX) *PostgresRepository {
+	router, _ := db.(ReadRouter)
 	return &PostgresRepository{
 		DB:      db,
 		Queries: sqlc.New(db),
+		router:  router,
 	}
 }
 
@@ -43,6 +60,30 @@ func (r *PostgresRepository) WithTx(tx pgx.Tx) *PostgresRepository {
 	}
 }
 
+// db возвращает транзакцию из контекста, если она была начата TxManager.
+func (r *PostgresRepository) db(ctx context.Context) sqlc.DBTX {
+	if tx, ok := pgxclient.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.DB
+}
+
+func (r *PostgresRepository) queries(ctx context.Context) sqlc.Querier {
+	if tx, ok := pgxclient.TxFromContext(ctx); ok {
+		return sqlc.New(tx)
+	}
+	return r.Queries
+}
+
+// readQueries — запросы для чтения, которым допустимо небольшое отставание
+// реплики. Транзакцию и pgxclient.ContextWithPrimary учитывает ReadRouter.
+func (r *PostgresRepository) readQueries(ctx context.Context) sqlc.Querier {
+	if r.router == nil {
+		return r.queries(ctx)
+	}
+	return sqlc.New(r.router.Reader(ctx))
+}
+
 func (r *PostgresRepository) handleError(err error) error {
 	if err == nil {
 		return nil
@@ -53,5 +94,6 @@ func (r *PostgresRepository) handleError(err error) error {
 	if errors.Is(err, pgx.ErrNoRows) {
 		return ErrNotFound
 	}
-	return ErrInternal
+	// причину сохраняем, чтобы TxManager мог распознать 40001
+	return fmt.Errorf("%w: %w", ErrInternal, err)
 }
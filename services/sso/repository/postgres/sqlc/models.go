@@ -21,4 +21,6 @@ type User struct {
 	CreatedAt time.Time          `json:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at"`
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	Role      string             `json:"role"`
+	LockedAt  pgtype.Timestamptz `json:"locked_at"`
 }
@@ -17,7 +17,11 @@ type Querier interface {
 	GetUserByID(ctx context.Context, id int64) (GetUserByIDRow, error)
 	GetUserByUsername(ctx context.Context, username string) (GetUserByUsernameRow, error)
 	HardDeleteUser(ctx context.Context, id int64) error
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error)
+	LockUser(ctx context.Context, id int64) (int64, error)
+	SetUserRole(ctx context.Context, arg SetUserRoleParams) (int64, error)
 	SoftDeleteUser(ctx context.Context, id int64) error
+	UnlockUser(ctx context.Context, id int64) (int64, error)
 	UpdatePassword(ctx context.Context, arg UpdatePasswordParams) error
 }
 
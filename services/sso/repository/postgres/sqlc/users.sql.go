@@ -8,6 +8,8 @@ package sqlc
 import (
 	"context"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const countUsers = `-- name: CountUsers :one
@@ -104,6 +106,8 @@ SELECT
     name,
     surname,
     is_male,
+    role,
+    locked_at,
     created_at,
     updated_at
 FROM users
@@ -112,15 +116,17 @@ WHERE email = $1
 `
 
 type GetUserByEmailRow struct {
-	ID        int64     `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
-	Name      string    `json:"name"`
-	Surname   string    `json:"surname"`
-	IsMale    bool      `json:"is_male"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int64              `json:"id"`
+	Username  string             `json:"username"`
+	Email     string             `json:"email"`
+	Password  string             `json:"password"`
+	Name      string             `json:"name"`
+	Surname   string             `json:"surname"`
+	IsMale    bool               `json:"is_male"`
+	Role      string             `json:"role"`
+	LockedAt  pgtype.Timestamptz `json:"locked_at"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
 }
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error) {
@@ -134,6 +140,8 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEm
 		&i.Name,
 		&i.Surname,
 		&i.IsMale,
+		&i.Role,
+		&i.LockedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -149,6 +157,8 @@ SELECT
     name,
     surname,
     is_male,
+    role,
+    locked_at,
     created_at,
     updated_at
 FROM users
@@ -157,15 +167,17 @@ WHERE id = $1
 `
 
 type GetUserByIDRow struct {
-	ID        int64     `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
-	Name      string    `json:"name"`
-	Surname   string    `json:"surname"`
-	IsMale    bool      `json:"is_male"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int64              `json:"id"`
+	Username  string             `json:"username"`
+	Email     string             `json:"email"`
+	Password  string             `json:"password"`
+	Name      string             `json:"name"`
+	Surname   string             `json:"surname"`
+	IsMale    bool               `json:"is_male"`
+	Role      string             `json:"role"`
+	LockedAt  pgtype.Timestamptz `json:"locked_at"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
 }
 
 func (q *Queries) GetUserByID(ctx context.Context, id int64) (GetUserByIDRow, error) {
@@ -179,6 +191,8 @@ func (q *Queries) GetUserByID(ctx context.Context, id int64) (GetUserByIDRow, er
 		&i.Name,
 		&i.Surname,
 		&i.IsMale,
+		&i.Role,
+		&i.LockedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -194,6 +208,8 @@ SELECT
     name,
     surname,
     is_male,
+    role,
+    locked_at,
     created_at,
     updated_at
 FROM users
@@ -202,15 +218,17 @@ WHERE username = $1
 `
 
 type GetUserByUsernameRow struct {
-	ID        int64     `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
-	Name      string    `json:"name"`
-	Surname   string    `json:"surname"`
-	IsMale    bool      `json:"is_male"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int64              `json:"id"`
+	Username  string             `json:"username"`
+	Email     string             `json:"email"`
+	Password  string             `json:"password"`
+	Name      string             `json:"name"`
+	Surname   string             `json:"surname"`
+	IsMale    bool               `json:"is_male"`
+	Role      string             `json:"role"`
+	LockedAt  pgtype.Timestamptz `json:"locked_at"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
 }
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (GetUserByUsernameRow, error) {
@@ -224,6 +242,8 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (GetUs
 		&i.Name,
 		&i.Surname,
 		&i.IsMale,
+		&i.Role,
+		&i.LockedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -240,6 +260,112 @@ func (q *Queries) HardDeleteUser(ctx context.Context, id int64) error {
 	return err
 }
 
+const listUsers = `-- name: ListUsers :many
+SELECT
+    id,
+    username,
+    email,
+    name,
+    surname,
+    is_male,
+    role,
+    locked_at,
+    created_at,
+    updated_at
+FROM users
+WHERE deleted_at IS NULL
+ORDER BY id
+LIMIT $1 OFFSET $2
+`
+
+type ListUsersParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type ListUsersRow struct {
+	ID        int64              `json:"id"`
+	Username  string             `json:"username"`
+	Email     string             `json:"email"`
+	Name      string             `json:"name"`
+	Surname   string             `json:"surname"`
+	IsMale    bool               `json:"is_male"`
+	Role      string             `json:"role"`
+	LockedAt  pgtype.Timestamptz `json:"locked_at"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error) {
+	rows, err := q.db.Query(ctx, listUsers, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUsersRow{}
+	for rows.Next() {
+		var i ListUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Email,
+			&i.Name,
+			&i.Surname,
+			&i.IsMale,
+			&i.Role,
+			&i.LockedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUser = `-- name: LockUser :execrows
+UPDATE users
+SET
+    locked_at  = COALESCE(locked_at, NOW()),
+    updated_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL
+`
+
+func (q *Queries) LockUser(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, lockUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setUserRole = `-- name: SetUserRole :execrows
+UPDATE users
+SET
+    role       = $2,
+    updated_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL
+`
+
+type SetUserRoleParams struct {
+	ID   int64  `json:"id"`
+	Role string `json:"role"`
+}
+
+func (q *Queries) SetUserRole(ctx context.Context, arg SetUserRoleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setUserRole, arg.ID, arg.Role)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const softDeleteUser = `-- name: SoftDeleteUser :exec
 UPDATE users
 SET
@@ -254,6 +380,23 @@ func (q *Queries) SoftDeleteUser(ctx context.Context, id int64) error {
 	return err
 }
 
+const unlockUser = `-- name: UnlockUser :execrows
+UPDATE users
+SET
+    locked_at  = NULL,
+    updated_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL
+`
+
+func (q *Queries) UnlockUser(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, unlockUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updatePassword = `-- name: UpdatePassword :exec
 UPDATE users
 SET
@@ -245,3 +245,106 @@ func TestExistsUserByEmail(t *testing.T) {
 		assert.False(t, exists)
 	})
 }
+
+func TestLockUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lock and unlock", func(t *testing.T) {
+		cleanup(t)
+
+		created, _ := testRepo.CreateUser(ctx, &domain.CreateUser{
+			Username: "locked",
+			Email:    "locked@test.com",
+			Password: "password",
+			Name:     "John",
+			Surname:  "Doe",
+			IsMale:   true,
+		})
+
+		require.NoError(t, testRepo.LockUser(ctx, created.ID))
+
+		user, err := testRepo.GetUserByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.True(t, user.Locked())
+		lockedAt := *user.LockedAt
+
+		// повторная блокировка не сдвигает момент блокировки
+		require.NoError(t, testRepo.LockUser(ctx, created.ID))
+		user, _ = testRepo.GetUserByID(ctx, created.ID)
+		assert.Equal(t, lockedAt, *user.LockedAt)
+
+		require.NoError(t, testRepo.UnlockUser(ctx, created.ID))
+		user, _ = testRepo.GetUserByID(ctx, created.ID)
+		assert.False(t, user.Locked())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		cleanup(t)
+
+		err := testRepo.LockUser(ctx, 99999)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func TestSetUserRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		cleanup(t)
+
+		created, _ := testRepo.CreateUser(ctx, &domain.CreateUser{
+			Username: "teacher",
+			Email:    "teacher@test.com",
+			Password: "password",
+			Name:     "John",
+			Surname:  "Doe",
+			IsMale:   true,
+		})
+
+		user, _ := testRepo.GetUserByID(ctx, created.ID)
+		assert.Equal(t, domain.RoleStudent, user.Role)
+
+		require.NoError(t, testRepo.SetUserRole(ctx, created.ID, domain.RoleTeacher))
+
+		user, _ = testRepo.GetUserByID(ctx, created.ID)
+		assert.Equal(t, domain.RoleTeacher, user.Role)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		cleanup(t)
+
+		err := testRepo.SetUserRole(ctx, 99999, domain.RoleAdmin)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func TestListUsers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pagination", func(t *testing.T) {
+		cleanup(t)
+
+		for _, name := range []string{"first", "second", "third"} {
+			_, err := testRepo.CreateUser(ctx, &domain.CreateUser{
+				Username: name,
+				Email:    name + "@test.com",
+				Password: "password",
+				Name:     "John",
+				Surname:  "Doe",
+				IsMale:   true,
+			})
+			require.NoError(t, err)
+		}
+
+		page, err := testRepo.ListUsers(ctx, 2, 0)
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		assert.Equal(t, "first", page[0].Username)
+		assert.Empty(t, page[0].Password)
+
+		page, err = testRepo.ListUsers(ctx, 2, 2)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, "third", page[0].Username)
+	})
+}
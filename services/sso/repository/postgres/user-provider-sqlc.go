@@ -3,14 +3,16 @@ package postgres
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/Krokozabra213/schools_backend/services/sso/domain"
 	"github.com/Krokozabra213/schools_backend/services/sso/repository/postgres/sqlc"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func (r *PostgresRepository) CreateUser(ctx context.Context, user *domain.CreateUser) (*domain.CreateUserRow, error) {
-	result, err := r.Queries.CreateUser(ctx, sqlc.CreateUserParams{
+	result, err := r.queries(ctx).CreateUser(ctx, sqlc.CreateUserParams{
 		Username: user.Username,
 		Email:    user.Email,
 		Password: user.Password,
@@ -34,29 +36,33 @@ func (r *PostgresRepository) CreateUser(ctx context.Context, user *domain.Create
 }
 
 func (r *PostgresRepository) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
-	result, err := r.Queries.GetUserByUsername(ctx, username)
+	result, err := r.readQueries(ctx).GetUserByUsername(ctx, username)
 	if err != nil {
 		return nil, r.handleError(err)
 	}
 	user := domain.NewUser(result.ID, result.Username, result.Email, result.Password, result.Name,
 		result.Surname, result.IsMale, result.CreatedAt, result.UpdatedAt)
+	user.Role = domain.Role(result.Role)
+	user.LockedAt = timePtr(result.LockedAt)
 
 	return &user, nil
 }
 
 func (r *PostgresRepository) GetUserByID(ctx context.Context, id int64) (*domain.User, error) {
-	result, err := r.Queries.GetUserByID(ctx, id)
+	result, err := r.readQueries(ctx).GetUserByID(ctx, id)
 	if err != nil {
 		return nil, r.handleError(err)
 	}
 	user := domain.NewUser(result.ID, result.Username, result.Email, result.Password, result.Name,
 		result.Surname, result.IsMale, result.CreatedAt, result.UpdatedAt)
+	user.Role = domain.Role(result.Role)
+	user.LockedAt = timePtr(result.LockedAt)
 
 	return &user, nil
 }
 
 func (r *PostgresRepository) UpdatePassword(ctx context.Context, id int64, password string) error {
-	err := r.Queries.UpdatePassword(ctx, sqlc.UpdatePasswordParams{
+	err := r.queries(ctx).UpdatePassword(ctx, sqlc.UpdatePasswordParams{
 		ID:       id,
 		Password: password,
 	})
@@ -67,7 +73,7 @@ func (r *PostgresRepository) UpdatePassword(ctx context.Context, id int64, passw
 }
 
 func (r *PostgresRepository) SoftDeleteUser(ctx context.Context, id int64) error {
-	err := r.Queries.SoftDeleteUser(ctx, id)
+	err := r.queries(ctx).SoftDeleteUser(ctx, id)
 	if err != nil {
 		return r.handleError(err)
 	}
@@ -75,7 +81,7 @@ func (r *PostgresRepository) SoftDeleteUser(ctx context.Context, id int64) error
 }
 
 func (r *PostgresRepository) HardDeleteUser(ctx context.Context, id int64) error {
-	err := r.Queries.HardDeleteUser(ctx, id)
+	err := r.queries(ctx).HardDeleteUser(ctx, id)
 	if err != nil {
 		return r.handleError(err)
 	}
@@ -83,7 +89,7 @@ func (r *PostgresRepository) HardDeleteUser(ctx context.Context, id int64) error
 }
 
 func (r *PostgresRepository) CountUsers(ctx context.Context) (int64, error) {
-	count, err := r.Queries.CountUsers(ctx)
+	count, err := r.readQueries(ctx).CountUsers(ctx)
 	if err != nil {
 		return 0, r.handleError(err)
 	}
@@ -91,7 +97,7 @@ func (r *PostgresRepository) CountUsers(ctx context.Context) (int64, error) {
 }
 
 func (r *PostgresRepository) ExistsUserByUsername(ctx context.Context, username string) (bool, error) {
-	exists, err := r.Queries.ExistsUserByUsername(ctx, username)
+	exists, err := r.readQueries(ctx).ExistsUserByUsername(ctx, username)
 	if err != nil {
 		return false, r.handleError(err)
 	}
@@ -100,10 +106,70 @@ func (r *PostgresRepository) ExistsUserByUsername(ctx context.Context, username
 }
 
 func (r *PostgresRepository) ExistsUserByEmail(ctx context.Context, email string) (bool, error) {
-	exists, err := r.Queries.ExistsUserByEmail(ctx, email)
+	exists, err := r.readQueries(ctx).ExistsUserByEmail(ctx, email)
 	if err != nil {
 		return false, r.handleError(err)
 	}
 
 	return exists, nil
 }
+
+// ListUsers возвращает страницу активных пользователей по возрастанию id.
+// Пароль в выборку не попадает.
+func (r *PostgresRepository) ListUsers(ctx context.Context, limit, offset int32) ([]domain.User, error) {
+	rows, err := r.readQueries(ctx).ListUsers(ctx, sqlc.ListUsersParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+
+	users := make([]domain.User, 0, len(rows))
+	for _, row := range rows {
+		user := domain.NewUser(row.ID, row.Username, row.Email, "", row.Name,
+			row.Surname, row.IsMale, row.CreatedAt, row.UpdatedAt)
+		user.Role = domain.Role(row.Role)
+		user.LockedAt = timePtr(row.LockedAt)
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// LockUser блокирует пользователя. Повторная блокировка не сдвигает locked_at.
+func (r *PostgresRepository) LockUser(ctx context.Context, id int64) error {
+	affected, err := r.queries(ctx).LockUser(ctx, id)
+	return r.affectedOne(affected, err)
+}
+
+func (r *PostgresRepository) UnlockUser(ctx context.Context, id int64) error {
+	affected, err := r.queries(ctx).UnlockUser(ctx, id)
+	return r.affectedOne(affected, err)
+}
+
+func (r *PostgresRepository) SetUserRole(ctx context.Context, id int64, role domain.Role) error {
+	affected, err := r.queries(ctx).SetUserRole(ctx, sqlc.SetUserRoleParams{
+		ID:   id,
+		Role: string(role),
+	})
+	return r.affectedOne(affected, err)
+}
+
+// affectedOne превращает результат :execrows в ErrNotFound, если строка не найдена.
+func (r *PostgresRepository) affectedOne(affected int64, err error) error {
+	if err != nil {
+		return r.handleError(err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func timePtr(ts pgtype.Timestamptz) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	return &ts.Time
+}
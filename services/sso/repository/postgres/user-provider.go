@@ -57,7 +57,7 @@ func (r *PostgresRepository) UpdateUser(ctx context.Context, params domain.Updat
         WHERE id = $%d AND deleted_at IS NULL
     `, strings.Join(setParts, ", "), argIndex)
 
-	result, err := r.DB.Exec(ctx, query, args...)
+	result, err := r.db(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return r.handleError(err)
 	}
@@ -0,0 +1,287 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	lrucache "github.com/Krokozabra213/schools_backend/internal/pkg/lru-cache"
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	invalidateChannel = "sso:cache:invalidate"
+
+	invalidateProfile   = "profile"
+	invalidateToken     = "token"
+	invalidateWatermark = "watermark"
+
+	defaultLocalCapacity      = 10_000
+	defaultLocalProfileTTL    = 30 * time.Second
+	defaultLocalRevocationTTL = 5 * time.Second
+)
+
+type PubSubClient interface {
+	Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
+
+// LocalCacheConfig задаёт размеры и TTL локального кеша.
+// TTL ограничивает устаревание, если сообщение об инвалидации потерялось.
+type LocalCacheConfig struct {
+	Capacity      int
+	ProfileTTL    time.Duration
+	RevocationTTL time.Duration
+}
+
+func (c LocalCacheConfig) withDefaults() LocalCacheConfig {
+	if c.Capacity <= 0 {
+		c.Capacity = defaultLocalCapacity
+	}
+	if c.ProfileTTL <= 0 {
+		c.ProfileTTL = defaultLocalProfileTTL
+	}
+	if c.RevocationTTL <= 0 {
+		c.RevocationTTL = defaultLocalRevocationTTL
+	}
+	return c
+}
+
+// CachedRepository keeps hot profiles and revocation checks in process memory
+// in front of RedisRepository. Writes are broadcast to other instances via pub/sub.
+type CachedRepository struct {
+	*RedisRepository
+
+	pubsub     PubSubClient
+	profiles   *lrucache.Cache[int64, domain.UserCacheProfile]
+	revoked    *lrucache.Cache[string, bool]
+	watermarks *lrucache.Cache[int64, time.Time]
+}
+
+func NewCachedRepository(repo *RedisRepository, pubsub PubSubClient, cfg LocalCacheConfig) (*CachedRepository, error) {
+	cfg = cfg.withDefaults()
+
+	profiles, err := lrucache.New[int64, domain.UserCacheProfile](
+		lrucache.WithCapacity(cfg.Capacity),
+		lrucache.WithTTL(cfg.ProfileTTL),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create profile cache: %w", err)
+	}
+
+	revoked, err := lrucache.New[string, bool](
+		lrucache.WithCapacity(cfg.Capacity),
+		lrucache.WithTTL(cfg.RevocationTTL),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create revocation cache: %w", err)
+	}
+
+	watermarks, err := lrucache.New[int64, time.Time](
+		lrucache.WithCapacity(cfg.Capacity),
+		lrucache.WithTTL(cfg.RevocationTTL),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create watermark cache: %w", err)
+	}
+
+	return &CachedRepository{
+		RedisRepository: repo,
+		pubsub:          pubsub,
+		profiles:        profiles,
+		revoked:         revoked,
+		watermarks:      watermarks,
+	}, nil
+}
+
+var (
+	_ TokenProvider          = (*CachedRepository)(nil)
+	_ TokenWatermarkProvider = (*CachedRepository)(nil)
+)
+
+func (r *CachedRepository) GetUserProfile(ctx context.Context, userID int64) (*domain.UserCacheProfile, error) {
+	if profile, ok := r.profiles.Get(userID); ok {
+		return &profile, nil
+	}
+
+	profile, err := r.RedisRepository.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.profiles.Set(userID, *profile)
+	return profile, nil
+}
+
+func (r *CachedRepository) CacheUserProfile(ctx context.Context, profile *domain.UserCacheProfile, ttl time.Duration) error {
+	if err := r.RedisRepository.CacheUserProfile(ctx, profile, ttl); err != nil {
+		return err
+	}
+
+	r.profiles.Set(profile.ID, *profile)
+	return nil
+}
+
+func (r *CachedRepository) DeleteUserProfile(ctx context.Context, userID int64) error {
+	r.profiles.Delete(userID)
+
+	if err := r.RedisRepository.DeleteUserProfile(ctx, userID); err != nil {
+		return err
+	}
+
+	r.publish(ctx, invalidateProfile, strconv.FormatInt(userID, 10))
+	return nil
+}
+
+func (r *CachedRepository) RevokeRefreshToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	if err := r.RedisRepository.RevokeRefreshToken(ctx, jti, expiresAt); err != nil {
+		return err
+	}
+
+	// отозванный токен не может стать валидным, поэтому держим его до истечения
+	r.revoked.SetWithTTL(jti, true, time.Until(expiresAt))
+	r.publish(ctx, invalidateToken, jti)
+	return nil
+}
+
+func (r *CachedRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	if revoked, ok := r.revoked.Get(jti); ok {
+		return revoked, nil
+	}
+
+	revoked, err := r.RedisRepository.IsTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	// срок токена здесь неизвестен, поэтому оба ответа живут RevocationTTL;
+	// до истечения токена держится только отзыв, сделанный этим инстансом
+	r.revoked.Set(jti, revoked)
+	return revoked, nil
+}
+
+func (r *CachedRepository) BumpTokensValidAfter(ctx context.Context, userID int64, at time.Time, ttl time.Duration) error {
+	if err := r.RedisRepository.BumpTokensValidAfter(ctx, userID, at, ttl); err != nil {
+		return err
+	}
+
+	r.watermarks.Set(userID, at)
+	r.publish(ctx, invalidateWatermark, strconv.FormatInt(userID, 10))
+	return nil
+}
+
+// TokensValidAfter вызывается на каждый запрос, поэтому читается из локального кеша;
+// устаревание ограничено RevocationTTL.
+func (r *CachedRepository) TokensValidAfter(ctx context.Context, userID int64) (time.Time, error) {
+	if at, ok := r.watermarks.Get(userID); ok {
+		return at, nil
+	}
+
+	at, err := r.RedisRepository.TokensValidAfter(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	r.watermarks.Set(userID, at)
+	return at, nil
+}
+
+// ErrSubscriptionClosed — подписка на инвалидации закрылась до отмены ctx.
+var ErrSubscriptionClosed = errors.New("invalidation subscription closed")
+
+// Listen applies invalidations published by other instances until ctx is done.
+// It returns ErrSubscriptionClosed if the subscription ends earlier; the caller
+// is expected to call Listen again.
+func (r *CachedRepository) Listen(ctx context.Context) error {
+	const op = "repository.CachedRepository.Listen"
+	log := componentLog().With(slog.String("op", op))
+
+	sub := r.pubsub.Subscribe(ctx, invalidateChannel)
+	defer sub.Close()
+
+	// ждём подтверждения подписки, чтобы не потерять первые сообщения
+	// и сообщить вызывающему о недоступном Redis
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("subscribe %s: %w", invalidateChannel, err)
+	}
+	r.purge()
+
+	// go-redis сам переподключается после обрыва; подтверждения новой
+	// подписки приходят только в ChannelWithSubscriptions
+	ch := sub.ChannelWithSubscriptions()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return ErrSubscriptionClosed
+			}
+			switch msg := msg.(type) {
+			case *redis.Subscription:
+				if msg.Kind == "subscribe" {
+					log.Info("resubscribed to invalidations")
+					r.purge()
+				}
+			case *redis.Message:
+				if err := r.applyInvalidation(msg.Payload); err != nil {
+					log.Warn("bad invalidation message", "error", err, "payload", msg.Payload)
+				}
+			}
+		}
+	}
+}
+
+// purge сбрасывает локальные кеши: пока подписки не было,
+// инвалидации могли потеряться.
+func (r *CachedRepository) purge() {
+	r.profiles.Purge()
+	r.revoked.Purge()
+	r.watermarks.Purge()
+}
+
+func (r *CachedRepository) applyInvalidation(payload string) error {
+	kind, id, ok := strings.Cut(payload, ":")
+	if !ok {
+		return errors.New("missing separator")
+	}
+
+	switch kind {
+	case invalidateProfile:
+		userID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return fmt.Errorf("parse user id: %w", err)
+		}
+		r.profiles.Delete(userID)
+	case invalidateWatermark:
+		userID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return fmt.Errorf("parse user id: %w", err)
+		}
+		r.watermarks.Delete(userID)
+	case invalidateToken:
+		r.revoked.Delete(id)
+	default:
+		return fmt.Errorf("unknown kind %q", kind)
+	}
+
+	return nil
+}
+
+func (r *CachedRepository) publish(ctx context.Context, kind, id string) {
+	const op = "repository.CachedRepository.publish"
+
+	// ошибка не критична: остальные инстансы увидят изменение через TTL
+	if err := r.pubsub.Publish(ctx, invalidateChannel, kind+":"+id).Err(); err != nil {
+		componentLog().Warn("failed publish invalidation",
+			slog.String("op", op),
+			slog.String("kind", kind),
+			slog.String("error", err.Error()),
+		)
+	}
+}
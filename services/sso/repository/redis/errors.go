@@ -6,4 +6,7 @@ var (
 	ErrInternal     = errors.New("internal error")
 	ErrNotFound     = errors.New("not found error")
 	ErrTokenExpired = errors.New("token already expired")
+	// ErrProfileInvalidated — профиль недавно сброшен, и запись в кеш
+	// отклонена: она могла нести данные, прочитанные до изменения
+	ErrProfileInvalidated = errors.New("profile invalidated recently")
 )
@@ -2,11 +2,23 @@ package redis
 
 import (
 	"context"
+	"log/slog"
+	"strconv"
 	"time"
 
+	gorediscli "github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/logger"
 	"github.com/redis/go-redis/v9"
 )
 
+// component — имя пакета в логах: по нему можно включить отдельный
+// уровень, например debug только для repository.redis.
+const component = "repository.redis"
+
+func componentLog() *slog.Logger {
+	return logger.Component(slog.Default(), component)
+}
+
 type TokenProvider interface {
 	RevokeRefreshToken(ctx context.Context, jti string, expiresAt time.Time) error
 	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
@@ -19,6 +31,14 @@ type RedisClient interface {
 	Exists(ctx context.Context, keys ...string) *redis.IntCmd
 	Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
 	SetEx(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	redis.Scripter
+}
+
+// userKey — ключ данных пользователя: "user:{id}:name". Id в hash tag,
+// поэтому в Redis Cluster все ключи пользователя в одном слоте и их можно
+// менять одной транзакцией или скриптом.
+func userKey(userID int64, name string) string {
+	return "user:" + gorediscli.HashTag(strconv.FormatInt(userID, 10)) + ":" + name
 }
 
 type RedisRepository struct {
@@ -0,0 +1,180 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+	repository "github.com/Krokozabra213/schools_backend/services/sso/repository/redis"
+)
+
+const invalidateChannel = "sso:cache:invalidate"
+
+// newCachedRepo создаёт инстанс с локальным кешем. TTL большие: устаревшая
+// запись может исчезнуть только по сообщению об инвалидации.
+func newCachedRepo(t *testing.T) *repository.CachedRepository {
+	t.Helper()
+
+	repo, err := repository.NewCachedRepository(testRepo, testClient, repository.LocalCacheConfig{
+		ProfileTTL:    time.Hour,
+		RevocationTTL: time.Hour,
+	})
+	require.NoError(t, err)
+	return repo
+}
+
+// listen запускает Listen и ждёт подписки; возвращённая функция
+// останавливает его и ждёт отписки.
+func listen(t *testing.T, repo *repository.CachedRepository) (stop func()) {
+	t.Helper()
+
+	before := subscribers(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- repo.Listen(ctx) }()
+
+	require.Eventually(t, func() bool { return subscribers(t) == before+1 },
+		5*time.Second, 10*time.Millisecond, "listener did not subscribe")
+
+	var stopped bool
+	stop = func() {
+		if stopped {
+			return
+		}
+		stopped = true
+		cancel()
+		require.NoError(t, <-done)
+		require.Eventually(t, func() bool { return subscribers(t) == before },
+			5*time.Second, 10*time.Millisecond, "listener did not unsubscribe")
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+func subscribers(t *testing.T) int64 {
+	t.Helper()
+
+	counts, err := testClient.PubSubNumSub(context.Background(), invalidateChannel).Result()
+	require.NoError(t, err)
+	return counts[invalidateChannel]
+}
+
+func TestCachedRepositoryInvalidation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("profile", func(t *testing.T) {
+		cleanup(t)
+		a, b := newCachedRepo(t), newCachedRepo(t)
+		listen(t, b)
+
+		old := &domain.UserCacheProfile{ID: 1, Username: "old"}
+		require.NoError(t, a.CacheUserProfile(ctx, old, time.Minute))
+		got, err := b.GetUserProfile(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "old", got.Username)
+
+		// запись в бизнес-слое сбрасывает профиль; следующее чтение идёт в базу
+		require.NoError(t, a.DeleteUserProfile(ctx, 1))
+
+		assert.Eventually(t, func() bool {
+			_, err := b.GetUserProfile(ctx, 1)
+			return err == repository.ErrNotFound
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("revoked token", func(t *testing.T) {
+		cleanup(t)
+		a, b := newCachedRepo(t), newCachedRepo(t)
+		listen(t, b)
+
+		jti := uuid.New().String()
+		revoked, err := b.IsTokenRevoked(ctx, jti)
+		require.NoError(t, err)
+		require.False(t, revoked)
+
+		require.NoError(t, a.RevokeRefreshToken(ctx, jti, time.Now().Add(time.Minute)))
+
+		assert.Eventually(t, func() bool {
+			revoked, err := b.IsTokenRevoked(ctx, jti)
+			return err == nil && revoked
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("watermark", func(t *testing.T) {
+		cleanup(t)
+		a, b := newCachedRepo(t), newCachedRepo(t)
+		listen(t, b)
+
+		got, err := b.TokensValidAfter(ctx, 1)
+		require.NoError(t, err)
+		require.True(t, got.IsZero())
+
+		at := time.Unix(time.Now().Unix(), 0)
+		require.NoError(t, a.BumpTokensValidAfter(ctx, 1, at, time.Minute))
+
+		assert.Eventually(t, func() bool {
+			got, err := b.TokensValidAfter(ctx, 1)
+			return err == nil && got.Equal(at)
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("bad message is skipped", func(t *testing.T) {
+		cleanup(t)
+		a, b := newCachedRepo(t), newCachedRepo(t)
+		listen(t, b)
+
+		require.NoError(t, a.CacheUserProfile(ctx, &domain.UserCacheProfile{ID: 1, Username: "old"}, time.Minute))
+		_, err := b.GetUserProfile(ctx, 1)
+		require.NoError(t, err)
+
+		require.NoError(t, testClient.Publish(ctx, invalidateChannel, "garbage").Err())
+		require.NoError(t, a.DeleteUserProfile(ctx, 1))
+
+		assert.Eventually(t, func() bool {
+			_, err := b.GetUserProfile(ctx, 1)
+			return err == repository.ErrNotFound
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+// После обрыва соединения go-redis подписывается заново сам; сообщения
+// из разрыва теряются, поэтому на повторную подписку локальный кеш
+// сбрасывается целиком, а Listen продолжает работать.
+func TestCachedRepositoryListenPurgesAfterReconnect(t *testing.T) {
+	ctx := context.Background()
+	cleanup(t)
+
+	a, b := newCachedRepo(t), newCachedRepo(t)
+	listen(t, b)
+
+	require.NoError(t, a.CacheUserProfile(ctx, &domain.UserCacheProfile{ID: 1, Username: "old"}, time.Minute))
+	_, err := b.GetUserProfile(ctx, 1)
+	require.NoError(t, err)
+
+	// изменение мимо pub/sub — как сообщение, потерянное в разрыве
+	require.NoError(t, testRepo.CacheUserProfile(ctx, &domain.UserCacheProfile{ID: 1, Username: "new"}, time.Minute))
+	got, err := b.GetUserProfile(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "old", got.Username, "stale entry expected before reconnect")
+
+	require.NoError(t, testClient.Do(ctx, "CLIENT", "KILL", "TYPE", "pubsub").Err())
+
+	assert.Eventually(t, func() bool {
+		got, err := b.GetUserProfile(ctx, 1)
+		return err == nil && got.Username == "new"
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// подписка восстановлена: инвалидации снова доходят
+	require.NoError(t, a.DeleteUserProfile(ctx, 1))
+	assert.Eventually(t, func() bool {
+		_, err := b.GetUserProfile(ctx, 1)
+		return err == repository.ErrNotFound
+	}, 5*time.Second, 10*time.Millisecond)
+}
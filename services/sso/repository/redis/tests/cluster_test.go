@@ -0,0 +1,65 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gorediscli "github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client"
+	"github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client/redistest"
+	"github.com/Krokozabra213/schools_backend/services/sso/domain"
+	repository "github.com/Krokozabra213/schools_backend/services/sso/repository/redis"
+)
+
+func TestRepositoryInCluster(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := gorediscli.New(ctx,
+		gorediscli.WithCluster(redistest.StartCluster(t)),
+		gorediscli.WithPassword(redistest.Password),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	repo := repository.NewRepository(client)
+
+	jti := uuid.New().String()
+	require.NoError(t, repo.RevokeRefreshToken(ctx, jti, time.Now().Add(time.Minute)))
+	revoked, err := repo.IsTokenRevoked(ctx, jti)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	at := time.Unix(time.Now().Unix(), 0)
+	require.NoError(t, repo.BumpTokensValidAfter(ctx, 42, at, time.Minute))
+	got, err := repo.TokensValidAfter(ctx, 42)
+	require.NoError(t, err)
+	assert.True(t, got.Equal(at))
+
+	profile := &domain.UserCacheProfile{ID: 42, Username: "testuser"}
+	require.NoError(t, repo.CacheUserProfile(ctx, profile, time.Minute))
+
+	// ключи пользователя в одном слоте: их можно читать одной командой
+	values, err := client.MGet(ctx, "user:{42}:profile", "user:{42}:tokens:not_before").Result()
+	require.NoError(t, err)
+	assert.NotNil(t, values[0])
+	assert.NotNil(t, values[1])
+}
+
+// Отзывы, записанные до перехода на hash tag, действуют, пока не истекут.
+func TestLegacyKeys(t *testing.T) {
+	ctx := context.Background()
+	cleanup(t)
+
+	jti := uuid.New().String()
+	require.NoError(t, testClient.Set(ctx, "token:revoked::"+jti, "", time.Minute).Err())
+
+	revoked, err := testRepo.IsTokenRevoked(ctx, jti)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
@@ -14,12 +14,12 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/redis"
 	"github.com/testcontainers/testcontainers-go/wait"
 
-	redisclient "github.com/Krokozabra213/schools_backend/pkg/redis-client"
+	gorediscli "github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client"
 	repository "github.com/Krokozabra213/schools_backend/services/sso/repository/redis"
 )
 
 var (
-	testClient *redisclient.RedisClient
+	testClient *gorediscli.Client
 	testRepo   *repository.RedisRepository
 )
 
@@ -49,20 +49,10 @@ func TestMain(m *testing.M) {
 	addr := strings.TrimPrefix(connStr, "redis://")
 
 	// Создаём клиент
-	cfg := redisclient.NewConfig(
-		addr,           // addr (формат host:port)
-		"",             // password
-		0,              // db
-		5*time.Second,  // dial timeout
-		3*time.Second,  // read timeout
-		3*time.Second,  // write timeout
-		1*time.Hour,    // max conn lifetime
-		30*time.Minute, // max conn idle time
-		10,             // pool size
-		2,              // min idle conns
+	testClient, err = gorediscli.New(ctx,
+		gorediscli.WithAddr(addr),
+		gorediscli.WithPassword(""),
 	)
-
-	testClient, err = redisclient.New(ctx, cfg)
 	if err != nil {
 		fmt.Printf("failed to connect: %v\n", err)
 		os.Exit(1)
@@ -72,7 +62,7 @@ func TestMain(m *testing.M) {
 
 	code := m.Run()
 
-	testClient.CloseConn()
+	testClient.Close()
 	container.Terminate(ctx)
 	os.Exit(code)
 }
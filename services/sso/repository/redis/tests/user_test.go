@@ -157,6 +157,21 @@ func TestDeleteUserProfile(t *testing.T) {
 
 		assert.NoError(t, err)
 	})
+
+	t.Run("blocks stale write back", func(t *testing.T) {
+		cleanup(t)
+
+		require.NoError(t, testRepo.DeleteUserProfile(ctx, 123))
+
+		// загрузка, прочитавшая строку до удаления, не возвращает её в кеш
+		err := testRepo.CacheUserProfile(ctx, &domain.UserCacheProfile{ID: 123, Username: "stale"}, time.Minute)
+		assert.ErrorIs(t, err, repository.ErrProfileInvalidated)
+		_, err = testRepo.GetUserProfile(ctx, 123)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		// другие пользователи не затронуты
+		require.NoError(t, testRepo.CacheUserProfile(ctx, &domain.UserCacheProfile{ID: 124}, time.Minute))
+	})
 }
 
 func TestUpdateUserProfileTTL(t *testing.T) {
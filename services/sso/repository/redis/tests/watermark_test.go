@@ -0,0 +1,84 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	repository "github.com/Krokozabra213/schools_backend/services/sso/repository/redis"
+)
+
+func TestBumpTokensValidAfter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		cleanup(t)
+
+		at := time.Now()
+		require.NoError(t, testRepo.BumpTokensValidAfter(ctx, 1, at, time.Minute))
+
+		got, err := testRepo.TokensValidAfter(ctx, 1)
+		require.NoError(t, err)
+		// водяной знак хранится с секундной точностью, как iat
+		assert.True(t, got.Equal(at.Truncate(time.Second)), "got %v, want %v", got, at)
+	})
+
+	t.Run("overwrites previous watermark", func(t *testing.T) {
+		cleanup(t)
+
+		at := time.Unix(time.Now().Unix(), 0)
+		require.NoError(t, testRepo.BumpTokensValidAfter(ctx, 1, at, time.Minute))
+		require.NoError(t, testRepo.BumpTokensValidAfter(ctx, 1, at.Add(time.Hour), time.Minute))
+
+		got, err := testRepo.TokensValidAfter(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, got.Equal(at.Add(time.Hour)))
+	})
+
+	t.Run("per user", func(t *testing.T) {
+		cleanup(t)
+
+		require.NoError(t, testRepo.BumpTokensValidAfter(ctx, 1, time.Now(), time.Minute))
+
+		got, err := testRepo.TokensValidAfter(ctx, 2)
+		require.NoError(t, err)
+		assert.True(t, got.IsZero())
+	})
+
+	t.Run("expires after ttl", func(t *testing.T) {
+		cleanup(t)
+
+		require.NoError(t, testRepo.BumpTokensValidAfter(ctx, 1, time.Now(), time.Second))
+		time.Sleep(2 * time.Second)
+
+		got, err := testRepo.TokensValidAfter(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, got.IsZero())
+	})
+}
+
+func TestTokensValidAfter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no watermark", func(t *testing.T) {
+		cleanup(t)
+
+		got, err := testRepo.TokensValidAfter(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, got.IsZero())
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		cleanup(t)
+
+		require.NoError(t, testClient.Set(ctx, "user:{1}:tokens:not_before", "yesterday", time.Minute).Err())
+
+		_, err := testRepo.TokensValidAfter(ctx, 1)
+		assert.ErrorIs(t, err, repository.ErrInternal)
+	})
+}
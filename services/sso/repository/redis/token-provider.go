@@ -5,14 +5,16 @@ import (
 	"fmt"
 	"log/slog"
 	"time"
+
+	gorediscli "github.com/Krokozabra213/schools_backend/internal/pkg/go-redis-client"
 )
 
-const tokenPrefix = "token:revoked:"
+const tokenPrefix = "token:revoked"
 
 // RevokeRefreshToken сохраняет refresh токен по JTI
 func (r *RedisRepository) RevokeRefreshToken(ctx context.Context, jti string, expiresAt time.Time) error {
 	const op = "repository.RevokeRefreshToken"
-	log := slog.With(
+	log := componentLog().With(
 		slog.String("op", op),
 		slog.String("jti", jti),
 	)
@@ -35,23 +37,38 @@ func (r *RedisRepository) RevokeRefreshToken(ctx context.Context, jti string, ex
 // IsTokenRevoked проверяет существование токена
 func (r *RedisRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
 	const op = "repository.IsTokenRevoked"
-	log := slog.With(
+	log := componentLog().With(
 		slog.String("op", op),
 		slog.String("jti", jti),
 	)
 
-	key := r.refreshTokenKey(jti)
-
-	exists, err := r.client.Exists(ctx, key).Result()
-	if err != nil {
-		log.Error("failed check token", "error", err)
-		return false, ErrInternal
+	// ключи по одному: в Redis Cluster они в разных слотах.
+	// TODO(2026-12-01): читать только refreshTokenKey и удалить
+	// legacyRefreshTokenKey — к этой дате истекут все refresh токены
+	// (jwt.refreshTokenTTL 720h), отозванные до перехода на hash tag.
+	for _, key := range []string{r.refreshTokenKey(jti), legacyRefreshTokenKey(jti)} {
+		exists, err := r.client.Exists(ctx, key).Result()
+		if err != nil {
+			log.Error("failed check token", "error", err)
+			return false, ErrInternal
+		}
+		if exists == 1 {
+			return true, nil
+		}
 	}
 
-	return exists == 1, nil
+	return false, nil
 }
 
-// refreshTokenKey генерирует ключ для refresh токена
+// refreshTokenKey генерирует ключ для refresh токена; JTI в hash tag,
+// чтобы ключи одного токена можно было менять вместе в Redis Cluster.
 func (r *RedisRepository) refreshTokenKey(jti string) string {
-	return fmt.Sprintf("%s:%s", tokenPrefix, jti)
+	return fmt.Sprintf("%s:%s", tokenPrefix, gorediscli.HashTag(jti))
+}
+
+// legacyRefreshTokenKey — ключ до перехода на hash tag. Отозванные до
+// обновления токены хранятся под ним до истечения, поэтому он читается,
+// пока не пройдёт время жизни refresh токена; новые отзывы сюда не пишутся.
+func legacyRefreshTokenKey(jti string) string {
+	return fmt.Sprintf("%s::%s", tokenPrefix, jti)
 }
@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type TokenWatermarkProvider interface {
+	BumpTokensValidAfter(ctx context.Context, userID int64, at time.Time, ttl time.Duration) error
+	TokensValidAfter(ctx context.Context, userID int64) (time.Time, error)
+}
+
+var _ TokenWatermarkProvider = (*RedisRepository)(nil)
+
+// BumpTokensValidAfter отзывает все токены пользователя, выданные до at.
+// ttl должен быть не меньше времени жизни самого долгого токена.
+func (r *RedisRepository) BumpTokensValidAfter(ctx context.Context, userID int64, at time.Time, ttl time.Duration) error {
+	const op = "repository.BumpTokensValidAfter"
+	log := componentLog().With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	key := r.tokenNotBeforeKey(userID)
+
+	if err := r.client.Set(ctx, key, at.Unix(), ttl).Err(); err != nil {
+		log.Error("failed set watermark", "error", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// TokensValidAfter возвращает водяной знак пользователя или нулевое время.
+func (r *RedisRepository) TokensValidAfter(ctx context.Context, userID int64) (time.Time, error) {
+	const op = "repository.TokensValidAfter"
+	log := componentLog().With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	value, err := r.client.Get(ctx, r.tokenNotBeforeKey(userID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, nil
+		}
+		log.Error("failed get watermark", "error", err)
+		return time.Time{}, ErrInternal
+	}
+
+	unix, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Error("failed parse watermark", "error", err)
+		return time.Time{}, ErrInternal
+	}
+
+	return time.Unix(unix, 0), nil
+}
+
+func (r *RedisRepository) tokenNotBeforeKey(userID int64) string {
+	return userKey(userID, "tokens:not_before")
+}
@@ -3,7 +3,6 @@ package redis
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log/slog"
 	"time"
 
@@ -11,27 +10,53 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-const userProfilePrefix = "user:profile:"
-
+// profileTombstoneTTL — сколько после DeleteUserProfile профиль нельзя
+// положить в кеш. Загрузка, прочитавшая строку до изменения, иначе вернула
+// бы старый профиль на весь TTL; срок больше таймаута загрузки профиля
+// в бизнес-слое (5s).
+const profileTombstoneTTL = 10 * time.Second
+
+// cacheProfileScript пишет профиль, только если нет надгробия.
+var cacheProfileScript = redis.NewScript(`
+	if redis.call('EXISTS', KEYS[2]) == 1 then
+		return 0
+	end
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return 1
+`)
+
+// deleteProfileScript удаляет профиль и ставит надгробие.
+var deleteProfileScript = redis.NewScript(`
+	redis.call('DEL', KEYS[1])
+	redis.call('SET', KEYS[2], '1', 'PX', ARGV[1])
+	return 1
+`)
+
+// CacheUserProfile кеширует профиль; ErrProfileInvalidated — профиль
+// сброшен меньше profileTombstoneTTL назад и не записан.
 func (r *RedisRepository) CacheUserProfile(ctx context.Context, profile *domain.UserCacheProfile, ttl time.Duration) error {
 	const op = "repository.CacheUserProfile"
-	log := slog.With(
+	log := componentLog().With(
 		slog.String("op", op),
 		slog.Int64("user_id", profile.ID),
 	)
 
-	key := r.userProfileKey(profile.ID)
-
 	data, err := json.Marshal(profile)
 	if err != nil {
 		log.Error("failed marshal profile", "error", err)
 		return ErrInternal
 	}
 
-	if err := r.client.Set(ctx, key, data, ttl).Err(); err != nil {
+	// ключи в одном слоте (hash tag пользователя)
+	keys := []string{r.userProfileKey(profile.ID), r.userProfileTombstoneKey(profile.ID)}
+	written, err := cacheProfileScript.Run(ctx, r.client, keys, data, ttl.Milliseconds()).Int()
+	if err != nil {
 		log.Error("failed set profile", "error", err)
 		return ErrInternal
 	}
+	if written == 0 {
+		return ErrProfileInvalidated
+	}
 
 	return nil
 }
@@ -39,7 +64,7 @@ func (r *RedisRepository) CacheUserProfile(ctx context.Context, profile *domain.
 // GetUserProfile получает профиль из кеша
 func (r *RedisRepository) GetUserProfile(ctx context.Context, userID int64) (*domain.UserCacheProfile, error) {
 	const op = "repository.GetUserProfile"
-	log := slog.With(
+	log := componentLog().With(
 		slog.String("op", op),
 		slog.Int64("user_id", userID),
 	)
@@ -67,7 +92,7 @@ func (r *RedisRepository) GetUserProfile(ctx context.Context, userID int64) (*do
 // UpdateUserProfileTTL обновляет TTL профиля
 func (r *RedisRepository) UpdateUserProfileTTL(ctx context.Context, userID int64, ttl time.Duration) error {
 	const op = "repository.UpdateUserProfileTTL"
-	log := slog.With(
+	log := componentLog().With(
 		slog.String("op", op),
 		slog.Int64("user_id", userID),
 	)
@@ -84,14 +109,13 @@ func (r *RedisRepository) UpdateUserProfileTTL(ctx context.Context, userID int64
 
 func (r *RedisRepository) DeleteUserProfile(ctx context.Context, userID int64) error {
 	const op = "repository.DeleteUserProfile"
-	log := slog.With(
+	log := componentLog().With(
 		slog.String("op", op),
 		slog.Int64("user_id", userID),
 	)
 
-	key := r.userProfileKey(userID)
-
-	if err := r.client.Del(ctx, key).Err(); err != nil {
+	keys := []string{r.userProfileKey(userID), r.userProfileTombstoneKey(userID)}
+	if err := deleteProfileScript.Run(ctx, r.client, keys, profileTombstoneTTL.Milliseconds()).Err(); err != nil {
 		log.Error("failed to delete profile", "error", err)
 		return ErrInternal
 	}
@@ -100,5 +124,9 @@ func (r *RedisRepository) DeleteUserProfile(ctx context.Context, userID int64) e
 }
 
 func (r *RedisRepository) userProfileKey(userID int64) string {
-	return fmt.Sprintf("%s:%d", userProfilePrefix, userID)
+	return userKey(userID, "profile")
+}
+
+func (r *RedisRepository) userProfileTombstoneKey(userID int64) string {
+	return userKey(userID, "profile:tombstone")
 }
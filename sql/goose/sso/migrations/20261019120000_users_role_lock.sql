@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN role      VARCHAR(32) NOT NULL DEFAULT 'student',
+    ADD COLUMN locked_at TIMESTAMPTZ;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('student', 'teacher', 'admin'));
+
+-- +goose Down
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_role_check,
+    DROP COLUMN IF EXISTS locked_at,
+    DROP COLUMN IF EXISTS role;
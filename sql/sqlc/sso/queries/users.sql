@@ -20,6 +20,8 @@ SELECT
     name,
     surname,
     is_male,
+    role,
+    locked_at,
     created_at,
     updated_at
 FROM users
@@ -35,6 +37,8 @@ SELECT
     name,
     surname,
     is_male,
+    role,
+    locked_at,
     created_at,
     updated_at
 FROM users
@@ -50,6 +54,8 @@ SELECT
     name,
     surname,
     is_male,
+    role,
+    locked_at,
     created_at,
     updated_at
 FROM users
@@ -93,3 +99,44 @@ SELECT EXISTS(
     WHERE email = $1
       AND deleted_at IS NULL
 );
+
+-- name: ListUsers :many
+SELECT
+    id,
+    username,
+    email,
+    name,
+    surname,
+    is_male,
+    role,
+    locked_at,
+    created_at,
+    updated_at
+FROM users
+WHERE deleted_at IS NULL
+ORDER BY id
+LIMIT $1 OFFSET $2;
+
+-- name: LockUser :execrows
+UPDATE users
+SET
+    locked_at  = COALESCE(locked_at, NOW()),
+    updated_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL;
+
+-- name: UnlockUser :execrows
+UPDATE users
+SET
+    locked_at  = NULL,
+    updated_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL;
+
+-- name: SetUserRole :execrows
+UPDATE users
+SET
+    role       = $2,
+    updated_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL;